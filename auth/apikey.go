@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"ai-catalog/model"
+	"ai-catalog/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// API key scopes
+const (
+	ScopeCatalogRead  = "catalog:read"
+	ScopeCatalogWrite = "catalog:write"
+	ScopeOrdersRead   = "orders:read"
+	ScopeOrdersWrite  = "orders:write"
+	ScopeUsersRead    = "users:read"
+	ScopeUsersWrite   = "users:write"
+)
+
+// Scopes lists every scope an API key can be granted
+var Scopes = []string{
+	ScopeCatalogRead, ScopeCatalogWrite,
+	ScopeOrdersRead, ScopeOrdersWrite,
+	ScopeUsersRead, ScopeUsersWrite,
+}
+
+// apiKeyPrefix marks Fintks API keys so they are easy to recognise in leaked secrets
+const apiKeyPrefix = "fks_"
+
+// apiKeyLastUsedInterval limits how often last_used_at is written for a busy key
+const apiKeyLastUsedInterval = time.Minute
+
+// ErrInvalidAPIKey is returned when an API key is unknown, expired or revoked
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// APIKey is a named, scoped credential for server-to-server access
+type APIKey = model.APIKey
+
+// IsValidScope reports whether scope is a known API key scope
+func IsValidScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAPIKey issues a new API key for a user. The secret is only returned here;
+// the database keeps its hash and a short prefix to tell keys apart.
+func CreateAPIKey(ctx context.Context, userID int, name string, scopes []string, ttl time.Duration) (*APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", errors.New("API key name is required")
+	}
+	if len(scopes) == 0 {
+		return nil, "", errors.New("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if !IsValidScope(scope) {
+			return nil, "", fmt.Errorf("invalid scope: %s", scope)
+		}
+	}
+
+	secret, err := randomToken(32)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate API key: %v", err)
+	}
+	rawKey := apiKeyPrefix + secret
+	prefix := rawKey[:len(apiKeyPrefix)+8]
+
+	var expiresAt *time.Time
+	if ttl > 0 {
+		t := time.Now().Add(ttl)
+		expiresAt = &t
+	}
+
+	key, err := Repos.APIKeys.Create(ctx, repository.NewAPIKey{
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   HashToken(rawKey),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return key, rawKey, nil
+}
+
+// AuthenticateAPIKey returns the active key matching a raw API key and records its use
+func AuthenticateAPIKey(ctx context.Context, rawKey string) (*APIKey, error) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := Repos.APIKeys.Authenticate(ctx, HashToken(rawKey), apiKeyLastUsedInterval)
+	if err == repository.ErrAPIKeyNotFound {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// ListAPIKeys returns a user's API keys, newest first, including revoked ones
+func ListAPIKeys(ctx context.Context, userID int) ([]*APIKey, error) {
+	return Repos.APIKeys.ListForUser(ctx, userID)
+}
+
+// RevokeAPIKey revokes a key. A userID of 0 revokes the key whoever owns it.
+func RevokeAPIKey(ctx context.Context, id, userID int) (bool, error) {
+	return Repos.APIKeys.Revoke(ctx, id, userID)
+}
@@ -1,97 +1,155 @@
-package auth
-
-import (
-	"errors"
-	"time"
-
-	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
-)
-
-var jwtSecret = []byte("your-secret-key") // In production, use environment variable
-
-// User represents a basic user for authentication
-type User struct {
-	ID    int    `json:"id"`
-	Email string `json:"email"`
-}
-
-// Claims represents JWT claims
-type Claims struct {
-	UserID int    `json:"user_id"`
-	Email  string `json:"email"`
-	jwt.RegisteredClaims
-}
-
-// HashPassword hashes a password using bcrypt
-func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	return string(bytes), err
-}
-
-// CheckPassword checks if a password matches its hash
-func CheckPassword(password, hash string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
-	return err == nil
-}
-
-// GenerateToken generates a JWT token for a user
-func GenerateToken(user *User) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
-	claims := &Claims{
-		UserID: user.ID,
-		Email:  user.Email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Issuer:    "fintks-store",
-		},
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
-}
-
-// ValidateToken validates a JWT token and returns the claims
-func ValidateToken(tokenString string) (*Claims, error) {
-	claims := &Claims{}
-
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	if !token.Valid {
-		return nil, errors.New("invalid token")
-	}
-
-	return claims, nil
-}
-
-// GetUserFromToken extracts user information from a JWT token
-func GetUserFromToken(tokenString string) (*User, error) {
-	claims, err := ValidateToken(tokenString)
-	if err != nil {
-		return nil, err
-	}
-
-	// In a real application, you would fetch the user from the database
-	// For now, we'll return a basic user object
-	user := &User{
-		ID:    claims.UserID,
-		Email: claims.Email,
-	}
-
-	return user, nil
-}
-
-// ExtractTokenFromHeader extracts the token from Authorization header
-func ExtractTokenFromHeader(authHeader string) (string, error) {
-	if len(authHeader) < 7 || authHeader[:7] != "Bearer " {
-		return "", errors.New("invalid authorization header")
-	}
-	return authHeader[7:], nil
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// User roles
+const (
+	RoleCustomer = "customer"
+	RoleAdmin    = "admin"
+)
+
+// User represents a basic user for authentication
+type User struct {
+	ID    int    `json:"id"`
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+// Claims represents JWT claims
+type Claims struct {
+	UserID int    `json:"user_id"`
+	Email  string `json:"email"`
+	Role   string `json:"role"`
+	// SessionID ties the access token to a revocable server-side session
+	SessionID string `json:"sid"`
+	// Purpose is set on special-purpose tokens, which are never accepted as access tokens
+	Purpose string `json:"purpose,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Account statuses
+const (
+	StatusActive    = "active"
+	StatusSuspended = "suspended"
+	StatusDeleted   = "deleted"
+)
+
+// IsValidRole reports whether role is one of the known user roles
+func IsValidRole(role string) bool {
+	return role == RoleCustomer || role == RoleAdmin
+}
+
+// IsValidStatus reports whether status is one of the known account statuses
+func IsValidStatus(status string) bool {
+	return status == StatusActive || status == StatusSuspended || status == StatusDeleted
+}
+
+// HashPassword hashes a password using bcrypt
+func HashPassword(password string) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(bytes), err
+}
+
+// MinPasswordLength is the minimum number of characters accepted for new passwords
+const MinPasswordLength = 8
+
+// ValidatePassword checks that a new password meets the minimum requirements
+func ValidatePassword(password string) error {
+	if len([]rune(password)) < MinPasswordLength {
+		return fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	}
+	return nil
+}
+
+// CheckPassword checks if a password matches its hash
+func CheckPassword(password, hash string) bool {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	return err == nil
+}
+
+// GenerateToken generates a short-lived JWT access token for a user session
+func GenerateToken(user *User, sessionID string) (string, error) {
+	expirationTime := time.Now().Add(AccessTokenTTL())
+	claims := &Claims{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    "fintks-store",
+		},
+	}
+
+	return signToken(claims)
+}
+
+// ValidateToken validates a JWT token and returns the claims
+func ValidateToken(ctx context.Context, tokenString string) (*Claims, error) {
+	claims := &Claims{}
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, verificationKey,
+		jwt.WithValidMethods([]string{AlgHS256, AlgRS256, AlgEdDSA}))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	if claims.Purpose != "" {
+		return nil, errors.New("token is not an access token")
+	}
+
+	if claims.SessionID == "" {
+		return nil, errors.New("token is not bound to a session")
+	}
+
+	if Repos != nil {
+		active, err := IsSessionActive(ctx, claims.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		if !active {
+			return nil, ErrSessionRevoked
+		}
+	}
+
+	return claims, nil
+}
+
+// GetUserFromToken extracts the identity carried by a JWT token. It does not
+// consult the users table; AuthMiddleware loads the full record separately.
+func GetUserFromToken(ctx context.Context, tokenString string) (*User, error) {
+	claims, err := ValidateToken(ctx, tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &User{
+		ID:    claims.UserID,
+		Email: claims.Email,
+		Role:  claims.Role,
+	}
+
+	return user, nil
+}
+
+// ExtractTokenFromHeader extracts the token from Authorization header
+func ExtractTokenFromHeader(authHeader string) (string, error) {
+	if len(authHeader) < 7 || authHeader[:7] != "Bearer " {
+		return "", errors.New("invalid authorization header")
+	}
+	return authHeader[7:], nil
 } 
\ No newline at end of file
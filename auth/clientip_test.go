@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	networks, err := ParseTrustedProxies("10.0.0.0/8, 192.168.1.5, fd00::/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetTrustedProxies(networks)
+	defer SetTrustedProxies(nil)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{"direct client", "203.0.113.7:4000", nil, "203.0.113.7"},
+		{"header from untrusted peer is ignored", "203.0.113.7:4000", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"trusted proxy without header", "10.1.2.3:4000", nil, "10.1.2.3"},
+		{"trusted proxy", "10.1.2.3:4000", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"spoofed hops left of the client", "10.1.2.3:4000", []string{"1.1.1.1, 2.2.2.2, 198.51.100.1"}, "198.51.100.1"},
+		{"chain of trusted proxies", "10.1.2.3:4000", []string{"198.51.100.1, 192.168.1.5, 10.9.9.9"}, "198.51.100.1"},
+		{"repeated headers", "10.1.2.3:4000", []string{"1.1.1.1", "198.51.100.1, 10.9.9.9"}, "198.51.100.1"},
+		{"every hop trusted", "10.1.2.3:4000", []string{"10.0.0.1, 10.0.0.2"}, "10.0.0.1"},
+		{"malformed hop", "10.1.2.3:4000", []string{"not-an-ip, 10.9.9.9"}, "10.9.9.9"},
+		{"ipv6 proxy", "[fd00::1]:4000", []string{"2001:db8::1"}, "2001:db8::1"},
+		{"single trusted address only", "192.168.1.6:4000", []string{"198.51.100.1"}, "192.168.1.6"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = test.remoteAddr
+			for _, value := range test.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+			if got := ClientIP(r); got != test.want {
+				t.Errorf("ClientIP = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxiesRejectsInvalidEntries(t *testing.T) {
+	for _, list := range []string{"10.0.0", "10.0.0.0/33", "proxy.internal"} {
+		if _, err := ParseTrustedProxies(list); err == nil {
+			t.Errorf("ParseTrustedProxies(%q) succeeded", list)
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Supported JWT signing algorithms
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// defaultJWTSecret is used when no key is configured and APP_ENV=development.
+// Anywhere else a missing key is an error, since tokens signed with a published
+// secret can be forged by anyone.
+const defaultJWTSecret = "your-secret-key"
+
+// ErrNoSigningKey is returned when a token is issued before any key is configured
+var ErrNoSigningKey = errors.New("no JWT signing key is configured")
+
+// SigningKey is a key used to sign and/or verify JWTs, identified by its kid
+type SigningKey struct {
+	ID        string
+	Algorithm string
+
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// CanSign reports whether the key holds private material and can issue tokens
+func (k *SigningKey) CanSign() bool {
+	return k.signKey != nil
+}
+
+var (
+	keysMu     sync.RWMutex
+	signingKey *SigningKey
+	verifyKeys map[string]*SigningKey
+)
+
+// NewHMACKey creates an HS256 key from a shared secret
+func NewHMACKey(id string, secret []byte) (*SigningKey, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("HS256 secret must not be empty")
+	}
+	return &SigningKey{ID: id, Algorithm: AlgHS256, method: jwt.SigningMethodHS256, signKey: secret, verifyKey: secret}, nil
+}
+
+// NewRSAKey creates an RS256 key. A nil private key makes it verification-only.
+func NewRSAKey(id string, private *rsa.PrivateKey, public *rsa.PublicKey) *SigningKey {
+	key := &SigningKey{ID: id, Algorithm: AlgRS256, method: jwt.SigningMethodRS256, verifyKey: public}
+	if private != nil {
+		key.signKey = private
+		key.verifyKey = &private.PublicKey
+	}
+	return key
+}
+
+// NewEd25519Key creates an EdDSA key. A nil private key makes it verification-only.
+func NewEd25519Key(id string, private ed25519.PrivateKey, public ed25519.PublicKey) *SigningKey {
+	key := &SigningKey{ID: id, Algorithm: AlgEdDSA, method: jwt.SigningMethodEdDSA, verifyKey: public}
+	if private != nil {
+		key.signKey = private
+		key.verifyKey = private.Public()
+	}
+	return key
+}
+
+// UseKeys installs the key used to sign new tokens plus any additional keys that
+// are still accepted for verification, e.g. the previous key during a rotation.
+func UseKeys(signing *SigningKey, additional ...*SigningKey) {
+	keys := map[string]*SigningKey{signing.ID: signing}
+	for _, key := range additional {
+		keys[key.ID] = key
+	}
+
+	keysMu.Lock()
+	defer keysMu.Unlock()
+	signingKey = signing
+	verifyKeys = keys
+}
+
+// currentSigningKey returns the key used to sign new tokens
+func currentSigningKey() *SigningKey {
+	keysMu.RLock()
+	defer keysMu.RUnlock()
+	return signingKey
+}
+
+// signToken signs claims with the current signing key and sets the kid header
+func signToken(claims jwt.Claims) (string, error) {
+	key := currentSigningKey()
+	if key == nil {
+		return "", ErrNoSigningKey
+	}
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.signKey)
+}
+
+// verificationKey picks the key a token was signed with based on its kid header
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	keysMu.RLock()
+	defer keysMu.RUnlock()
+
+	key := signingKey
+	if kid, ok := token.Header["kid"].(string); ok {
+		key, ok = verifyKeys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+	}
+	if key == nil {
+		return nil, ErrNoSigningKey
+	}
+
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+	return key.verifyKey, nil
+}
+
+// LoadKeysFromEnv configures JWT keys from the environment:
+//
+//	JWT_ALGORITHM         HS256 (default), RS256 or EdDSA
+//	JWT_KEY_ID            kid of the signing key (default "default")
+//	JWT_SECRET            HS256 secret (or JWT_SECRET_FILE); only optional with APP_ENV=development
+//	JWT_PRIVATE_KEY_FILE  PEM private key for RS256/EdDSA (or JWT_PRIVATE_KEY inline)
+//	JWT_VERIFY_KEYS       extra keys still accepted during rotation, as a comma separated
+//	                      list of kid:ALG:path (PEM public key, or a secret file for HS256)
+func LoadKeysFromEnv() error {
+	algorithm := os.Getenv("JWT_ALGORITHM")
+	if algorithm == "" {
+		algorithm = AlgHS256
+	}
+	keyID := os.Getenv("JWT_KEY_ID")
+	if keyID == "" {
+		keyID = "default"
+	}
+
+	var signing *SigningKey
+	switch algorithm {
+	case AlgHS256:
+		secret, err := valueOrFile("JWT_SECRET", "JWT_SECRET_FILE")
+		if err != nil {
+			return err
+		}
+		if secret == "" {
+			if os.Getenv("APP_ENV") != "development" {
+				return errors.New("JWT_SECRET or JWT_SECRET_FILE is required (set APP_ENV=development to use the insecure development secret)")
+			}
+			log.Println("⚠️ JWT_SECRET is not set, using the insecure development secret")
+			secret = defaultJWTSecret
+		}
+		signing, err = NewHMACKey(keyID, []byte(secret))
+		if err != nil {
+			return err
+		}
+	case AlgRS256, AlgEdDSA:
+		pemData, err := valueOrFile("JWT_PRIVATE_KEY", "JWT_PRIVATE_KEY_FILE")
+		if err != nil {
+			return err
+		}
+		if pemData == "" {
+			return fmt.Errorf("JWT_PRIVATE_KEY_FILE is required for %s", algorithm)
+		}
+		signing, err = parsePrivateKey(keyID, algorithm, []byte(pemData))
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported JWT_ALGORITHM %q", algorithm)
+	}
+
+	var additional []*SigningKey
+	for _, entry := range strings.Split(os.Getenv("JWT_VERIFY_KEYS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 {
+			return fmt.Errorf("invalid JWT_VERIFY_KEYS entry %q, expected kid:ALG:path", entry)
+		}
+		data, err := os.ReadFile(parts[2])
+		if err != nil {
+			return fmt.Errorf("failed to read verification key %s: %v", parts[0], err)
+		}
+		key, err := parseVerificationKey(parts[0], parts[1], data)
+		if err != nil {
+			return err
+		}
+		additional = append(additional, key)
+	}
+
+	UseKeys(signing, additional...)
+	return nil
+}
+
+// valueOrFile reads a setting from an environment variable or the file named by another
+func valueOrFile(valueVar, fileVar string) (string, error) {
+	if value := os.Getenv(valueVar); value != "" {
+		return value, nil
+	}
+	if path := os.Getenv(fileVar); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %v", fileVar, err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return "", nil
+}
+
+// parsePrivateKey parses a PEM encoded PKCS#8 (or PKCS#1 RSA) private key
+func parsePrivateKey(id, algorithm string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: no PEM data found", id)
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		rsaKey, rsaErr := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if rsaErr != nil {
+			return nil, fmt.Errorf("key %s: failed to parse private key: %v", id, err)
+		}
+		parsed = rsaKey
+	}
+
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		if algorithm == AlgRS256 {
+			return NewRSAKey(id, private, nil), nil
+		}
+	case ed25519.PrivateKey:
+		if algorithm == AlgEdDSA {
+			return NewEd25519Key(id, private, nil), nil
+		}
+	}
+	return nil, fmt.Errorf("key %s: private key type does not match %s", id, algorithm)
+}
+
+// parseVerificationKey parses a public key (or HS256 secret) accepted for verification only
+func parseVerificationKey(id, algorithm string, data []byte) (*SigningKey, error) {
+	if algorithm == AlgHS256 {
+		key, err := NewHMACKey(id, []byte(strings.TrimSpace(string(data))))
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %v", id, err)
+		}
+		return key, nil
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: no PEM data found", id)
+	}
+	if block.Type != "PUBLIC KEY" && block.Type != "RSA PUBLIC KEY" {
+		// Accept a private key file too and use its public half
+		private, err := parsePrivateKey(id, algorithm, data)
+		if err != nil {
+			return nil, err
+		}
+		private.signKey = nil
+		return private, nil
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		rsaKey, rsaErr := x509.ParsePKCS1PublicKey(block.Bytes)
+		if rsaErr != nil {
+			return nil, fmt.Errorf("key %s: failed to parse public key: %v", id, err)
+		}
+		parsed = rsaKey
+	}
+
+	switch public := parsed.(type) {
+	case *rsa.PublicKey:
+		if algorithm == AlgRS256 {
+			return NewRSAKey(id, nil, public), nil
+		}
+	case ed25519.PublicKey:
+		if algorithm == AlgEdDSA {
+			return NewEd25519Key(id, nil, public), nil
+		}
+	}
+	return nil, fmt.Errorf("key %s: public key type does not match %s", id, algorithm)
+}
+
+// JWK is a JSON Web Key as published in the JWKS document
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+// JWKS returns the public keys other services can use to verify tokens.
+// Symmetric HS256 keys are never published.
+func JWKS() []JWK {
+	keysMu.RLock()
+	defer keysMu.RUnlock()
+
+	keys := []JWK{}
+	for _, key := range verifyKeys {
+		switch public := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			keys = append(keys, JWK{
+				KeyType:   "RSA",
+				KeyID:     key.ID,
+				Algorithm: key.Algorithm,
+				Use:       "sig",
+				N:         base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			keys = append(keys, JWK{
+				KeyType:   "OKP",
+				KeyID:     key.ID,
+				Algorithm: key.Algorithm,
+				Use:       "sig",
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].KeyID < keys[j].KeyID })
+	return keys
+}
+
+// JWKSHandler serves the JWKS document at /.well-known/jwks.json
+func JWKSHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(map[string]interface{}{"keys": JWKS()})
+}
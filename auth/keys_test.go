@@ -0,0 +1,50 @@
+package auth
+
+import "testing"
+
+func TestLoadKeysFromEnvRequiresSecret(t *testing.T) {
+	t.Setenv("JWT_ALGORITHM", "")
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("JWT_SECRET_FILE", "")
+	t.Setenv("JWT_VERIFY_KEYS", "")
+
+	for _, env := range []string{"", "production", "Development"} {
+		t.Setenv("APP_ENV", env)
+		if err := LoadKeysFromEnv(); err == nil {
+			t.Errorf("APP_ENV=%q: loaded keys without a JWT secret", env)
+		}
+	}
+
+	t.Setenv("APP_ENV", "development")
+	if err := LoadKeysFromEnv(); err != nil {
+		t.Fatalf("APP_ENV=development: %v", err)
+	}
+	if key := currentSigningKey(); key == nil || string(key.signKey.([]byte)) != defaultJWTSecret {
+		t.Errorf("APP_ENV=development: expected the development secret")
+	}
+
+	t.Setenv("APP_ENV", "")
+	t.Setenv("JWT_SECRET", "a-configured-secret")
+	if err := LoadKeysFromEnv(); err != nil {
+		t.Fatalf("JWT_SECRET set: %v", err)
+	}
+	if key := currentSigningKey(); string(key.signKey.([]byte)) != "a-configured-secret" {
+		t.Errorf("JWT_SECRET set: expected the configured secret")
+	}
+}
+
+func TestTokensNeedAConfiguredKey(t *testing.T) {
+	keysMu.Lock()
+	previous, previousVerify := signingKey, verifyKeys
+	signingKey, verifyKeys = nil, nil
+	keysMu.Unlock()
+	defer func() {
+		keysMu.Lock()
+		signingKey, verifyKeys = previous, previousVerify
+		keysMu.Unlock()
+	}()
+
+	if _, err := GenerateToken(&User{ID: 1, Email: "user@example.com", Role: "customer"}, "session"); err != ErrNoSigningKey {
+		t.Errorf("GenerateToken without a key: got %v, want ErrNoSigningKey", err)
+	}
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"ai-catalog/model"
+	"ai-catalog/repository"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Repos stores sessions, API keys and single-use tokens
+var Repos *repository.Repositories
+
+// SetRepositories sets the repositories the auth package stores its data in
+func SetRepositories(repos *repository.Repositories) {
+	Repos = repos
+}
+
+// ErrInvalidRefreshToken is returned when a refresh token is unknown, expired or revoked
+var ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
+// ErrSessionRevoked is returned when a token belongs to a revoked or expired session
+var ErrSessionRevoked = errors.New("session has been revoked")
+
+// Session represents a server-side login session backing a refresh token
+type Session = model.Session
+
+// AccessTokenTTL returns the lifetime of access tokens (ACCESS_TOKEN_TTL, default 15m)
+func AccessTokenTTL() time.Duration {
+	return durationFromEnv("ACCESS_TOKEN_TTL", 15*time.Minute)
+}
+
+// RefreshTokenTTL returns the lifetime of refresh tokens (REFRESH_TOKEN_TTL, default 30 days)
+func RefreshTokenTTL() time.Duration {
+	return durationFromEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+}
+
+func durationFromEnv(name string, fallback time.Duration) time.Duration {
+	if value := os.Getenv(name); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			return d
+		}
+	}
+	return fallback
+}
+
+// randomToken returns a URL-safe random string built from n random bytes
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hex-encoded SHA-256 hash used to store opaque tokens
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateSession starts a new session for a user and returns it with its refresh token
+func CreateSession(ctx context.Context, userID int, userAgent, ipAddress string) (*Session, string, error) {
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		return nil, "", fmt.Errorf("failed to generate session id: %v", err)
+	}
+	refreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate refresh token: %v", err)
+	}
+
+	session, err := Repos.Sessions.Create(ctx, repository.NewSession{
+		ID:               hex.EncodeToString(idBytes),
+		UserID:           userID,
+		RefreshTokenHash: HashToken(refreshToken),
+		UserAgent:        userAgent,
+		IPAddress:        ipAddress,
+		TTL:              RefreshTokenTTL(),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return session, refreshToken, nil
+}
+
+// RotateRefreshToken exchanges a refresh token for a new one on the same session.
+// Presenting a refresh token that was already rotated revokes the whole session,
+// since it means the token was copied.
+func RotateRefreshToken(ctx context.Context, refreshToken string) (*Session, string, error) {
+	newRefreshToken, err := randomToken(32)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate refresh token: %v", err)
+	}
+
+	session, err := Repos.Sessions.Rotate(ctx, HashToken(refreshToken), HashToken(newRefreshToken), RefreshTokenTTL())
+	if err == repository.ErrSessionNotFound {
+		return nil, "", ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return session, newRefreshToken, nil
+}
+
+// RevokeSession revokes a single session
+func RevokeSession(ctx context.Context, sessionID string) error {
+	return Repos.Sessions.Revoke(ctx, sessionID)
+}
+
+// RevokeAllSessions revokes every active session of a user
+func RevokeAllSessions(ctx context.Context, userID int) error {
+	return Repos.Sessions.RevokeAll(ctx, userID)
+}
+
+// RevokeOtherSessions revokes every active session of a user except the given one
+func RevokeOtherSessions(ctx context.Context, userID int, keepSessionID string) error {
+	return Repos.Sessions.RevokeOthers(ctx, userID, keepSessionID)
+}
+
+// IsSessionActive reports whether a session exists and is neither revoked nor expired
+func IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	return Repos.Sessions.IsActive(ctx, sessionID)
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"ai-catalog/repository"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Purposes of single-use user tokens
+const (
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailVerification = "email_verification"
+)
+
+// Lifetimes of single-use user tokens
+const (
+	PasswordResetTokenTTL     = time.Hour
+	EmailVerificationTokenTTL = 48 * time.Hour
+)
+
+// ErrInvalidToken is returned when a single-use token is unknown, expired or already used
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// CreateUserToken issues a single-use token for the given purpose. Only the
+// hash is stored; any earlier unused token with the same purpose is invalidated.
+func CreateUserToken(ctx context.Context, userID int, purpose string, ttl time.Duration) (string, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token: %v", err)
+	}
+	if err := Repos.UserTokens.Create(ctx, userID, purpose, HashToken(token), ttl); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// ConsumeUserToken marks a token as used and returns the user it was issued to
+func ConsumeUserToken(ctx context.Context, token, purpose string) (int, error) {
+	userID, err := Repos.UserTokens.Consume(ctx, HashToken(token), purpose)
+	if err == repository.ErrUserTokenNotFound {
+		return 0, ErrInvalidToken
+	}
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed of the RFC 6238 appendix B test vectors
+var rfc6238Secret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestVerifyTOTPMatchesRFC6238(t *testing.T) {
+	// The RFC lists 8-digit codes; 6-digit codes are their last six digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+	for _, test := range tests {
+		code := test.code[len(test.code)-totpDigits:]
+		step, ok := VerifyTOTP(rfc6238Secret, code, time.Unix(test.unix, 0))
+		if !ok || step != test.unix/totpPeriod {
+			t.Errorf("VerifyTOTP(%s at %d) = %d, %v, want step %d", code, test.unix, step, ok, test.unix/totpPeriod)
+		}
+	}
+}
+
+func TestVerifyTOTPWindow(t *testing.T) {
+	// 050471 is the code of the step containing T = 1111111111
+	issued := time.Unix(1111111111, 0)
+	wantStep := issued.Unix() / totpPeriod
+	tests := []struct {
+		name   string
+		at     time.Time
+		wantOK bool
+	}{
+		{"same step", issued, true},
+		{"one step later", issued.Add(totpPeriod * time.Second), true},
+		{"one step earlier", issued.Add(-totpPeriod * time.Second), true},
+		{"two steps later", issued.Add(2 * totpPeriod * time.Second), false},
+		{"two steps earlier", issued.Add(-2 * totpPeriod * time.Second), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			step, ok := VerifyTOTP(rfc6238Secret, "050471", test.at)
+			if ok != test.wantOK {
+				t.Fatalf("VerifyTOTP at %d: ok = %v, want %v", test.at.Unix(), ok, test.wantOK)
+			}
+			// The step is the one the code belongs to, so replays are detected across the window
+			if ok && step != wantStep {
+				t.Errorf("VerifyTOTP at %d: step = %d, want %d", test.at.Unix(), step, wantStep)
+			}
+		})
+	}
+}
+
+func TestVerifyTOTPInput(t *testing.T) {
+	at := time.Unix(59, 0)
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		wantOK bool
+	}{
+		{"spaces in code", rfc6238Secret, " 287 082 ", true},
+		{"lower-case secret", strings.ToLower(rfc6238Secret), "287082", true},
+		{"wrong code", rfc6238Secret, "287083", false},
+		{"short code", rfc6238Secret, "28708", false},
+		{"eight digits", rfc6238Secret, "94287082", false},
+		{"invalid secret", "not base32!", "287082", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, ok := VerifyTOTP(test.secret, test.code, at); ok != test.wantOK {
+				t.Errorf("VerifyTOTP(%q, %q): ok = %v, want %v", test.secret, test.code, ok, test.wantOK)
+			}
+		})
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(RecoveryCodeCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' || NormalizeRecoveryCode(code) != code {
+			t.Errorf("recovery code %q is not in canonical xxxxx-xxxxx form", code)
+		}
+		if seen[code] {
+			t.Errorf("recovery code %q issued twice", code)
+		}
+		seen[code] = true
+	}
+
+	for typed, want := range map[string]string{
+		"ABCDE-FGHIJ":   "abcde-fghij",
+		"abcdefghij":    "abcde-fghij",
+		" abcde fghij ": "abcde-fghij",
+		"abc":           "abc",
+	} {
+		if got := NormalizeRecoveryCode(typed); got != want {
+			t.Errorf("NormalizeRecoveryCode(%q) = %q, want %q", typed, got, want)
+		}
+	}
+}
+
+func TestTwoFactorChallengesHaveUniqueIDs(t *testing.T) {
+	key, err := NewHMACKey("test", []byte("test-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	UseKeys(key)
+
+	ids := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		token, expiresAt, err := GenerateTwoFactorChallenge(7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		challenge, err := ValidateTwoFactorChallenge(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if challenge.UserID != 7 || challenge.ID == "" || !challenge.ExpiresAt.Equal(expiresAt.Truncate(time.Second)) {
+			t.Errorf("challenge = %+v, want user 7 expiring at %v", challenge, expiresAt)
+		}
+		if ids[challenge.ID] {
+			t.Errorf("challenge ID %q issued twice", challenge.ID)
+		}
+		ids[challenge.ID] = true
+	}
+
+	// Access tokens are not challenges
+	access, err := GenerateToken(&User{ID: 7, Email: "user@example.com", Role: RoleCustomer}, "session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ValidateTwoFactorChallenge(access); err != ErrInvalidChallenge {
+		t.Errorf("ValidateTwoFactorChallenge(access token): got %v, want %v", err, ErrInvalidChallenge)
+	}
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Two-factor authentication settings
+const (
+	// TwoFactorChallengeTTL is how long a user has to enter their code after the password step
+	TwoFactorChallengeTTL = 5 * time.Minute
+	// RecoveryCodeCount is the number of recovery codes issued when 2FA is enabled
+	RecoveryCodeCount = 10
+	// PurposeTwoFactorChallenge marks a JWT that only proves the password step of a login
+	PurposeTwoFactorChallenge = "2fa_challenge"
+)
+
+// Reasons recorded for the two-factor step of a login
+const (
+	// LoginReasonTwoFactorPending is recorded when the password was correct but a code is still needed
+	LoginReasonTwoFactorPending = "two_factor_pending"
+	LoginReasonInvalidTwoFactor = "invalid_two_factor"
+)
+
+// ErrInvalidChallenge is returned when a two-factor challenge token is invalid or expired
+var ErrInvalidChallenge = errors.New("invalid or expired two-factor challenge")
+
+// ErrInvalidTwoFactorCode is returned when a TOTP or recovery code does not match
+var ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+
+// TwoFactorChallenge is a validated two-factor challenge token
+type TwoFactorChallenge struct {
+	// ID is the token's jti, recorded when the challenge is used so it cannot
+	// complete a second login
+	ID        string
+	UserID    int
+	ExpiresAt time.Time
+}
+
+// GenerateTwoFactorChallenge issues a short-lived token proving that a user passed
+// the password step. It cannot be used as an access token.
+func GenerateTwoFactorChallenge(userID int) (string, time.Time, error) {
+	id, err := randomToken(16)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate challenge id: %v", err)
+	}
+	expiresAt := time.Now().Add(TwoFactorChallengeTTL)
+	claims := &Claims{
+		UserID:  userID,
+		Purpose: PurposeTwoFactorChallenge,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			Issuer:    "fintks-store",
+		},
+	}
+
+	token, err := signToken(claims)
+	return token, expiresAt, err
+}
+
+// ValidateTwoFactorChallenge checks a challenge token's signature and expiry.
+// Callers must still record its ID to keep it from being used twice.
+func ValidateTwoFactorChallenge(tokenString string) (*TwoFactorChallenge, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, verificationKey,
+		jwt.WithValidMethods([]string{AlgHS256, AlgRS256, AlgEdDSA}), jwt.WithExpirationRequired())
+	if err != nil || !token.Valid || claims.Purpose != PurposeTwoFactorChallenge || claims.ID == "" {
+		return nil, ErrInvalidChallenge
+	}
+	return &TwoFactorChallenge{ID: claims.ID, UserID: claims.UserID, ExpiresAt: claims.ExpiresAt.Time}, nil
+}
@@ -0,0 +1,41 @@
+// Command mockoidc runs a local mock OpenID Connect provider for trying out social
+// login without registering an application with a real provider:
+//
+//	go run ./cmd/mockoidc -addr :9000
+//
+// then start the store with
+//
+//	OIDC_PROVIDERS=mock OIDC_MOCK_ISSUER=http://localhost:9000 OIDC_MOCK_CLIENT_ID=fintks-store
+//
+// and open http://localhost:8080/auth/oidc/mock/login.
+package main
+
+import (
+	"ai-catalog/oidc/oidctest"
+	"flag"
+	"log"
+	"net/http"
+)
+
+func main() {
+	addr := flag.String("addr", ":9000", "address to listen on")
+	issuer := flag.String("issuer", "http://localhost:9000", "issuer URL the provider is reachable at")
+	clientID := flag.String("client-id", "fintks-store", "client ID accepted by the provider")
+	clientSecret := flag.String("client-secret", "", "client secret required by the provider (optional)")
+	flag.Parse()
+
+	provider, err := oidctest.NewProvider(*issuer, *clientID, *clientSecret)
+	if err != nil {
+		log.Fatal("Failed to create mock provider:", err)
+	}
+
+	// customer@fintks.com matches a seeded account, so signing in with it links the identity
+	provider.AddUser(oidctest.User{Subject: "mock-customer", Email: "customer@fintks.com", EmailVerified: true, GivenName: "Customer", FamilyName: "User"})
+	provider.AddUser(oidctest.User{Subject: "mock-new", Email: "new.user@example.com", EmailVerified: true, GivenName: "New", FamilyName: "User"})
+	provider.AddUser(oidctest.User{Subject: "mock-unverified", Email: "unverified@example.com", EmailVerified: false, GivenName: "Unverified", FamilyName: "User"})
+
+	log.Printf("Mock OIDC provider %s listening on %s (client ID %q)", *issuer, *addr, *clientID)
+	if err := http.ListenAndServe(*addr, provider); err != nil {
+		log.Fatal("Failed to start mock provider:", err)
+	}
+}
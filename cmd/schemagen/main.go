@@ -0,0 +1,575 @@
+// Command schemagen generates the graphql-go schema and typed resolver interfaces
+// from an SDL file, so the SDL is the single source of truth for the API:
+//
+//	go run ./cmd/schemagen -schema graph/schema.graphqls -config graph/schemagen.json -out graph/schema_gen.go
+//
+// It is normally run through go generate in the graph package. The generated
+// NewSchema builds every type in the SDL and calls a ResolverRoot for the fields
+// of Query and Mutation, for fields with arguments, and for the object fields
+// listed under "resolvers" in the config. Other fields are read from the model
+// structs by graphql-go's default resolver, using their json tags.
+//
+// Object types map to the struct of the same name in the output package unless
+// "models" maps them to another type, such as "ai-catalog/jobs.Job". Input types
+// become generated structs, or a plain map when mapped to map[string]interface{},
+// which lets resolvers tell omitted fields apart from empty ones.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// mapModel is the model name that decodes an input type into a plain map
+const mapModel = "map[string]interface{}"
+
+// config customizes how SDL types map to Go
+type config struct {
+	// Package is the name of the generated package
+	Package string `json:"package"`
+	// Models maps SDL type names to Go types, as "import/path.Type" or mapModel
+	Models map[string]string `json:"models"`
+	// Resolvers lists the object fields that are resolved by a ResolverRoot
+	// method instead of read from the model
+	Resolvers map[string][]string `json:"resolvers"`
+}
+
+// scalars maps the built-in scalars to graphql-go types and Go types
+var scalars = map[string]struct{ graphql, goType string }{
+	"Int":     {"graphql.Int", "int"},
+	"Float":   {"graphql.Float", "float64"},
+	"String":  {"graphql.String", "string"},
+	"Boolean": {"graphql.Boolean", "bool"},
+	"ID":      {"graphql.ID", "string"},
+}
+
+func main() {
+	schemaPath := flag.String("schema", "schema.graphqls", "SDL file to generate from")
+	configPath := flag.String("config", "schemagen.json", "generator config")
+	outPath := flag.String("out", "schema_gen.go", "Go file to write")
+	flag.Parse()
+
+	source, err := os.ReadFile(*schemaPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	doc, err := parser.Parse(parser.ParseParams{Source: string(source)})
+	if err != nil {
+		log.Fatalf("failed to parse %s: %v", *schemaPath, err)
+	}
+
+	cfg := config{Package: "graph"}
+	if raw, err := os.ReadFile(*configPath); err == nil {
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			log.Fatalf("failed to parse %s: %v", *configPath, err)
+		}
+	} else if !os.IsNotExist(err) {
+		log.Fatal(err)
+	}
+
+	g := newGenerator(cfg, path.Base(*schemaPath))
+	for _, definition := range doc.Definitions {
+		switch d := definition.(type) {
+		case *ast.ObjectDefinition:
+			g.objects = append(g.objects, d)
+		case *ast.InputObjectDefinition:
+			g.inputs = append(g.inputs, d)
+		default:
+			log.Fatalf("unsupported definition %T in %s", definition, *schemaPath)
+		}
+	}
+
+	code, err := g.generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*outPath, code, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type generator struct {
+	cfg     config
+	source  string
+	objects []*ast.ObjectDefinition
+	inputs  []*ast.InputObjectDefinition
+	imports map[string]bool
+	out     strings.Builder
+}
+
+func newGenerator(cfg config, source string) *generator {
+	return &generator{
+		cfg:     cfg,
+		source:  source,
+		imports: map[string]bool{"github.com/graphql-go/graphql": true},
+	}
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.out, format, args...)
+}
+
+// generate renders the Go file and formats it
+func (g *generator) generate() ([]byte, error) {
+	for _, object := range g.objects {
+		for _, field := range object.Fields {
+			if err := g.checkType(field.Type); err != nil {
+				return nil, fmt.Errorf("%s.%s: %v", object.Name.Value, field.Name.Value, err)
+			}
+			for _, arg := range field.Arguments {
+				if err := g.checkType(arg.Type); err != nil {
+					return nil, fmt.Errorf("%s.%s(%s): %v", object.Name.Value, field.Name.Value, arg.Name.Value, err)
+				}
+			}
+		}
+	}
+	for _, input := range g.inputs {
+		for _, field := range input.Fields {
+			if err := g.checkType(field.Type); err != nil {
+				return nil, fmt.Errorf("%s.%s: %v", input.Name.Value, field.Name.Value, err)
+			}
+		}
+	}
+
+	g.printResolverRoot()
+	for _, object := range g.objects {
+		g.printObjectResolver(object)
+	}
+	for _, input := range g.inputs {
+		g.printInput(input)
+	}
+	g.printNewSchema()
+
+	var file strings.Builder
+	fmt.Fprintf(&file, "// Code generated by schemagen from %s. DO NOT EDIT.\n\n", g.source)
+	fmt.Fprintf(&file, "package %s\n\nimport (\n", g.cfg.Package)
+	// Module imports first, then third-party packages, as in the rest of the repo
+	var local, external []string
+	for imp := range g.imports {
+		if strings.Contains(strings.Split(imp, "/")[0], ".") {
+			external = append(external, imp)
+		} else {
+			local = append(local, imp)
+		}
+	}
+	sort.Strings(local)
+	sort.Strings(external)
+	for _, imp := range local {
+		fmt.Fprintf(&file, "\t%q\n", imp)
+	}
+	if len(local) > 0 && len(external) > 0 {
+		file.WriteString("\n")
+	}
+	for _, imp := range external {
+		fmt.Fprintf(&file, "\t%q\n", imp)
+	}
+	file.WriteString(")\n\n")
+	file.WriteString(g.out.String())
+
+	code, err := format.Source([]byte(file.String()))
+	if err != nil {
+		return nil, fmt.Errorf("generated code does not compile: %v\n%s", err, file.String())
+	}
+	return code, nil
+}
+
+// checkType reports references to types that are not defined
+func (g *generator) checkType(t ast.Type) error {
+	name := namedType(t).Name.Value
+	if _, ok := scalars[name]; ok || g.object(name) != nil || g.input(name) != nil {
+		return nil
+	}
+	return fmt.Errorf("unknown type %s", name)
+}
+
+func (g *generator) object(name string) *ast.ObjectDefinition {
+	for _, object := range g.objects {
+		if object.Name.Value == name {
+			return object
+		}
+	}
+	return nil
+}
+
+func (g *generator) input(name string) *ast.InputObjectDefinition {
+	for _, input := range g.inputs {
+		if input.Name.Value == name {
+			return input
+		}
+	}
+	return nil
+}
+
+// isRoot reports whether an object is one of the operation types, whose fields
+// are all resolved by the ResolverRoot
+func isRoot(object *ast.ObjectDefinition) bool {
+	return object.Name.Value == "Query" || object.Name.Value == "Mutation"
+}
+
+// resolvedFields returns the fields of an object that need a resolver method
+func (g *generator) resolvedFields(object *ast.ObjectDefinition) []*ast.FieldDefinition {
+	var fields []*ast.FieldDefinition
+	for _, field := range object.Fields {
+		if isRoot(object) || len(field.Arguments) > 0 || g.listedResolver(object.Name.Value, field.Name.Value) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+func (g *generator) listedResolver(object, field string) bool {
+	for _, name := range g.cfg.Resolvers[object] {
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
+
+// modelType returns the Go type a named SDL type maps to, without a pointer
+func (g *generator) modelType(name string) string {
+	if s, ok := scalars[name]; ok {
+		return s.goType
+	}
+	model, ok := g.cfg.Models[name]
+	if !ok {
+		return name
+	}
+	if model == mapModel {
+		return model
+	}
+	i := strings.LastIndex(model, ".")
+	if i < 0 {
+		return model
+	}
+	g.imports[model[:i]] = true
+	return path.Base(model[:i]) + model[i:]
+}
+
+// goType returns the Go type of a value of SDL type t. Objects are pointers and
+// nullable scalars and inputs are pointers unless hasDefault; lists are slices.
+func (g *generator) goType(t ast.Type, hasDefault bool) string {
+	nonNull := false
+	if n, ok := t.(*ast.NonNull); ok {
+		nonNull = true
+		t = n.Type
+	}
+	if list, ok := t.(*ast.List); ok {
+		return "[]" + g.goType(list.Type, false)
+	}
+
+	name := t.(*ast.Named).Name.Value
+	model := g.modelType(name)
+	switch {
+	case model == mapModel:
+		return model
+	case g.object(name) != nil:
+		return "*" + model
+	case nonNull || hasDefault:
+		return model
+	default:
+		return "*" + model
+	}
+}
+
+// graphqlType returns the graphql-go expression for SDL type t
+func graphqlType(t ast.Type) string {
+	switch t := t.(type) {
+	case *ast.NonNull:
+		return "graphql.NewNonNull(" + graphqlType(t.Type) + ")"
+	case *ast.List:
+		return "graphql.NewList(" + graphqlType(t.Type) + ")"
+	default:
+		name := t.(*ast.Named).Name.Value
+		if s, ok := scalars[name]; ok {
+			return s.graphql
+		}
+		return typeVar(name)
+	}
+}
+
+func namedType(t ast.Type) *ast.Named {
+	switch t := t.(type) {
+	case *ast.NonNull:
+		return namedType(t.Type)
+	case *ast.List:
+		return namedType(t.Type)
+	default:
+		return t.(*ast.Named)
+	}
+}
+
+// typeVar names the local variable holding a type in NewSchema
+func typeVar(name string) string {
+	return strings.ToLower(name[:1]) + name[1:] + "Type"
+}
+
+func exported(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// initialisms are spelled in upper case in Go names
+var initialisms = map[string]bool{"Id": true, "Ip": true, "Sku": true, "Uri": true, "Url": true}
+
+// goName exports an SDL field name, spelling common initialisms the Go way:
+// ipAddress becomes IPAddress and categoryId becomes CategoryID
+func goName(name string) string {
+	var words []string
+	start := 0
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			words = append(words, name[start:i])
+			start = i
+		}
+	}
+	words = append(words, name[start:])
+
+	for i, word := range words {
+		word = exported(word)
+		if initialisms[word] {
+			word = strings.ToUpper(word)
+		}
+		words[i] = word
+	}
+	return strings.Join(words, "")
+}
+
+func argsType(object *ast.ObjectDefinition, field *ast.FieldDefinition) string {
+	return object.Name.Value + exported(field.Name.Value) + "Args"
+}
+
+func (g *generator) printResolverRoot() {
+	g.printf("// ResolverRoot provides the resolvers for the fields of the schema that are not\n")
+	g.printf("// read directly from the models\n")
+	g.printf("type ResolverRoot interface {\n")
+	for _, object := range g.objects {
+		if len(g.resolvedFields(object)) > 0 {
+			g.printf("\t%s() %sResolver\n", object.Name.Value, object.Name.Value)
+		}
+	}
+	g.printf("}\n\n")
+}
+
+func (g *generator) printObjectResolver(object *ast.ObjectDefinition) {
+	fields := g.resolvedFields(object)
+	if len(fields) == 0 {
+		return
+	}
+
+	name := object.Name.Value
+	g.printf("// %sResolver resolves the fields of %s\n", name, name)
+	g.printf("type %sResolver interface {\n", name)
+	for _, field := range fields {
+		params := "p graphql.ResolveParams"
+		if !isRoot(object) {
+			params += ", obj *" + g.modelType(name)
+		}
+		if len(field.Arguments) > 0 {
+			params += ", args " + argsType(object, field)
+		}
+		g.printf("\t%s(%s) (%s, error)\n", exported(field.Name.Value), params, g.goType(field.Type, false))
+	}
+	g.printf("}\n\n")
+
+	for _, field := range fields {
+		if len(field.Arguments) == 0 {
+			continue
+		}
+		g.printf("// %s holds the arguments of %s.%s\n", argsType(object, field), name, field.Name.Value)
+		g.printf("type %s struct {\n", argsType(object, field))
+		for _, arg := range field.Arguments {
+			g.printf("\t%s %s\n", goName(arg.Name.Value), g.goType(arg.Type, arg.DefaultValue != nil))
+		}
+		g.printf("}\n\n")
+	}
+}
+
+func (g *generator) printInput(input *ast.InputObjectDefinition) {
+	name := input.Name.Value
+	if g.modelType(name) != name {
+		return
+	}
+
+	g.printf("// %s is the %s input type\n", name, name)
+	g.printf("type %s struct {\n", name)
+	for _, field := range input.Fields {
+		g.printf("\t%s %s\n", goName(field.Name.Value), g.goType(field.Type, field.DefaultValue != nil))
+	}
+	g.printf("}\n\n")
+
+	g.printf("// decode%s converts a %s argument value from graphql-go\n", name, name)
+	g.printf("func decode%s(m map[string]interface{}) %s {\n", name, name)
+	g.printf("\tvar in %s\n", name)
+	for _, field := range input.Fields {
+		g.printDecode("in."+goName(field.Name.Value), fmt.Sprintf("m[%q]", field.Name.Value), field.Type, field.DefaultValue != nil, 1)
+	}
+	g.printf("\treturn in\n}\n\n")
+}
+
+// printDecode prints statements that convert src, a value from graphql-go's
+// argument map, to the Go type of t and assign it to dst
+func (g *generator) printDecode(dst, src string, t ast.Type, hasDefault bool, depth int) {
+	goType := g.goType(t, hasDefault)
+	nonNull := false
+	if n, ok := t.(*ast.NonNull); ok {
+		nonNull = true
+		t = n.Type
+	}
+	v := fmt.Sprintf("v%d", depth)
+
+	if list, ok := t.(*ast.List); ok {
+		item := fmt.Sprintf("item%d", depth)
+		g.printf("if %s, ok := %s.([]interface{}); ok {\n", v, src)
+		g.printf("%s = make(%s, 0, len(%s))\n", dst, goType, v)
+		g.printf("for _, %s := range %s {\n", item, v)
+		g.printf("var elem%d %s\n", depth, g.goType(list.Type, false))
+		g.printDecode(fmt.Sprintf("elem%d", depth), item, list.Type, false, depth+1)
+		g.printf("%s = append(%s, elem%d)\n", dst, dst, depth)
+		g.printf("}\n}\n")
+		return
+	}
+
+	name := t.(*ast.Named).Name.Value
+	model := g.modelType(name)
+	switch {
+	case model == mapModel:
+		g.printf("%s, _ = %s.(map[string]interface{})\n", dst, src)
+	case g.input(name) != nil && (nonNull || hasDefault):
+		g.printf("if %s, ok := %s.(map[string]interface{}); ok {\n%s = decode%s(%s)\n}\n", v, src, dst, name, v)
+	case g.input(name) != nil:
+		g.printf("if %s, ok := %s.(map[string]interface{}); ok {\ndecoded := decode%s(%s)\n%s = &decoded\n}\n", v, src, name, v, dst)
+	case nonNull || hasDefault:
+		g.printf("%s, _ = %s.(%s)\n", dst, src, model)
+	default:
+		g.printf("if %s, ok := %s.(%s); ok {\n%s = &%s\n}\n", v, src, model, dst, v)
+	}
+}
+
+func (g *generator) printNewSchema() {
+	g.printf("// NewSchema builds the schema described by %s, resolved by r\n", g.source)
+	g.printf("func NewSchema(r ResolverRoot) (graphql.Schema, error) {\n")
+
+	var objectVars, inputVars, extraTypes []string
+	for _, object := range g.objects {
+		objectVars = append(objectVars, typeVar(object.Name.Value))
+		if !isRoot(object) {
+			extraTypes = append(extraTypes, typeVar(object.Name.Value))
+		}
+	}
+	for _, input := range g.inputs {
+		inputVars = append(inputVars, typeVar(input.Name.Value))
+		extraTypes = append(extraTypes, typeVar(input.Name.Value))
+	}
+	if len(objectVars) > 0 {
+		g.printf("var %s *graphql.Object\n", strings.Join(objectVars, ", "))
+	}
+	if len(inputVars) > 0 {
+		g.printf("var %s *graphql.InputObject\n\n", strings.Join(inputVars, ", "))
+	}
+
+	for _, input := range g.inputs {
+		g.printf("%s = graphql.NewInputObject(graphql.InputObjectConfig{\n", typeVar(input.Name.Value))
+		g.printf("Name: %q,\n", input.Name.Value)
+		g.printf("Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {\n")
+		g.printf("return graphql.InputObjectConfigFieldMap{\n")
+		for _, field := range input.Fields {
+			g.printf("%q: &graphql.InputObjectFieldConfig{Type: %s%s},\n", field.Name.Value, graphqlType(field.Type), defaultValue(field.DefaultValue))
+		}
+		g.printf("}\n}),\n})\n\n")
+	}
+
+	for _, object := range g.objects {
+		name := object.Name.Value
+		g.printf("%s = graphql.NewObject(graphql.ObjectConfig{\n", typeVar(name))
+		g.printf("Name: %q,\n", name)
+		g.printf("Fields: graphql.FieldsThunk(func() graphql.Fields {\n")
+		g.printf("return graphql.Fields{\n")
+		for _, field := range object.Fields {
+			g.printField(object, field)
+		}
+		g.printf("}\n}),\n})\n\n")
+	}
+
+	g.printf("return graphql.NewSchema(graphql.SchemaConfig{\n")
+	if g.object("Query") != nil {
+		g.printf("Query: queryType,\n")
+	}
+	if g.object("Mutation") != nil {
+		g.printf("Mutation: mutationType,\n")
+	}
+	g.printf("// Include types that no field returns yet\n")
+	g.printf("Types: []graphql.Type{%s},\n", strings.Join(extraTypes, ", "))
+	g.printf("})\n}\n")
+}
+
+func (g *generator) printField(object *ast.ObjectDefinition, field *ast.FieldDefinition) {
+	g.printf("%q: &graphql.Field{\n", field.Name.Value)
+	g.printf("Type: %s,\n", graphqlType(field.Type))
+
+	if len(field.Arguments) > 0 {
+		g.printf("Args: graphql.FieldConfigArgument{\n")
+		for _, arg := range field.Arguments {
+			g.printf("%q: &graphql.ArgumentConfig{Type: %s%s},\n", arg.Name.Value, graphqlType(arg.Type), defaultValue(arg.DefaultValue))
+		}
+		g.printf("},\n")
+	}
+
+	resolved := false
+	for _, f := range g.resolvedFields(object) {
+		resolved = resolved || f == field
+	}
+	if resolved {
+		g.printf("Resolve: func(p graphql.ResolveParams) (interface{}, error) {\n")
+		call := []string{"p"}
+		if !isRoot(object) {
+			g.printf("obj, ok := p.Source.(*%s)\n", g.modelType(object.Name.Value))
+			g.printf("if !ok {\nreturn nil, nil\n}\n")
+			call = append(call, "obj")
+		}
+		if len(field.Arguments) > 0 {
+			g.printf("var args %s\n", argsType(object, field))
+			for _, arg := range field.Arguments {
+				g.printDecode("args."+goName(arg.Name.Value), fmt.Sprintf("p.Args[%q]", arg.Name.Value), arg.Type, arg.DefaultValue != nil, 1)
+			}
+			call = append(call, "args")
+		}
+		g.printf("return r.%s().%s(%s)\n", object.Name.Value, exported(field.Name.Value), strings.Join(call, ", "))
+		g.printf("},\n")
+	}
+	g.printf("},\n")
+}
+
+// defaultValue renders an SDL default value as a DefaultValue config field
+func defaultValue(value ast.Value) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case *ast.IntValue:
+		n, err := strconv.Atoi(v.Value)
+		if err != nil {
+			log.Fatalf("invalid default value %s: %v", v.Value, err)
+		}
+		return fmt.Sprintf(", DefaultValue: %d", n)
+	case *ast.FloatValue:
+		return fmt.Sprintf(", DefaultValue: float64(%s)", v.Value)
+	case *ast.StringValue:
+		return fmt.Sprintf(", DefaultValue: %q", v.Value)
+	case *ast.BooleanValue:
+		return fmt.Sprintf(", DefaultValue: %t", v.Value)
+	default:
+		log.Fatalf("unsupported default value %T", value)
+		return ""
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/migrations"
+	"ai-catalog/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+const commandUsage = `Usage:
+  main                      start the server (applies pending migrations unless MIGRATE_ON_START=false)
+  main migrate up           apply all pending migrations
+  main migrate down [n]     roll back the last n migrations (default 1)
+  main migrate to <version> migrate up or down to the given version (0 rolls back everything)
+  main migrate status       list migrations and when they were applied
+  main seed                 load the demo catalog into an empty database
+  main grant-admin <email>  give an existing account the admin role`
+
+// runCommand runs a command-line subcommand such as "migrate up" or "seed"
+func runCommand(args []string) error {
+	switch args[0] {
+	case "migrate":
+		return runMigrate(args[1:])
+	case "seed":
+		return runSeed()
+	case "grant-admin":
+		if len(args) < 2 {
+			return fmt.Errorf("grant-admin needs an email address\n%s", commandUsage)
+		}
+		return runGrantAdmin(args[1])
+	case "help", "-h", "--help":
+		fmt.Println(commandUsage)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], commandUsage)
+	}
+}
+
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("migrate needs a subcommand\n%s", commandUsage)
+	}
+
+	db, err := Connect()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	var run []migrations.Migration
+	verb := "Applied"
+	switch args[0] {
+	case "up":
+		run, err = migrations.Up(ctx, db)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		verb = "Rolled back"
+		run, err = migrations.Down(ctx, db, steps)
+	case "to":
+		if len(args) < 2 {
+			return fmt.Errorf("migrate to needs a version\n%s", commandUsage)
+		}
+		version, convErr := strconv.Atoi(args[1])
+		if convErr != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		run, err = migrations.To(ctx, db, version)
+		verb = "Migrated"
+	case "status":
+		return printMigrationStatus(ctx, db)
+	default:
+		return fmt.Errorf("unknown migrate subcommand %q\n%s", args[0], commandUsage)
+	}
+
+	for _, migration := range run {
+		fmt.Printf("%s %s\n", verb, migration)
+	}
+	if err == nil && len(run) == 0 {
+		fmt.Println("Database schema is up to date")
+	}
+	return err
+}
+
+func printMigrationStatus(ctx context.Context, db *sql.DB) error {
+	statuses, err := migrations.Status(ctx, db)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		name := status.Name
+		if name == "" {
+			name = "(missing files)"
+		}
+		applied := "pending"
+		if status.AppliedAt != nil {
+			applied = "applied " + status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%04d  %-30s %s\n", status.Version, name, applied)
+	}
+	return nil
+}
+
+func runSeed() error {
+	db, err := Connect()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	err = migrations.Seed(context.Background(), db)
+	if errors.Is(err, migrations.ErrAlreadySeeded) {
+		fmt.Fprintln(os.Stderr, err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println("Seed data loaded")
+	return nil
+}
+
+// runGrantAdmin promotes an account to admin, for stores that were not seeded
+// with the demo admin
+func runGrantAdmin(email string) error {
+	db, err := Connect()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	ctx := context.Background()
+
+	users := repository.NewPostgres(db).Users
+	user, err := users.GetByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("%s: %v", email, err)
+	}
+	if err := users.SetRole(ctx, user.ID, auth.RoleAdmin); err != nil {
+		return err
+	}
+	fmt.Printf("%s is now an admin\n", user.Email)
+	return nil
+}
@@ -0,0 +1,193 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/jobs"
+	"ai-catalog/mailer"
+	"ai-catalog/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Mailer delivers account emails such as password resets and verification links
+var Mailer mailer.Mailer = &mailer.LogMailer{}
+
+// SetMailer sets the mailer used for account emails
+func SetMailer(m mailer.Mailer) {
+	Mailer = m
+}
+
+// appURL builds a link into the frontend app (APP_BASE_URL, default http://localhost:8080)
+func appURL(param, token string) string {
+	baseURL := os.Getenv("APP_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+	return fmt.Sprintf("%s/app?%s=%s", strings.TrimRight(baseURL, "/"), param, url.QueryEscape(token))
+}
+
+// sendVerificationEmail issues an email verification token and mails it to the user
+func sendVerificationEmail(ctx context.Context, user *User) error {
+	token, err := auth.CreateUserToken(ctx, user.ID, auth.TokenPurposeEmailVerification, auth.EmailVerificationTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	return Mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your Fintks Store email address",
+		Body: fmt.Sprintf("Hello %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nVerification code: %s\n\nThe link expires in %s.\n",
+			user.FirstName, appURL("verify_token", token), token, auth.EmailVerificationTokenTTL),
+	})
+}
+
+// sendPasswordResetEmail issues a password reset token and mails it to the user
+func sendPasswordResetEmail(ctx context.Context, user *User) error {
+	token, err := auth.CreateUserToken(ctx, user.ID, auth.TokenPurposePasswordReset, auth.PasswordResetTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	return Mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your Fintks Store password",
+		Body: fmt.Sprintf("Hello %s,\n\nWe received a request to reset your password. Open the link below to choose a new one:\n\n%s\n\nReset code: %s\n\nThe link expires in %s. If you did not request a reset you can ignore this email.\n",
+			user.FirstName, appURL("reset_token", token), token, auth.PasswordResetTokenTTL),
+	})
+}
+
+// passwordResetPayload is the payload of a JobSendPasswordReset job
+type passwordResetPayload struct {
+	Email string `json:"email"`
+}
+
+// runSendPasswordReset mails a password reset link to the account with the
+// requested email address, if there is an active one
+func runSendPasswordReset(ctx context.Context, data json.RawMessage) (interface{}, error) {
+	var payload passwordResetPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, jobs.Permanent(err)
+	}
+
+	user, err := Repos.Users.GetByEmail(ctx, payload.Email)
+	if err == repository.ErrUserNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Status != auth.StatusActive {
+		return nil, nil
+	}
+	return nil, sendPasswordResetEmail(ctx, user)
+}
+
+// UpdatePassword replaces a user's password hash
+func UpdatePassword(ctx context.Context, userID int, password string) error {
+	passwordHash, err := auth.HashPassword(password)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %v", err)
+	}
+	err = Repos.Users.UpdatePassword(ctx, userID, passwordHash)
+	InvalidateAuthenticatedUser(userID)
+	return err
+}
+
+// MarkEmailVerified flags a user's email address as verified
+func MarkEmailVerified(ctx context.Context, userID int) error {
+	err := Repos.Users.MarkEmailVerified(ctx, userID)
+	InvalidateAuthenticatedUser(userID)
+	return err
+}
+
+// logMailError records a failed account email without failing the request
+func logMailError(kind string, user *User, err error) {
+	if err != nil {
+		log.Printf("failed to send %s email to user %d: %v", kind, user.ID, err)
+	}
+}
+
+// UpdateProfile replaces a user's name and contact details. Optional fields that
+// are not given keep their current value.
+func UpdateProfile(ctx context.Context, userID int, input UpdateUserInput) (*User, error) {
+	firstName := strings.TrimSpace(input.FirstName)
+	lastName := strings.TrimSpace(input.LastName)
+
+	problems := NewValidationError()
+	if firstName == "" {
+		problems.Add("firstName", "is required")
+	}
+	if lastName == "" {
+		problems.Add("lastName", "is required")
+	}
+	if input.Phone != nil && len(*input.Phone) > 20 {
+		problems.Add("phone", "must be at most 20 characters")
+	}
+	if input.City != nil && len(*input.City) > 100 {
+		problems.Add("city", "must be at most 100 characters")
+	}
+	if err := problems.OrNil(); err != nil {
+		return nil, err
+	}
+
+	user, err := Repos.Users.UpdateProfile(ctx, userID, repository.ProfileUpdate{
+		FirstName: firstName,
+		LastName:  lastName,
+		Phone:     input.Phone,
+		Address:   input.Address,
+		City:      input.City,
+	})
+	InvalidateAuthenticatedUser(userID)
+	return user, err
+}
+
+// CreateUser hashes the password and stores a new customer account
+func CreateUser(ctx context.Context, email, password, firstName, lastName, phone, address, city string) (*User, error) {
+	passwordHash, err := auth.HashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %v", err)
+	}
+
+	return Repos.Users.Create(ctx, repository.NewUser{
+		Email:        email,
+		PasswordHash: passwordHash,
+		FirstName:    firstName,
+		LastName:     lastName,
+		Phone:        phone,
+		Address:      address,
+		City:         city,
+	})
+}
+
+// AuthenticateUser authenticates a user with email and password
+// Unknown emails, deleted accounts and wrong passwords all return auth.ErrInvalidCredentials
+func AuthenticateUser(ctx context.Context, email, password string) (*User, error) {
+	user, err := Repos.Users.GetByEmail(ctx, email)
+	if err == repository.ErrUserNotFound {
+		auth.CheckPasswordAgainstNothing(password)
+		return nil, auth.ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	passwordHash, err := Repos.Users.PasswordHash(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !auth.CheckPassword(password, passwordHash) {
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	if err := checkAccountStatus(user); err == ErrAccountDeleted {
+		return nil, auth.ErrInvalidCredentials
+	} else if err != nil {
+		return user, err
+	}
+	return user, nil
+}
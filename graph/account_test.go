@@ -0,0 +1,217 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/mailer"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingMailer keeps the messages it is asked to send
+type recordingMailer struct {
+	mu   sync.Mutex
+	sent []mailer.Message
+}
+
+func (m *recordingMailer) Send(msg mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// messages returns the messages sent so far
+func (m *recordingMailer) messages() []mailer.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]mailer.Message(nil), m.sent...)
+}
+
+// useRecordingMailer sends account emails to a recording mailer for the test
+func useRecordingMailer(t *testing.T) *recordingMailer {
+	t.Helper()
+	m := &recordingMailer{}
+	previous := Mailer
+	SetMailer(m)
+	t.Cleanup(func() { SetMailer(previous) })
+	return m
+}
+
+// mailedToken returns the code following label in the last message sent to email
+func mailedToken(t *testing.T, m *recordingMailer, email, label string) string {
+	t.Helper()
+	messages := m.messages()
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].To != email {
+			continue
+		}
+		for _, line := range strings.Split(messages[i].Body, "\n") {
+			if token, ok := strings.CutPrefix(line, label+": "); ok {
+				return token
+			}
+		}
+	}
+	t.Fatalf("no %q was mailed to %s", label, email)
+	return ""
+}
+
+// runPasswordResetJobs runs the queued password reset emails and returns how many there were
+func runPasswordResetJobs(t *testing.T) int {
+	t.Helper()
+	ctx := context.Background()
+	count := 0
+	for {
+		job, err := Repos.Jobs.Claim(ctx, []string{JobSendPasswordReset}, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job == nil {
+			return count
+		}
+		if _, err := runSendPasswordReset(ctx, job.Payload); err != nil {
+			t.Fatal(err)
+		}
+		count++
+	}
+}
+
+const (
+	requestPasswordReset = `mutation($email: String!) { requestPasswordReset(email: $email) }`
+	resetPassword        = `mutation($token: String!, $password: String!) { resetPassword(token: $token, newPassword: $password) }`
+	verifyEmail          = `mutation($token: String!) { verifyEmail(token: $token) }`
+)
+
+func TestPasswordResetRequestsDoNotRevealWhichEmailsAreRegistered(t *testing.T) {
+	useMemory(t)
+	mail := useRecordingMailer(t)
+	createUser(t, "user@example.com", auth.RoleCustomer)
+
+	for _, email := range []string{"user@example.com", "nobody@example.com"} {
+		var got struct{ RequestPasswordReset bool }
+		execute(t, context.Background(), requestPasswordReset, map[string]interface{}{"email": email}, &got)
+		if !got.RequestPasswordReset {
+			t.Errorf("requestPasswordReset(%s) = false, want true", email)
+		}
+	}
+
+	// Nothing is looked up or sent while the request is being answered
+	if sent := mail.messages(); len(sent) != 0 {
+		t.Fatalf("sent %d emails while answering the requests, want them queued", len(sent))
+	}
+	if queued := runPasswordResetJobs(t); queued != 2 {
+		t.Fatalf("queued %d password reset jobs, want one per request", queued)
+	}
+	if sent := mail.messages(); len(sent) != 1 || sent[0].To != "user@example.com" {
+		t.Errorf("sent %+v, want a single email to the registered account", sent)
+	}
+}
+
+func TestPasswordResetTokensAreSingleUse(t *testing.T) {
+	useMemory(t)
+	useSigningKey(t)
+	mail := useRecordingMailer(t)
+	ctx := context.Background()
+	user := createUser(t, "user@example.com", auth.RoleCustomer)
+	session, _, err := auth.CreateSession(ctx, user.ID, "test", "192.0.2.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	execute(t, ctx, requestPasswordReset, map[string]interface{}{"email": user.Email}, nil)
+	runPasswordResetJobs(t)
+	first := mailedToken(t, mail, user.Email, "Reset code")
+
+	// Requesting another reset invalidates the link already mailed
+	execute(t, ctx, requestPasswordReset, map[string]interface{}{"email": user.Email}, nil)
+	runPasswordResetJobs(t)
+	token := mailedToken(t, mail, user.Email, "Reset code")
+	if errs := run(t, ctx, resetPassword, map[string]interface{}{"token": first, "password": "new-password-1"}, nil); len(errs) == 0 {
+		t.Error("reset the password with a superseded token")
+	}
+
+	var got struct{ ResetPassword bool }
+	execute(t, ctx, resetPassword, map[string]interface{}{"token": token, "password": "new-password-1"}, &got)
+	if !got.ResetPassword {
+		t.Fatal("resetPassword returned false")
+	}
+	hash, err := Repos.Users.PasswordHash(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !auth.CheckPassword("new-password-1", hash) {
+		t.Error("the new password was not stored")
+	}
+	if active, err := auth.IsSessionActive(ctx, session.ID); err != nil || active {
+		t.Errorf("session active = %v (%v) after a reset, want it revoked", active, err)
+	}
+
+	errs := run(t, ctx, resetPassword, map[string]interface{}{"token": token, "password": "new-password-2"}, nil)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), auth.ErrInvalidToken.Error()) {
+		t.Errorf("reusing the reset token: got %v, want %v", errs, auth.ErrInvalidToken)
+	}
+}
+
+func TestExpiredOrMisdirectedTokensAreRejected(t *testing.T) {
+	useMemory(t)
+	ctx := context.Background()
+	user := createUser(t, "user@example.com", auth.RoleCustomer)
+
+	expired, err := auth.CreateUserToken(ctx, user.ID, auth.TokenPurposePasswordReset, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := run(t, ctx, resetPassword, map[string]interface{}{"token": expired, "password": "new-password-1"}, nil); len(errs) == 0 {
+		t.Error("reset the password with an expired token")
+	}
+
+	// A verification link cannot be used to reset the password, nor the other way round
+	verification, err := auth.CreateUserToken(ctx, user.ID, auth.TokenPurposeEmailVerification, auth.EmailVerificationTokenTTL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := run(t, ctx, resetPassword, map[string]interface{}{"token": verification, "password": "new-password-1"}, nil); len(errs) == 0 {
+		t.Error("reset the password with an email verification token")
+	}
+	reset, err := auth.CreateUserToken(ctx, user.ID, auth.TokenPurposePasswordReset, auth.PasswordResetTokenTTL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := run(t, ctx, verifyEmail, map[string]interface{}{"token": reset}, nil); len(errs) == 0 {
+		t.Error("verified the email with a password reset token")
+	}
+
+	if _, err := auth.ConsumeUserToken(ctx, "not-a-token", auth.TokenPurposePasswordReset); err != auth.ErrInvalidToken {
+		t.Errorf("consuming an unknown token: err = %v, want %v", err, auth.ErrInvalidToken)
+	}
+}
+
+func TestRegistrationMailsAVerificationLink(t *testing.T) {
+	useMemory(t)
+	useSigningKey(t)
+	mail := useRecordingMailer(t)
+	ctx := clientContext("192.0.2.1:4000", "")
+
+	var registered struct {
+		Register struct{ User struct{ ID int } }
+	}
+	execute(t, ctx, `mutation {
+		register(input: {email: "new@example.com", password: "password-123", firstName: "New", lastName: "User"}) { user { id } }
+	}`, nil, &registered)
+	token := mailedToken(t, mail, "new@example.com", "Verification code")
+
+	var got struct{ VerifyEmail bool }
+	execute(t, context.Background(), verifyEmail, map[string]interface{}{"token": token}, &got)
+	user, err := Repos.Users.GetByID(context.Background(), registered.Register.User.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.VerifyEmail || !user.EmailVerified {
+		t.Errorf("verifyEmail = %v, email verified = %v, want both true", got.VerifyEmail, user.EmailVerified)
+	}
+	if errs := run(t, context.Background(), verifyEmail, map[string]interface{}{"token": token}, nil); len(errs) == 0 {
+		t.Error("verified the email twice with the same token")
+	}
+}
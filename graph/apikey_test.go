@@ -0,0 +1,111 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/model"
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// withAPIKey returns the request context AuthMiddleware builds for an X-API-Key
+// header, or the reason the key is refused
+func withAPIKey(rawKey string) (context.Context, error) {
+	ctx := clientContext("192.0.2.1:4000", "")
+	key, err := auth.AuthenticateAPIKey(ctx, rawKey)
+	if err != nil {
+		return nil, err
+	}
+	user, err := LoadAuthenticatedUser(ctx, key.UserID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, "user", user)
+	return context.WithValue(ctx, "apiKey", key), nil
+}
+
+// createAPIKey issues an API key for user with the given scopes and returns its secret
+func createAPIKey(t *testing.T, user *User, scopes ...string) string {
+	t.Helper()
+	_, rawKey, err := auth.CreateAPIKey(context.Background(), user.ID, "integration", scopes, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rawKey
+}
+
+func TestAPIKeysAreLimitedToTheirScopes(t *testing.T) {
+	store := useMemory(t)
+	product := store.AddProduct(model.Product{Name: "Lamp", Price: 30, IsActive: true, StockQuantity: 5, SKU: "LAMP"})
+	admin := createUser(t, "admin@example.com", auth.RoleAdmin)
+	update := `mutation($id: Int!) { updateProduct(id: $id, input: {price: 35}) { price } }`
+	vars := map[string]interface{}{"id": product.ID}
+
+	ctx, err := withAPIKey(createAPIKey(t, admin, auth.ScopeCatalogRead))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := run(t, ctx, update, vars, nil)
+	if len(errs) != 1 || errorCode(errs[0]) != "INSUFFICIENT_SCOPE" {
+		t.Fatalf("updateProduct with a catalog:read key: got %v, want INSUFFICIENT_SCOPE", errs)
+	}
+	if scope := errs[0].(gqlerrors.FormattedError).Extensions["requiredScope"]; scope != auth.ScopeCatalogWrite {
+		t.Errorf("requiredScope = %v, want %s", scope, auth.ScopeCatalogWrite)
+	}
+
+	ctx, err = withAPIKey(createAPIKey(t, admin, auth.ScopeCatalogWrite))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct{ UpdateProduct struct{ Price float64 } }
+	execute(t, ctx, update, vars, &got)
+	if got.UpdateProduct.Price != 35 {
+		t.Errorf("price = %v after an update with a catalog:write key, want 35", got.UpdateProduct.Price)
+	}
+}
+
+func TestRevokedAPIKeysAreRefused(t *testing.T) {
+	useMemory(t)
+	user := createUser(t, "user@example.com", auth.RoleCustomer)
+	rawKey := createAPIKey(t, user, auth.ScopeOrdersRead)
+	key, err := auth.AuthenticateAPIKey(context.Background(), rawKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var revoked struct{ RevokeApiKey bool }
+	execute(t, asUser(user), `mutation($id: Int!) { revokeApiKey(id: $id) }`, map[string]interface{}{"id": key.ID}, &revoked)
+	if !revoked.RevokeApiKey {
+		t.Fatal("revokeApiKey returned false")
+	}
+
+	if _, err := auth.AuthenticateAPIKey(context.Background(), rawKey); err != auth.ErrInvalidAPIKey {
+		t.Errorf("authenticating a revoked key: err = %v, want %v", err, auth.ErrInvalidAPIKey)
+	}
+	if _, err := auth.AuthenticateAPIKey(context.Background(), "fks_unknown"); err != auth.ErrInvalidAPIKey {
+		t.Errorf("authenticating an unknown key: err = %v, want %v", err, auth.ErrInvalidAPIKey)
+	}
+}
+
+func TestAPIKeysCannotUseAccountMutations(t *testing.T) {
+	useMemory(t)
+	user := createUser(t, "user@example.com", auth.RoleCustomer)
+	ctx, err := withAPIKey(createAPIKey(t, user, auth.Scopes...))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, operation := range []string{
+		`mutation { changePassword(currentPassword: "password-123", newPassword: "password-456") }`,
+		`mutation { createApiKey(name: "escalated", scopes: ["users:write"]) { key } }`,
+		`mutation { revokeApiKey(id: 1) }`,
+		`mutation { logoutAllDevices }`,
+		`mutation { updateProfile(input: {firstName: "Mallory", lastName: "User"}) { id } }`,
+	} {
+		errs := run(t, ctx, operation, nil, nil)
+		if len(errs) != 1 || errorCode(errs[0]) != "INSUFFICIENT_SCOPE" {
+			t.Errorf("%s with a fully scoped key: got %v, want INSUFFICIENT_SCOPE", operation, errs)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package graph
+
+import (
+	"ai-catalog/repository"
+	"context"
+)
+
+// ErrCartItemNotFound is returned when a cart item does not exist or belongs to another user
+var ErrCartItemNotFound = repository.ErrCartItemNotFound
+
+// UpdateCartItem sets the quantity of an item in the user's cart
+func UpdateCartItem(ctx context.Context, userID, id, quantity int) (*CartItem, error) {
+	if quantity < 1 {
+		return nil, &ValidationError{Fields: map[string]string{"quantity": "must be at least 1, use removeFromCart to remove the item"}}
+	}
+	return Repos.Carts.SetQuantity(ctx, userID, id, quantity)
+}
@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"ai-catalog/repository"
+	"context"
+	"sort"
+	"strings"
+)
+
+// Errors returned by the category mutations
+var (
+	ErrCategoryNotFound = repository.ErrCategoryNotFound
+	ErrCategoryNotEmpty = repository.ErrCategoryNotEmpty
+)
+
+// categoryChildren returns the subcategories of parentID, or the root
+// categories when it is nil, ordered by their name in locale
+func categoryChildren(ctx context.Context, parentID *int, locale string) ([]*Category, error) {
+	categories, err := Repos.Categories.Children(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeCategories(ctx, categories, locale); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(categories, func(i, j int) bool { return categories[i].Name < categories[j].Name })
+	return categories, nil
+}
+
+// validateCategoryParent checks that a new parent category exists
+func validateCategoryParent(ctx context.Context, problems *ValidationError, parentID *int) {
+	if parentID == nil {
+		return
+	}
+	if _, err := Repos.Categories.GetByID(ctx, *parentID); err != nil {
+		problems.Add("parentId", "category does not exist")
+	}
+}
+
+// CreateCategory validates the input and inserts a new category
+func CreateCategory(ctx context.Context, input CreateCategoryInput) (*Category, error) {
+	problems := NewValidationError()
+	category := repository.NewCategory{
+		Name:        strings.TrimSpace(input.Name),
+		Description: strings.TrimSpace(stringValue(input.Description)),
+		ImageURL:    strings.TrimSpace(stringValue(input.ImageURL)),
+		ParentID:    input.ParentID,
+	}
+	if category.Name == "" {
+		problems.Add("name", "is required")
+	} else if len([]rune(category.Name)) > 100 {
+		problems.Add("name", "must be at most 100 characters")
+	}
+	if len([]rune(category.ImageURL)) > 500 {
+		problems.Add("imageUrl", "must be at most 500 characters")
+	}
+	validateCategoryParent(ctx, problems, category.ParentID)
+	if err := problems.OrNil(); err != nil {
+		return nil, err
+	}
+
+	return Repos.Categories.Create(ctx, category)
+}
+
+// MoveCategory gives a category a new parent, or makes it a root when parentID
+// is nil. Moves that would put a category under itself are rejected.
+func MoveCategory(ctx context.Context, id int, parentID *int) (*Category, error) {
+	problems := NewValidationError()
+	validateCategoryParent(ctx, problems, parentID)
+	if err := problems.OrNil(); err != nil {
+		return nil, err
+	}
+
+	category, err := Repos.Categories.Move(ctx, id, parentID)
+	if err == repository.ErrCategoryCycle {
+		return nil, &ValidationError{Fields: map[string]string{"parentId": err.Error()}}
+	}
+	return category, err
+}
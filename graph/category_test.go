@@ -0,0 +1,36 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/model"
+	"testing"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+func TestMoveCategoryRejectsCycles(t *testing.T) {
+	store := useMemory(t)
+	admin := createUser(t, "admin@example.com", auth.RoleAdmin)
+	electronics := store.AddCategory(model.Category{Name: "Electronics"})
+	phones := store.AddCategory(model.Category{Name: "Phones", ParentID: &electronics.ID})
+	moveCategory := `mutation($id: Int!, $parent: Int) { moveCategory(id: $id, parentId: $parent) { id parentId } }`
+
+	errs := run(t, asUser(admin), moveCategory, map[string]interface{}{"id": electronics.ID, "parent": phones.ID}, nil)
+	if len(errs) != 1 || errorCode(errs[0]) != "BAD_USER_INPUT" {
+		t.Fatalf("moving a category under its child: got %v, want BAD_USER_INPUT", errs)
+	}
+	fields, _ := errs[0].(gqlerrors.FormattedError).Extensions["fields"].(map[string]string)
+	if fields["parentId"] == "" {
+		t.Errorf("fields = %v, want the problem reported on parentId", errs[0].(gqlerrors.FormattedError).Extensions["fields"])
+	}
+
+	var got struct{ MoveCategory struct{ ID, ParentID *int } }
+	execute(t, asUser(admin), moveCategory, map[string]interface{}{"id": phones.ID, "parent": nil}, &got)
+	if got.MoveCategory.ParentID != nil {
+		t.Errorf("parentId = %v after moving phones to the root, want null", *got.MoveCategory.ParentID)
+	}
+	execute(t, asUser(admin), moveCategory, map[string]interface{}{"id": electronics.ID, "parent": phones.ID}, &got)
+	if got.MoveCategory.ParentID == nil || *got.MoveCategory.ParentID != phones.ID {
+		t.Errorf("moveCategory = %+v, want electronics under phones once phones is a root", got.MoveCategory)
+	}
+}
@@ -0,0 +1,103 @@
+package graph
+
+import (
+	"ai-catalog/handlers"
+	"ai-catalog/jobs"
+	"ai-catalog/repository"
+	"context"
+	"fmt"
+	"log"
+)
+
+// validateDescriptionOptions checks the language and tone requested for generated copy
+func validateDescriptionOptions(problems *ValidationError, languageField, language, toneField, tone string) {
+	if _, ok := handlers.DescriptionLanguages[language]; !ok {
+		problems.Add(languageField, "unsupported language, use ar or en")
+	}
+	for _, t := range handlers.DescriptionTones {
+		if t == tone {
+			return
+		}
+	}
+	problems.Add(toneField, "unsupported tone")
+}
+
+// descriptionOptions returns the language and tone in input, falling back to the defaults
+func descriptionOptions(input map[string]interface{}, languageField, toneField string) (string, string) {
+	language, _ := input[languageField].(string)
+	tone, _ := input[toneField].(string)
+	return defaultDescriptionOptions(language, tone)
+}
+
+// defaultDescriptionOptions replaces an empty language or tone with the default
+func defaultDescriptionOptions(language, tone string) (string, string) {
+	if language == "" {
+		language = handlers.DefaultDescriptionLanguage
+	}
+	if tone == "" {
+		tone = handlers.DefaultDescriptionTone
+	}
+	return language, tone
+}
+
+// generateProductCopy asks the language model for marketing copy for a product
+func generateProductCopy(ctx context.Context, name string, categoryID int, language, tone string) (*handlers.ProductCopy, error) {
+	var category string
+	found, err := Repos.Categories.GetByID(ctx, categoryID)
+	if err == nil {
+		category = found.Name
+	} else if err != ErrCategoryNotFound {
+		return nil, err
+	}
+	return handlers.GenerateDescription(ctx, name, category, language, tone)
+}
+
+// queueGeneratedDescription queues generation of copy for a new product that
+// was created without a description, keeping a short description that was
+// given. Queueing is best effort: the product is kept without copy if it
+// fails, and the copy can be generated later with enqueueProductDescription.
+func queueGeneratedDescription(ctx context.Context, userID int, product *Product, input map[string]interface{}) {
+	if _, provided := input["description"]; provided {
+		return
+	}
+
+	language, tone := descriptionOptions(input, "descriptionLanguage", "descriptionTone")
+	_, keepShortDescription := input["shortDescription"]
+	_, err := jobs.Enqueue(ctx, JobGenerateDescription, generateDescriptionPayload{
+		ProductID:            product.ID,
+		Language:             language,
+		Tone:                 tone,
+		KeepShortDescription: keepShortDescription,
+	}, userID)
+	if err != nil {
+		log.Printf("failed to queue description generation for product %d: %v", product.ID, err)
+	}
+}
+
+// generateDescription replaces a product's description and, unless
+// keepShortDescription is set, its short description with freshly generated
+// copy. It calls the language model, so it runs in the job worker except for
+// regenerateProductDescription, which waits for the copy.
+func generateDescription(ctx context.Context, productID int, language, tone string, keepShortDescription bool) (*Product, error) {
+	problems := NewValidationError()
+	validateDescriptionOptions(problems, "language", language, "tone", tone)
+	if err := problems.OrNil(); err != nil {
+		return nil, err
+	}
+
+	product, err := Repos.Products.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	generated, err := generateProductCopy(ctx, product.Name, product.CategoryID, language, tone)
+	if err != nil {
+		return nil, fmt.Errorf("description generation failed: %v", err)
+	}
+
+	fields := repository.ProductFields{"description": generated.Description}
+	if !keepShortDescription {
+		fields["short_description"] = generated.ShortDescription
+	}
+	return Repos.Products.Update(ctx, productID, fields)
+}
@@ -0,0 +1,231 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/handlers"
+	"ai-catalog/model"
+	"ai-catalog/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// useFakeLLM answers language model requests with a fake client for the test
+func useFakeLLM(t *testing.T, responses map[string]string) *handlers.FakeLLMClient {
+	t.Helper()
+	client := &handlers.FakeLLMClient{Responses: responses}
+	handlers.SetLLMClient(client)
+	t.Cleanup(func() { handlers.SetLLMClient(nil) })
+	return client
+}
+
+// runJob runs a job handler with the payload encoded as the queue stores it
+func runJob(t *testing.T, handler func(context.Context, json.RawMessage) (interface{}, error), payload interface{}) (interface{}, error) {
+	t.Helper()
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return handler(context.Background(), data)
+}
+
+func TestDescriptionJobsReplaceTheProductCopy(t *testing.T) {
+	store := useMemory(t)
+	client := useFakeLLM(t, map[string]string{
+		handlers.TaskDescription: `{"shortDescription": "A fast phone.", "description": "A fast phone. It lasts all day."}`,
+	})
+	category := store.AddCategory(model.Category{Name: "Phones"})
+	product := store.AddProduct(model.Product{
+		Name: "Phone", ShortDescription: "Written by hand", Price: 100,
+		CategoryID: category.ID, IsActive: true, SKU: "PHONE-1",
+	})
+
+	tests := []struct {
+		name                 string
+		keepShortDescription bool
+		wantShortDescription string
+	}{
+		{"replace", false, "A fast phone."},
+		{"keep short description", true, "Written by hand"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Repos.Products.Update(context.Background(), product.ID, repository.ProductFields{"short_description": "Written by hand"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = runJob(t, runGenerateDescription, generateDescriptionPayload{
+				ProductID: product.ID, Language: "en", Tone: "friendly", KeepShortDescription: tt.keepShortDescription,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := Repos.Products.GetByID(context.Background(), product.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Description != "A fast phone. It lasts all day." || got.ShortDescription != tt.wantShortDescription {
+				t.Errorf("product copy = %q / %q, want the generated description and short description %q",
+					got.ShortDescription, got.Description, tt.wantShortDescription)
+			}
+		})
+	}
+
+	requests := client.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("the model got %d requests, want 2", len(requests))
+	}
+	prompt := requests[0].Messages[0].Content
+	for _, want := range []string{"English", "friendly", `"Phones"`} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt %q does not contain %s", prompt, want)
+		}
+	}
+}
+
+func TestDescriptionJobsFailWithoutChangingTheProduct(t *testing.T) {
+	store := useMemory(t)
+	client := useFakeLLM(t, nil)
+	product := store.AddProduct(model.Product{Name: "Phone", Description: "Old", Price: 100, IsActive: true, SKU: "PHONE-1"})
+
+	client.Err = errors.New("model unavailable")
+	if _, err := runJob(t, runGenerateDescription, generateDescriptionPayload{ProductID: product.ID, Language: "en", Tone: "friendly"}); err == nil {
+		t.Error("the job succeeded while the model was unavailable")
+	}
+	client.Err = nil
+	if _, err := runJob(t, runGenerateDescription, generateDescriptionPayload{ProductID: product.ID, Language: "fr", Tone: "friendly"}); err == nil {
+		t.Error("the job accepted an unsupported language")
+	}
+
+	got, err := Repos.Products.GetByID(context.Background(), product.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Description != "Old" {
+		t.Errorf("description = %q after failed jobs", got.Description)
+	}
+	if requests := client.Requests(); len(requests) != 1 {
+		t.Errorf("the model got %d requests, want only the one that failed", len(requests))
+	}
+}
+
+func TestTranslationsAreRememberedAndOverridable(t *testing.T) {
+	useMemory(t)
+	client := useFakeLLM(t, map[string]string{handlers.TaskTranslation: "هاتف"})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		result, err := runJob(t, runTranslateText, translateTextPayload{Text: " Phone ", From: "English", To: "arabic"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text := result.(map[string]interface{})["text"]; text != "هاتف" {
+			t.Errorf("translation %d = %v", i+1, text)
+		}
+	}
+	if requests := client.Requests(); len(requests) != 1 {
+		t.Errorf("the model got %d requests, want the repeated text served from the translation memory", len(requests))
+	}
+
+	if _, err := SetTranslationOverride(ctx, "Phone", "english", "Arabic", "جوال", 1); err != nil {
+		t.Fatal(err)
+	}
+	translated, err := translate(ctx, "Phone", "English", "Arabic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if translated != "جوال" {
+		t.Errorf("translation after the override = %q", translated)
+	}
+}
+
+func TestFillTranslationsOnlyTranslatesMissingText(t *testing.T) {
+	t.Setenv("CATALOG_DEFAULT_LOCALE", "en")
+	store := useMemory(t)
+	client := useFakeLLM(t, nil)
+	ctx := context.Background()
+	category := store.AddCategory(model.Category{Name: "Phones"})
+	product := store.AddProduct(model.Product{
+		Name: "Phone", Description: "A phone", Price: 100, CategoryID: category.ID, IsActive: true, SKU: "PHONE-1",
+	})
+	if _, err := SetProductTranslation(ctx, product.ID, "ar", "هاتف", "", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runJob(t, runFillTranslations, fillTranslationsPayload{Locale: "ar"}); err != nil {
+		t.Fatal(err)
+	}
+
+	translations, err := Repos.Translations.Products(ctx, "ar", []int{product.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[translation] Translate the following text from english to arabic: A phone"
+	if got := translations[product.ID]; got == nil || got.Name != "هاتف" || got.Description != want {
+		t.Errorf("product translation = %+v, want the name kept and the description translated", got)
+	}
+	categories, err := Repos.Translations.Categories(ctx, "ar", []int{category.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := categories[category.ID]; got == nil || got.Name != "[translation] Translate the following text from english to arabic: Phones" {
+		t.Errorf("category translation = %+v", got)
+	}
+	if requests := client.Requests(); len(requests) != 2 {
+		t.Errorf("the model got %d requests, want one for each missing text", len(requests))
+	}
+}
+
+func TestLanguageModelMutationsCanWaitOrQueue(t *testing.T) {
+	store := useMemory(t)
+	useFakeLLM(t, map[string]string{
+		handlers.TaskDescription: `{"shortDescription": "A fast phone.", "description": "A fast phone. It lasts all day."}`,
+		handlers.TaskTranslation: "هاتف",
+	})
+	product := store.AddProduct(model.Product{Name: "Phone", Price: 100, IsActive: true, SKU: "PHONE-1"})
+	admin := createUser(t, "admin@example.com", auth.RoleAdmin)
+	customer := createUser(t, "customer@example.com", auth.RoleCustomer)
+
+	regenerate := `mutation($id: Int!) {
+		regenerateProductDescription(productId: $id, language: "en") { shortDescription description }
+	}`
+	vars := map[string]interface{}{"id": product.ID}
+	if errs := run(t, asUser(customer), regenerate, vars, nil); len(errs) == 0 {
+		t.Error("a customer regenerated a product description")
+	}
+	var regenerated struct {
+		RegenerateProductDescription struct{ ShortDescription, Description string }
+	}
+	execute(t, asUser(admin), regenerate, vars, &regenerated)
+	if regenerated.RegenerateProductDescription.Description != "A fast phone. It lasts all day." {
+		t.Errorf("regenerateProductDescription = %+v", regenerated.RegenerateProductDescription)
+	}
+
+	// translateText stays public
+	var translated struct{ TranslateText string }
+	execute(t, context.Background(), `mutation { translateText(text: "Phone", from: "English", to: "Arabic") }`, nil, &translated)
+	if translated.TranslateText != "هاتف" {
+		t.Errorf("translateText = %q", translated.TranslateText)
+	}
+
+	// Any signed-in user can queue a translation and poll its job
+	var queued struct {
+		EnqueueTranslation struct {
+			ID     int
+			Status string
+		}
+	}
+	execute(t, asUser(customer), `mutation { enqueueTranslation(text: "Phone", from: "English", to: "Arabic") { id status } }`, nil, &queued)
+	if queued.EnqueueTranslation.Status != "pending" {
+		t.Fatalf("enqueueTranslation = %+v, want a pending job", queued.EnqueueTranslation)
+	}
+	var polled struct{ Job struct{ ID int } }
+	execute(t, asUser(customer), `query($id: Int!) { job(id: $id) { id } }`,
+		map[string]interface{}{"id": queued.EnqueueTranslation.ID}, &polled)
+	if polled.Job.ID != queued.EnqueueTranslation.ID {
+		t.Errorf("job = %+v, want the queued translation", polled.Job)
+	}
+}
@@ -0,0 +1,4 @@
+package graph
+
+// The schema is generated from schema.graphqls; edit the SDL and run go generate
+//go:generate go run ../cmd/schemagen -schema schema.graphqls -config schemagen.json -out schema_gen.go
@@ -0,0 +1,116 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+)
+
+// ForbiddenError is returned when the current user lacks the role an operation requires
+type ForbiddenError struct {
+	Operation    string
+	RequiredRole string
+}
+
+func (e *ForbiddenError) Error() string {
+	return fmt.Sprintf("forbidden: %s requires the %s role", e.Operation, e.RequiredRole)
+}
+
+// Extensions exposes a machine-readable error code to GraphQL clients
+func (e *ForbiddenError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":         "FORBIDDEN",
+		"requiredRole": e.RequiredRole,
+	}
+}
+
+// ScopeError is returned when a request authenticated with an API key calls an
+// operation the key is not scoped for. RequiredScope is empty for operations that
+// API keys can never use.
+type ScopeError struct {
+	Operation     string
+	RequiredScope string
+}
+
+func (e *ScopeError) Error() string {
+	if e.RequiredScope == "" {
+		return fmt.Sprintf("forbidden: %s cannot be used with an API key", e.Operation)
+	}
+	return fmt.Sprintf("forbidden: %s requires an API key with the %s scope", e.Operation, e.RequiredScope)
+}
+
+// Extensions exposes a machine-readable error code to GraphQL clients
+func (e *ScopeError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":          "INSUFFICIENT_SCOPE",
+		"requiredScope": e.RequiredScope,
+	}
+}
+
+// requestAPIKey returns the API key the request was authenticated with, if any
+func requestAPIKey(p graphql.ResolveParams) *auth.APIKey {
+	key, _ := p.Context.Value("apiKey").(*auth.APIKey)
+	return key
+}
+
+// RequireUser returns the authenticated user or an error if the request is anonymous.
+// Requests authenticated with an API key are rejected; use RequireScope for
+// operations that integrations may call.
+func RequireUser(p graphql.ResolveParams) (*User, error) {
+	user, ok := p.Context.Value("user").(*User)
+	if !ok {
+		return nil, fmt.Errorf("user not authenticated")
+	}
+	if requestAPIKey(p) != nil {
+		return nil, &ScopeError{Operation: p.Info.FieldName}
+	}
+	return user, nil
+}
+
+// RequireScope returns the authenticated user. Requests authenticated with an API
+// key must have been granted the scope; user sessions are not restricted by scopes.
+func RequireScope(p graphql.ResolveParams, scope string) (*User, error) {
+	user, ok := p.Context.Value("user").(*User)
+	if !ok {
+		return nil, fmt.Errorf("user not authenticated")
+	}
+	if key := requestAPIKey(p); key != nil && !key.HasScope(scope) {
+		return nil, &ScopeError{Operation: p.Info.FieldName, RequiredScope: scope}
+	}
+	return user, nil
+}
+
+// RequireRole returns the authenticated user if they have the given role and,
+// for API key requests, the given scope
+func RequireRole(p graphql.ResolveParams, role, scope string) (*User, error) {
+	user, err := RequireScope(p, scope)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role != role {
+		return nil, &ForbiddenError{Operation: p.Info.FieldName, RequiredRole: role}
+	}
+	return user, nil
+}
+
+// RequireAdmin returns the authenticated user if they are an admin and, for API key
+// requests, the key has the given scope. Admins who are required to use two-factor
+// authentication must have enrolled first.
+func RequireAdmin(p graphql.ResolveParams, scope string) (*User, error) {
+	user, err := RequireRole(p, auth.RoleAdmin, scope)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactorSetupRequired(user) {
+		return nil, &TwoFactorSetupRequiredError{Operation: p.Info.FieldName}
+	}
+	return user, nil
+}
+
+// hasAdminAccess reports whether RequireAdmin would accept the request, for
+// operations that admins may perform on other users' data
+func hasAdminAccess(p graphql.ResolveParams, scope string) bool {
+	_, err := RequireAdmin(p, scope)
+	return err == nil
+}
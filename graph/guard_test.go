@@ -0,0 +1,112 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/model"
+	"ai-catalog/repository"
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// errorCode returns the machine-readable code a GraphQL error carries in its extensions
+func errorCode(err error) string {
+	formatted, ok := err.(gqlerrors.FormattedError)
+	if !ok {
+		return ""
+	}
+	code, _ := formatted.Extensions["code"].(string)
+	return code
+}
+
+func TestCustomersCannotManageTheCatalogOrRoles(t *testing.T) {
+	store := useMemory(t)
+	category := store.AddCategory(model.Category{Name: "Lighting"})
+	product := store.AddProduct(model.Product{Name: "Lamp", Price: 30, CategoryID: category.ID, IsActive: true, StockQuantity: 5, SKU: "LAMP"})
+	customer := createUser(t, "customer@example.com", auth.RoleCustomer)
+
+	tests := []struct {
+		name      string
+		operation string
+		variables map[string]interface{}
+	}{
+		{"createProduct", `mutation($category: Int!) {
+			createProduct(input: {name: "Desk", price: 80, categoryId: $category, description: "A desk"}) { id }
+		}`, map[string]interface{}{"category": category.ID}},
+		{"updateProduct", `mutation($id: Int!) { updateProduct(id: $id, input: {price: 1}) { id } }`,
+			map[string]interface{}{"id": product.ID}},
+		{"deleteProduct", `mutation($id: Int!) { deleteProduct(id: $id) }`,
+			map[string]interface{}{"id": product.ID}},
+		{"updateUserRole", `mutation($id: Int!) { updateUserRole(userId: $id, role: "admin") { id } }`,
+			map[string]interface{}{"id": customer.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := run(t, asUser(customer), tt.operation, tt.variables, nil)
+			if len(errs) != 1 || errorCode(errs[0]) != "FORBIDDEN" {
+				t.Fatalf("got %v, want a FORBIDDEN error", errs)
+			}
+		})
+	}
+
+	// Nothing was changed on the customer's behalf
+	ctx := context.Background()
+	stored, err := Repos.Products.GetByID(ctx, product.ID)
+	if err != nil {
+		t.Fatalf("product after the forbidden mutations: %v", err)
+	}
+	if stored.Price != 30 {
+		t.Errorf("price = %v, want it unchanged", stored.Price)
+	}
+	user, err := Repos.Users.GetByID(ctx, customer.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Role != auth.RoleCustomer {
+		t.Errorf("role = %q, want the customer to stay a customer", user.Role)
+	}
+}
+
+func TestAdminsMustEnrollIn2FABeforeActingOnOthersData(t *testing.T) {
+	t.Setenv("ADMIN_REQUIRE_2FA", "true")
+	store := useMemory(t)
+	ctx := context.Background()
+	product := store.AddProduct(model.Product{Name: "Lamp", Price: 30, IsActive: true, StockQuantity: 5, SKU: "LAMP"})
+	customer := createUser(t, "customer@example.com", auth.RoleCustomer)
+	admin := createUser(t, "admin@example.com", auth.RoleAdmin)
+
+	if _, err := Repos.Carts.Add(ctx, customer.ID, product.ID, nil, 1); err != nil {
+		t.Fatal(err)
+	}
+	var placed struct{ CreateOrder struct{ ID int } }
+	execute(t, asUser(customer), placeOrder, nil, &placed)
+	review, err := Repos.Reviews.Create(ctx, repository.NewReview{UserID: customer.ID, ProductID: product.ID, Rating: 5, Comment: "Bright"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	getOrder := `query($id: Int!) { order(id: $id) { id } }`
+	deleteReview := `mutation($id: Int!) { deleteReview(id: $id) }`
+	orderVars := map[string]interface{}{"id": placed.CreateOrder.ID}
+	reviewVars := map[string]interface{}{"id": review.ID}
+
+	if errs := run(t, asUser(admin), getOrder, orderVars, nil); len(errs) == 0 {
+		t.Error("an admin without 2FA read a customer's order")
+	}
+	if errs := run(t, asUser(admin), deleteReview, reviewVars, nil); len(errs) == 0 {
+		t.Error("an admin without 2FA deleted a customer's review")
+	}
+
+	admin.TwoFactorEnabled = true
+	var got struct{ Order struct{ ID int } }
+	execute(t, asUser(admin), getOrder, orderVars, &got)
+	if got.Order.ID != placed.CreateOrder.ID {
+		t.Errorf("order = %+v, want the customer's order", got.Order)
+	}
+	var deleted struct{ DeleteReview bool }
+	execute(t, asUser(admin), deleteReview, reviewVars, &deleted)
+	if !deleted.DeleteReview {
+		t.Error("deleteReview returned false")
+	}
+}
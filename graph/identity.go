@@ -0,0 +1,138 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/oidc"
+	"ai-catalog/repository"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// OIDCLoginRedirect signs in the user behind a verified OIDC identity and returns
+// the frontend URL to redirect to. Tokens are passed in the URL fragment so they
+// never reach server logs; users with 2FA get a challenge token instead.
+func OIDCLoginRedirect(ctx context.Context, identity *oidc.Identity) (string, error) {
+	user, err := userForIdentity(ctx, identity)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := completeLogin(ctx, user)
+	if err != nil {
+		return "", err
+	}
+
+	userAgent, ipAddress := requestClient(ctx)
+	if response.TwoFactorRequired {
+		recordLoginAttempt(ctx, user.Email, ipAddress, userAgent, &user.ID, false, auth.LoginReasonTwoFactorPending)
+	} else {
+		recordLoginAttempt(ctx, user.Email, ipAddress, userAgent, &user.ID, true, auth.LoginReasonSuccess)
+	}
+
+	fragment := url.Values{}
+	if response.TwoFactorRequired {
+		fragment.Set("twoFactorRequired", "true")
+		fragment.Set("challengeToken", response.ChallengeToken)
+	} else {
+		fragment.Set("token", response.Token)
+		fragment.Set("refreshToken", response.RefreshToken)
+		if response.TwoFactorSetupRequired {
+			fragment.Set("twoFactorSetupRequired", "true")
+		}
+	}
+
+	baseURL := os.Getenv("APP_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+	return strings.TrimRight(baseURL, "/") + "/app#" + fragment.Encode(), nil
+}
+
+// userForIdentity finds the user linked to an identity. Unknown identities are
+// linked to the account with the same verified email address, or get a new account.
+func userForIdentity(ctx context.Context, identity *oidc.Identity) (*User, error) {
+	userID, err := Repos.Identities.Login(ctx, identity.Provider, identity.Subject, identity.Email)
+	if err == repository.ErrIdentityNotFound {
+		userID, err = linkIdentity(ctx, identity)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := Repos.Users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAccountStatus(user); err != nil {
+		return nil, &oidc.LoginError{Message: err.Error()}
+	}
+	return user, nil
+}
+
+// linkIdentity links a new identity to an existing account by verified email,
+// creating the account if there is none
+func linkIdentity(ctx context.Context, identity *oidc.Identity) (int, error) {
+	if identity.Email == "" || !identity.EmailVerified {
+		return 0, &oidc.LoginError{Message: fmt.Sprintf("your %s account does not have a verified email address", identity.Provider)}
+	}
+
+	account, err := identityAccount(identity)
+	if err != nil {
+		return 0, err
+	}
+	userID, err := Repos.Identities.Link(ctx, repository.NewIdentity{
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}, account)
+	if err == repository.ErrEmailNotVerified {
+		// Someone else may have registered the address; only its verified owner can link it
+		return 0, &oidc.LoginError{Message: fmt.Sprintf("an account with this email already exists, sign in with your password and verify your email address before using %s", identity.Provider)}
+	}
+	return userID, err
+}
+
+// identityAccount returns the account to create for a new identity. The account
+// gets an unusable random password; its owner can set one with requestPasswordReset.
+func identityAccount(identity *oidc.Identity) (repository.NewUser, error) {
+	randomPassword := make([]byte, 32)
+	if _, err := rand.Read(randomPassword); err != nil {
+		return repository.NewUser{}, err
+	}
+	passwordHash, err := auth.HashPassword(base64.RawURLEncoding.EncodeToString(randomPassword))
+	if err != nil {
+		return repository.NewUser{}, fmt.Errorf("failed to hash password: %v", err)
+	}
+
+	firstName, lastName := identity.GivenName, identity.FamilyName
+	if firstName == "" && lastName == "" {
+		firstName, lastName, _ = strings.Cut(identity.Name, " ")
+	}
+	if firstName == "" {
+		firstName = strings.Split(identity.Email, "@")[0]
+	}
+
+	return repository.NewUser{
+		Email:         identity.Email,
+		PasswordHash:  passwordHash,
+		FirstName:     firstName,
+		LastName:      lastName,
+		EmailVerified: true,
+	}, nil
+}
+
+// GetUserIdentities returns the external identities linked to a user
+func GetUserIdentities(ctx context.Context, userID int) ([]*UserIdentity, error) {
+	return Repos.Identities.ListForUser(ctx, userID)
+}
+
+// UnlinkIdentity removes a provider's identity from a user and reports whether
+// one was linked
+func UnlinkIdentity(ctx context.Context, userID int, provider string) (bool, error) {
+	return Repos.Identities.Unlink(ctx, userID, provider)
+}
@@ -0,0 +1,134 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/oidc"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// verifiedIdentity returns an identity from the "mock" provider with a verified email
+func verifiedIdentity(subject, email string) *oidc.Identity {
+	return &oidc.Identity{Provider: "mock", Subject: subject, Email: email, EmailVerified: true, GivenName: "Ada", FamilyName: "Lovelace"}
+}
+
+func TestIdentitiesAreLinkedByVerifiedEmail(t *testing.T) {
+	useMemory(t)
+	ctx := context.Background()
+	existing := createUser(t, "ada@example.com", auth.RoleCustomer)
+	if err := Repos.Users.MarkEmailVerified(ctx, existing.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := userForIdentity(ctx, verifiedIdentity("ada-1", "Ada@Example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != existing.ID {
+		t.Errorf("signed in user %d, want the existing account %d", user.ID, existing.ID)
+	}
+
+	// The identity stays linked when its email changes at the provider
+	again, err := userForIdentity(ctx, verifiedIdentity("ada-1", "ada@elsewhere.example"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != existing.ID {
+		t.Errorf("second login signed in user %d, want %d", again.ID, existing.ID)
+	}
+}
+
+func TestNewIdentitiesGetAVerifiedAccount(t *testing.T) {
+	useMemory(t)
+	user, err := userForIdentity(context.Background(), verifiedIdentity("grace-1", "grace@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != "grace@example.com" || !user.EmailVerified || user.FirstName != "Ada" || user.LastName != "Lovelace" {
+		t.Errorf("created account = %+v", user)
+	}
+}
+
+func TestIdentitiesAreNotLinkedToUnverifiedAccounts(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity *oidc.Identity
+		wantErr  string
+	}{
+		{"unverified account", verifiedIdentity("mallory-1", "victim@example.com"), "verify your email address"},
+		{"unverified identity", &oidc.Identity{Provider: "mock", Subject: "mallory-2", Email: "victim@example.com"}, "does not have a verified email address"},
+		{"identity without email", &oidc.Identity{Provider: "mock", Subject: "mallory-3", EmailVerified: true}, "does not have a verified email address"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useMemory(t)
+			victim := createUser(t, "victim@example.com", auth.RoleCustomer)
+
+			_, err := userForIdentity(context.Background(), tt.identity)
+			var loginErr *oidc.LoginError
+			if !errors.As(err, &loginErr) || !strings.Contains(loginErr.Message, tt.wantErr) {
+				t.Fatalf("userForIdentity: got %v, want a login error containing %q", err, tt.wantErr)
+			}
+			identities, err := GetUserIdentities(context.Background(), victim.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(identities) != 0 {
+				t.Errorf("identities linked to the unverified account: %+v", identities)
+			}
+		})
+	}
+}
+
+func TestOIDCLoginIsRecordedOnceItCompletes(t *testing.T) {
+	useMemory(t)
+	ctx := context.Background()
+	user := createUser(t, "ada@example.com", auth.RoleCustomer)
+	if err := Repos.Users.MarkEmailVerified(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := Repos.TwoFactor.Enable(ctx, user.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// A key that can only verify makes issuing the challenge fail
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.UseKeys(auth.NewRSAKey("verify-only", nil, &private.PublicKey))
+	if _, err := OIDCLoginRedirect(ctx, verifiedIdentity("ada-1", "ada@example.com")); err == nil {
+		t.Fatal("OIDCLoginRedirect succeeded without a signing key")
+	}
+	attempts, err := ListLoginAttempts(ctx, "ada@example.com", "", nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attempts) != 0 {
+		t.Fatalf("recorded %+v for a login that failed", attempts)
+	}
+
+	key, err := auth.NewHMACKey("test", []byte("test-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.UseKeys(key)
+	redirect, err := OIDCLoginRedirect(ctx, verifiedIdentity("ada-1", "ada@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(redirect, "twoFactorRequired=true") || !strings.Contains(redirect, "challengeToken=") {
+		t.Errorf("redirect = %q, want a 2FA challenge", redirect)
+	}
+	attempts, err = ListLoginAttempts(ctx, "ada@example.com", "", nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attempts) != 1 || attempts[0].Success || attempts[0].Reason != auth.LoginReasonTwoFactorPending {
+		t.Errorf("recorded attempts = %+v, want one pending 2FA attempt", attempts)
+	}
+}
@@ -0,0 +1,119 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/jobs"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Background job types
+const (
+	JobGenerateDescription = "generate_description"
+	JobTranslateText       = "translate_text"
+	JobSendPasswordReset   = "send_password_reset"
+)
+
+// generateDescriptionPayload is the payload of a JobGenerateDescription job
+type generateDescriptionPayload struct {
+	ProductID int    `json:"productId"`
+	Language  string `json:"language"`
+	Tone      string `json:"tone"`
+	// KeepShortDescription leaves a short description given at creation alone
+	KeepShortDescription bool `json:"keepShortDescription,omitempty"`
+}
+
+// translateTextPayload is the payload of a JobTranslateText job
+type translateTextPayload struct {
+	Text string `json:"text"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// RegisterJobHandlers registers the handlers for the job types enqueued by the API
+func RegisterJobHandlers(worker *jobs.Worker) {
+	worker.Handle(JobGenerateDescription, runGenerateDescription)
+	worker.Handle(JobTranslateText, runTranslateText)
+	worker.Handle(JobFillTranslations, runFillTranslations)
+	worker.Handle(JobSendPasswordReset, runSendPasswordReset)
+}
+
+func runGenerateDescription(ctx context.Context, data json.RawMessage) (interface{}, error) {
+	var payload generateDescriptionPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, jobs.Permanent(err)
+	}
+
+	product, err := generateDescription(ctx, payload.ProductID, payload.Language, payload.Tone, payload.KeepShortDescription)
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) || err == ErrProductNotFound {
+		return nil, jobs.Permanent(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"productId":        product.ID,
+		"shortDescription": product.ShortDescription,
+		"description":      product.Description,
+	}, nil
+}
+
+func runTranslateText(ctx context.Context, data json.RawMessage) (interface{}, error) {
+	var payload translateTextPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, jobs.Permanent(err)
+	}
+
+	translated, err := translate(ctx, payload.Text, payload.From, payload.To)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"text": translated}, nil
+}
+
+// GetJobForUser returns a job if the user enqueued it or is an admin
+func GetJobForUser(ctx context.Context, id int, user *User) (*jobs.Job, error) {
+	job, err := jobs.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role != auth.RoleAdmin && (job.CreatedBy == nil || *job.CreatedBy != user.ID) {
+		return nil, jobs.ErrNotFound
+	}
+	return job, nil
+}
+
+// jobResult renders a job's JSON result as a string for GraphQL
+func jobResult(job *jobs.Job) *string {
+	if job.Result == nil {
+		return nil
+	}
+	result := string(job.Result)
+	return &result
+}
+
+// enqueueDescriptionJob validates the options and queues description generation for a product
+func enqueueDescriptionJob(ctx context.Context, userID, productID int, language, tone string) (*jobs.Job, error) {
+	problems := NewValidationError()
+	validateDescriptionOptions(problems, "language", language, "tone", tone)
+	if err := problems.OrNil(); err != nil {
+		return nil, err
+	}
+	if _, err := Repos.Products.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	job, err := jobs.Enqueue(ctx, JobGenerateDescription, generateDescriptionPayload{
+		ProductID: productID,
+		Language:  language,
+		Tone:      tone,
+	}, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue job: %v", err)
+	}
+	return job, nil
+}
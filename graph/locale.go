@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// localeNames maps the catalog locales to the language names used when translating
+var localeNames = map[string]string{
+	"ar": "Arabic",
+	"en": "English",
+}
+
+// IsSupportedLocale reports whether the catalog can be served in a locale
+func IsSupportedLocale(locale string) bool {
+	_, ok := localeNames[locale]
+	return ok
+}
+
+// DefaultLocale is the language of the name and description columns of the
+// products and categories tables, set by CATALOG_DEFAULT_LOCALE
+func DefaultLocale() string {
+	if locale := os.Getenv("CATALOG_DEFAULT_LOCALE"); IsSupportedLocale(locale) {
+		return locale
+	}
+	return "en"
+}
+
+// ParseAcceptLanguage returns the supported locale the client prefers most in an
+// Accept-Language header, or an empty string if it accepts none of them
+func ParseAcceptLanguage(header string) string {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		// Only the primary language subtag matters: ar-SA is served as ar
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if q > 0 && IsSupportedLocale(primary) {
+			candidates = append(candidates, candidate{locale: primary, q: q})
+		}
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].locale
+}
+
+// requestLocale returns the locale to serve catalog text in: the locale argument
+// if given, else the one negotiated from Accept-Language, else the default
+func requestLocale(p graphql.ResolveParams) (string, error) {
+	if locale, ok := p.Args["locale"].(string); ok && locale != "" {
+		locale = strings.ToLower(locale)
+		if !IsSupportedLocale(locale) {
+			return "", &ValidationError{Fields: map[string]string{"locale": "unsupported locale, use ar or en"}}
+		}
+		return locale, nil
+	}
+	if locale, ok := p.Context.Value("locale").(string); ok && locale != "" {
+		return locale, nil
+	}
+	return DefaultLocale(), nil
+}
@@ -0,0 +1,98 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/repository"
+	"context"
+	"log"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// loginWithPassword authenticates a login attempt with throttling: repeated failures
+// for an email or client IP are slowed down and then temporarily locked out, and
+// every attempt is recorded in login_attempts for auditing.
+func loginWithPassword(p graphql.ResolveParams, email, password string) (*User, error) {
+	userAgent, ipAddress := requestClient(p.Context)
+
+	delay, err := checkLoginAllowed(p.Context, email, ipAddress)
+	if err != nil {
+		if _, locked := err.(*auth.LoginLockedError); locked {
+			recordLoginAttempt(p.Context, email, ipAddress, userAgent, nil, false, auth.LoginReasonLocked)
+		}
+		return nil, err
+	}
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-p.Context.Done():
+			timer.Stop()
+			return nil, p.Context.Err()
+		}
+	}
+
+	user, err := AuthenticateUser(p.Context, email, password)
+	switch {
+	case err == auth.ErrInvalidCredentials:
+		recordLoginAttempt(p.Context, email, ipAddress, userAgent, nil, false, auth.LoginReasonInvalidCredentials)
+		return nil, err
+	case err != nil && user != nil:
+		// Correct password for a suspended account
+		recordLoginAttempt(p.Context, email, ipAddress, userAgent, &user.ID, false, auth.LoginReasonAccountInactive)
+		return nil, err
+	case err != nil:
+		return nil, err
+	}
+
+	// With 2FA the login only succeeds once verifyTwoFactor accepts a code
+	if user.TwoFactorEnabled {
+		recordLoginAttempt(p.Context, email, ipAddress, userAgent, &user.ID, false, auth.LoginReasonTwoFactorPending)
+		return user, nil
+	}
+
+	recordLoginAttempt(p.Context, email, ipAddress, userAgent, &user.ID, true, auth.LoginReasonSuccess)
+	return user, nil
+}
+
+// checkLoginAllowed returns how long to delay a login attempt for the given
+// email and client IP, or a LoginLockedError while either is locked out
+func checkLoginAllowed(ctx context.Context, email, ipAddress string) (time.Duration, error) {
+	accountFailures, err := Repos.LoginAttempts.AccountFailures(ctx, auth.NormalizeEmail(email), auth.LockoutReasons, auth.LoginFailureWindow)
+	if err != nil {
+		return 0, err
+	}
+	ipFailures, err := Repos.LoginAttempts.IPFailures(ctx, ipAddress, auth.LockoutReasons, auth.LoginFailureWindow)
+	if err != nil {
+		return 0, err
+	}
+	return auth.CheckLoginAllowed(accountFailures, ipFailures)
+}
+
+// recordLoginAttempt stores a login attempt, logging rather than failing on errors
+func recordLoginAttempt(ctx context.Context, email, ipAddress, userAgent string, userID *int, success bool, reason string) {
+	err := Repos.LoginAttempts.Record(ctx, repository.NewLoginAttempt{
+		Email:     auth.NormalizeEmail(email),
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		UserID:    userID,
+		Success:   success,
+		Reason:    reason,
+	})
+	if err != nil {
+		log.Printf("failed to record login attempt: %v", err)
+	}
+}
+
+// ListLoginAttempts returns the most recent login attempts, newest first. Empty
+// filters and a nil success match every attempt.
+func ListLoginAttempts(ctx context.Context, email, ipAddress string, success *bool, limit int) ([]*LoginAttempt, error) {
+	return Repos.LoginAttempts.List(ctx, repository.LoginAttemptFilter{
+		Email:     auth.NormalizeEmail(email),
+		IPAddress: ipAddress,
+		Success:   success,
+		Limit:     limit,
+	})
+}
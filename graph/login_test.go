@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// clientContext returns the context AuthMiddleware gives a request from
+// remoteAddr carrying the given X-Forwarded-For header
+func clientContext(remoteAddr, forwardedFor string) context.Context {
+	r := httptest.NewRequest("POST", "/graphql", nil)
+	r.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		r.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	ctx := context.WithValue(context.Background(), "userAgent", "test")
+	return context.WithValue(ctx, "clientIP", auth.ClientIP(r))
+}
+
+func TestSpoofedForwardedForDoesNotResetIPLockout(t *testing.T) {
+	useMemory(t)
+	auth.SetTrustedProxies(nil)
+
+	// An attacker rotates the header and the email on every attempt
+	for i := 0; i < auth.MaxIPLoginFailures; i++ {
+		ctx := clientContext("203.0.113.9:4000", fmt.Sprintf("10.0.0.%d", i))
+		_, ipAddress := requestClient(ctx)
+		recordLoginAttempt(ctx, fmt.Sprintf("victim%d@example.com", i), ipAddress, "test", nil, false, auth.LoginReasonInvalidCredentials)
+	}
+
+	ctx := clientContext("203.0.113.9:4000", "192.0.2.200")
+	errs := run(t, ctx, `mutation { login(input: {email: "fresh@example.com", password: "guess"}) { token } }`, nil, nil)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "too many failed login attempts") {
+		t.Fatalf("login with a spoofed X-Forwarded-For: got %v, want the IP lockout", errs)
+	}
+
+	attempts, err := ListLoginAttempts(context.Background(), "fresh@example.com", "", nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attempts) != 1 || attempts[0].IPAddress != "203.0.113.9" || attempts[0].Reason != auth.LoginReasonLocked {
+		t.Errorf("recorded attempts = %+v, want one locked attempt from the connecting address", attempts)
+	}
+}
+
+func TestIPLockoutFollowsTheClientBehindATrustedProxy(t *testing.T) {
+	useMemory(t)
+	networks, err := auth.ParseTrustedProxies("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.SetTrustedProxies(networks)
+	defer auth.SetTrustedProxies(nil)
+
+	// Clients forge entries to the left of the one the proxy appends
+	for i := 0; i < auth.MaxIPLoginFailures; i++ {
+		ctx := clientContext("10.1.1.1:4000", fmt.Sprintf("192.0.2.%d, 203.0.113.9", i))
+		_, ipAddress := requestClient(ctx)
+		recordLoginAttempt(ctx, fmt.Sprintf("victim%d@example.com", i), ipAddress, "test", nil, false, auth.LoginReasonInvalidCredentials)
+	}
+
+	locked := clientContext("10.1.1.1:4000", "198.51.100.77, 203.0.113.9")
+	errs := run(t, locked, `mutation { login(input: {email: "fresh@example.com", password: "guess"}) { token } }`, nil, nil)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "too many failed login attempts") {
+		t.Fatalf("login from the locked client: got %v, want the IP lockout", errs)
+	}
+
+	if _, err := checkLoginAllowed(context.Background(), "fresh@example.com", "203.0.113.10"); err != nil {
+		t.Errorf("another client behind the same proxy is locked out: %v", err)
+	}
+}
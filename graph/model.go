@@ -1,150 +1,110 @@
-package graph
-
-import (
-	"time"
-)
-
-// User represents a user in the system
-type User struct {
-	ID           int       `json:"id"`
-	Email        string    `json:"email"`
-	FirstName    string    `json:"firstName"`
-	LastName     string    `json:"lastName"`
-	Phone        string    `json:"phone"`
-	Address      string    `json:"address"`
-	City         string    `json:"city"`
-	Country      string    `json:"country"`
-	CreatedAt    time.Time `json:"createdAt"`
-	UpdatedAt    time.Time `json:"updatedAt"`
-}
-
-// Category represents a product category
-type Category struct {
-	ID          int       `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	ImageURL    string    `json:"imageUrl"`
-	ParentID    *int      `json:"parentId"`
-	CreatedAt   time.Time `json:"createdAt"`
-}
-
-// Product represents a product in the catalog
-type Product struct {
-	ID                int       `json:"id"`
-	Name              string    `json:"name"`
-	Price             float64   `json:"price"`
-	OriginalPrice     *float64  `json:"originalPrice"`
-	CategoryID        int       `json:"categoryId"`
-	Category          *Category `json:"category"`
-	Description       string    `json:"description"`
-	ShortDescription  string    `json:"shortDescription"`
-	ImageURL          string    `json:"imageUrl"`
-	StockQuantity     int       `json:"stockQuantity"`
-	SKU               string    `json:"sku"`
-	Weight            *float64  `json:"weight"`
-	Dimensions        string    `json:"dimensions"`
-	IsActive          bool      `json:"isActive"`
-	IsFeatured        bool      `json:"isFeatured"`
-	CreatedAt         time.Time `json:"createdAt"`
-	UpdatedAt         time.Time `json:"updatedAt"`
-	AverageRating     float64   `json:"averageRating"`
-	ReviewCount       int       `json:"reviewCount"`
-	IsInWishlist      bool      `json:"isInWishlist"`
-	IsLiked           bool      `json:"isLiked"`
-}
-
-// CartItem represents an item in the shopping cart
-type CartItem struct {
-	ID        int       `json:"id"`
-	UserID    int       `json:"userId"`
-	ProductID int       `json:"productId"`
-	Product   *Product  `json:"product"`
-	Quantity  int       `json:"quantity"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
-}
-
-// WishlistItem represents an item in the wishlist
-type WishlistItem struct {
-	ID        int       `json:"id"`
-	UserID    int       `json:"userId"`
-	ProductID int       `json:"productId"`
-	Product   *Product  `json:"product"`
-	CreatedAt time.Time `json:"createdAt"`
-}
-
-// Order represents a customer order
-type Order struct {
-	ID             int       `json:"id"`
-	UserID         int       `json:"userId"`
-	User           *User     `json:"user"`
-	OrderNumber    string    `json:"orderNumber"`
-	Status         string    `json:"status"`
-	TotalAmount    float64   `json:"totalAmount"`
-	ShippingAddress string   `json:"shippingAddress"`
-	ShippingCity   string    `json:"shippingCity"`
-	ShippingCountry string   `json:"shippingCountry"`
-	ShippingPhone  string    `json:"shippingPhone"`
-	PaymentMethod  string    `json:"paymentMethod"`
-	PaymentStatus  string    `json:"paymentStatus"`
-	Notes          string    `json:"notes"`
-	CreatedAt      time.Time `json:"createdAt"`
-	UpdatedAt      time.Time `json:"updatedAt"`
-	Items          []*OrderItem `json:"items"`
-}
-
-// OrderItem represents an item in an order
-type OrderItem struct {
-	ID           int       `json:"id"`
-	OrderID      int       `json:"orderId"`
-	ProductID    int       `json:"productId"`
-	ProductName  string    `json:"productName"`
-	ProductPrice float64   `json:"productPrice"`
-	Quantity     int       `json:"quantity"`
-	TotalPrice   float64   `json:"totalPrice"`
-	CreatedAt    time.Time `json:"createdAt"`
-}
-
-// Review represents a product review
-type Review struct {
-	ID                int       `json:"id"`
-	UserID            int       `json:"userId"`
-	User              *User     `json:"user"`
-	ProductID         int       `json:"productId"`
-	Rating            int       `json:"rating"`
-	Title             string    `json:"title"`
-	Comment           string    `json:"comment"`
-	IsVerifiedPurchase bool     `json:"isVerifiedPurchase"`
-	CreatedAt         time.Time `json:"createdAt"`
-	UpdatedAt         time.Time `json:"updatedAt"`
-	LikeCount         int       `json:"likeCount"`
-	IsLiked           bool      `json:"isLiked"`
-}
-
-// AuthResponse represents authentication response
-type AuthResponse struct {
-	User  *User  `json:"user"`
-	Token string `json:"token"`
-}
-
-// CartSummary represents cart summary information
-type CartSummary struct {
-	Items      []*CartItem `json:"items"`
-	TotalItems int         `json:"totalItems"`
-	TotalPrice float64     `json:"totalPrice"`
-}
-
-// SearchResult represents search results
-type SearchResult struct {
-	Products   []*Product `json:"products"`
-	Categories []*Category `json:"categories"`
-	Total      int        `json:"total"`
-}
-
-// Pagination represents pagination information
-type Pagination struct {
-	Page       int `json:"page"`
-	Limit      int `json:"limit"`
-	Total      int `json:"total"`
-	TotalPages int `json:"totalPages"`
-} 
\ No newline at end of file
+package graph
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/model"
+	"time"
+)
+
+// Domain entities are defined in the model package so the repository package can
+// use them without importing graph
+type (
+	User           = model.User
+	Category       = model.Category
+	Product        = model.Product
+	ProductVariant = model.ProductVariant
+	SelectedOption = model.SelectedOption
+	CartItem       = model.CartItem
+	WishlistItem   = model.WishlistItem
+	Order          = model.Order
+	OrderItem      = model.OrderItem
+	Review         = model.Review
+
+	UserIdentity           = model.UserIdentity
+	LoginAttempt           = model.LoginAttempt
+	TranslationMemoryEntry = model.TranslationMemoryEntry
+)
+
+// Connections page through listings with cursors and are built by the repositories
+type (
+	PageInfo           = model.PageInfo
+	ProductEdge        = model.ProductEdge
+	ProductConnection  = model.ProductConnection
+	OrderEdge          = model.OrderEdge
+	OrderConnection    = model.OrderConnection
+	ReviewEdge         = model.ReviewEdge
+	ReviewConnection   = model.ReviewConnection
+	WishlistItemEdge   = model.WishlistItemEdge
+	WishlistConnection = model.WishlistConnection
+)
+
+// Facets count search results by the refinements a storefront offers
+type (
+	ProductFacets   = model.ProductFacets
+	CategoryFacet   = model.CategoryFacet
+	PriceRangeFacet = model.PriceRangeFacet
+	RatingFacet     = model.RatingFacet
+)
+
+// AuthResponse represents authentication response. When the account has 2FA
+// enabled, login returns TwoFactorRequired and a ChallengeToken instead of tokens.
+type AuthResponse struct {
+	User                   *User     `json:"user"`
+	Token                  string    `json:"token"`
+	RefreshToken           string    `json:"refreshToken"`
+	ExpiresAt              time.Time `json:"expiresAt"`
+	TwoFactorRequired      bool      `json:"twoFactorRequired"`
+	ChallengeToken         string    `json:"challengeToken"`
+	TwoFactorSetupRequired bool      `json:"twoFactorSetupRequired"`
+}
+
+// TwoFactorSetup is returned when a user starts enrolling an authenticator app
+type TwoFactorSetup struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+// CartSummary represents cart summary information
+type CartSummary struct {
+	Items      []*CartItem `json:"items"`
+	TotalItems int         `json:"totalItems"`
+	TotalPrice float64     `json:"totalPrice"`
+}
+
+// SearchResult represents search results
+type SearchResult struct {
+	Products   []*Product     `json:"products"`
+	Categories []*Category    `json:"categories"`
+	Total      int            `json:"total"`
+	Facets     *ProductFacets `json:"facets"`
+}
+
+// ProductOption is an option type of a product sold in variants, such as Size,
+// with the values its variants have
+type ProductOption struct {
+	Name   string                `json:"name"`
+	Values []*ProductOptionValue `json:"values"`
+}
+
+// ProductOptionValue is a value of a product option, such as a size
+type ProductOptionValue struct {
+	Value     string `json:"value"`
+	Available bool   `json:"available"`
+}
+
+// SearchSuggestions completes a search as it is typed
+type SearchSuggestions struct {
+	Queries    []string    `json:"queries"`
+	Products   []*Product  `json:"products"`
+	Categories []*Category `json:"categories"`
+}
+
+// SearchStat is how often customers searched for a query
+type SearchStat = model.SearchStat
+
+// CreateApiKeyPayload is returned when an API key is created. Key is the only
+// time the raw key is shown.
+type CreateApiKeyPayload struct {
+	APIKey *auth.APIKey `json:"apiKey"`
+	Key    string       `json:"key"`
+}
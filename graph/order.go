@@ -0,0 +1,28 @@
+package graph
+
+import (
+	"ai-catalog/repository"
+	"context"
+)
+
+// ErrOrderNotFound is returned when an order does not exist or belongs to another user
+var ErrOrderNotFound = repository.ErrOrderNotFound
+
+// GetOrderForUser returns an order with its items if the user placed it or,
+// with asAdmin, any order. asAdmin must come from RequireAdmin so admins who
+// still have to enroll in 2FA only see their own orders.
+func GetOrderForUser(ctx context.Context, id int, user *User, asAdmin bool) (*Order, error) {
+	order, err := Repos.Orders.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !asAdmin && order.UserID != user.ID {
+		return nil, ErrOrderNotFound
+	}
+
+	order.Items, err = Repos.Orders.Items(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
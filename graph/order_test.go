@@ -0,0 +1,196 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/model"
+	"ai-catalog/repository"
+	"context"
+	"strings"
+	"testing"
+)
+
+const placeOrder = `mutation {
+	createOrder(input: {shippingAddress: "1 Main St", shippingCity: "Riyadh", shippingPhone: "0500000000", paymentMethod: "cod"}) { id totalAmount }
+}`
+
+// variantProduct stores an active product sold in one variant and returns both
+func variantProduct(t *testing.T, store *repository.Memory, sku string, price float64, stock int) (*Product, *ProductVariant) {
+	t.Helper()
+	product := store.AddProduct(model.Product{Name: "Shoe", Price: 1, IsActive: true, SKU: sku})
+	variant, err := Repos.Variants.Create(context.Background(), repository.NewVariant{
+		ProductID: product.ID, SKU: sku + "-42", Price: price, StockQuantity: stock,
+		Options: []*model.SelectedOption{{Name: "Size", Value: "42"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return product, variant
+}
+
+func TestOrdersCannotExceedTheStock(t *testing.T) {
+	store := useMemory(t)
+	product := store.AddProduct(model.Product{Name: "Lamp", Price: 30, IsActive: true, StockQuantity: 2, SKU: "LAMP"})
+	customer := createUser(t, "customer@example.com", auth.RoleCustomer)
+	ctx := context.Background()
+	if _, err := Repos.Carts.Add(ctx, customer.ID, product.ID, nil, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	// Someone else buys one after the lamps were added to this cart
+	if _, err := Repos.Products.Update(ctx, product.ID, repository.ProductFields{"stock_quantity": 1}); err != nil {
+		t.Fatal(err)
+	}
+	errs := run(t, asUser(customer), placeOrder, nil, nil)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), repository.ErrInsufficientStock.Error()) {
+		t.Fatalf("createOrder beyond the stock: got %v, want %v", errs, repository.ErrInsufficientStock)
+	}
+	stored, err := Repos.Products.GetByID(ctx, product.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.StockQuantity != 1 {
+		t.Errorf("stock after the rejected order = %d, want 1", stored.StockQuantity)
+	}
+
+	var placed struct{ CreateOrder struct{ TotalAmount float64 } }
+	execute(t, asUser(customer), `mutation($id: Int!) { updateCartItem(id: $id, quantity: 1) { quantity } }`,
+		map[string]interface{}{"id": cartItemID(t, customer)}, nil)
+	execute(t, asUser(customer), placeOrder, nil, &placed)
+	if placed.CreateOrder.TotalAmount != 30 {
+		t.Errorf("order total = %v, want 30", placed.CreateOrder.TotalAmount)
+	}
+	if stored, _ := Repos.Products.GetByID(ctx, product.ID); stored.StockQuantity != 0 {
+		t.Errorf("stock after the order = %d, want 0", stored.StockQuantity)
+	}
+}
+
+func TestACartIsOnlyOrderedOnce(t *testing.T) {
+	store := useMemory(t)
+	product := store.AddProduct(model.Product{Name: "Lamp", Price: 30, IsActive: true, StockQuantity: 5, SKU: "LAMP"})
+	customer := createUser(t, "customer@example.com", auth.RoleCustomer)
+	ctx := context.Background()
+	if _, err := Repos.Carts.Add(ctx, customer.ID, product.ID, nil, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	// A double click sends the same checkout twice at once
+	results := make(chan []error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { results <- run(t, asUser(customer), placeOrder, nil, nil) }()
+	}
+	placed, empty := 0, 0
+	for i := 0; i < 2; i++ {
+		switch errs := <-results; {
+		case len(errs) == 0:
+			placed++
+		case len(errs) == 1 && strings.Contains(errs[0].Error(), repository.ErrCartEmpty.Error()):
+			empty++
+		default:
+			t.Errorf("unexpected errors: %v", errs)
+		}
+	}
+	if placed != 1 || empty != 1 {
+		t.Errorf("placed %d orders and found the cart empty %d times, want one of each", placed, empty)
+	}
+	if stored, _ := Repos.Products.GetByID(ctx, product.ID); stored.StockQuantity != 3 {
+		t.Errorf("stock after the checkouts = %d, want 3", stored.StockQuantity)
+	}
+}
+
+// cartItemID returns the ID of the only item in the user's cart
+func cartItemID(t *testing.T, user *User) int {
+	t.Helper()
+	items, err := Repos.Carts.List(context.Background(), user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("cart has %d items, want 1", len(items))
+	}
+	return items[0].ID
+}
+
+func TestDeactivatedItemsCannotBeOrdered(t *testing.T) {
+	tests := []struct {
+		name       string
+		deactivate func(ctx context.Context, product *Product, variant *ProductVariant) error
+	}{
+		{"product", func(ctx context.Context, product *Product, variant *ProductVariant) error {
+			_, err := Repos.Products.SetActive(ctx, product.ID, false)
+			return err
+		}},
+		{"variant", func(ctx context.Context, product *Product, variant *ProductVariant) error {
+			inactive := false
+			_, err := Repos.Variants.Update(ctx, variant.ID, repository.VariantUpdate{IsActive: &inactive})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := useMemory(t)
+			product, variant := variantProduct(t, store, "SHOE", 80, 5)
+			customer := createUser(t, "customer@example.com", auth.RoleCustomer)
+			ctx := context.Background()
+			if _, err := Repos.Carts.Add(ctx, customer.ID, product.ID, &variant.ID, 1); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.deactivate(ctx, product, variant); err != nil {
+				t.Fatal(err)
+			}
+
+			var cart struct {
+				Cart struct {
+					Items      []struct{ Available bool }
+					TotalItems int
+					TotalPrice float64
+				}
+			}
+			execute(t, asUser(customer), `{ cart { items { available } totalItems totalPrice } }`, nil, &cart)
+			if len(cart.Cart.Items) != 1 || cart.Cart.Items[0].Available || cart.Cart.TotalItems != 0 || cart.Cart.TotalPrice != 0 {
+				t.Errorf("cart = %+v, want the item unavailable and left out of the totals", cart.Cart)
+			}
+
+			if errs := run(t, asUser(customer), `mutation($id: Int!) { updateCartItem(id: $id, quantity: 2) { quantity } }`,
+				map[string]interface{}{"id": cartItemID(t, customer)}, nil); len(errs) == 0 {
+				t.Error("the quantity of an unavailable item was changed")
+			}
+			errs := run(t, asUser(customer), placeOrder, nil, nil)
+			if len(errs) != 1 || !strings.Contains(errs[0].Error(), repository.ErrCartUnavailable.Error()) {
+				t.Errorf("createOrder with an unavailable item: got %v, want %v", errs, repository.ErrCartUnavailable)
+			}
+			if stored, _ := Repos.Variants.GetByID(ctx, variant.ID); stored.StockQuantity != 5 {
+				t.Errorf("variant stock after the rejected order = %d, want 5", stored.StockQuantity)
+			}
+		})
+	}
+}
+
+func TestPriceFiltersUseVariantPrices(t *testing.T) {
+	store := useMemory(t)
+	product, _ := variantProduct(t, store, "SHOE", 120, 5)
+	store.AddProduct(model.Product{Name: "Sock", Price: 120, IsActive: true, StockQuantity: 5, SKU: "SOCK"})
+	store.AddProduct(model.Product{Name: "Shoe horn", Price: 20, IsActive: true, StockQuantity: 5, SKU: "HORN"})
+
+	var got struct {
+		SearchProducts struct {
+			Products []struct{ Name string }
+			Facets   struct {
+				PriceRanges []struct {
+					Min   float64
+					Count int
+				}
+			}
+		}
+	}
+	execute(t, context.Background(), `{
+		searchProducts(query: "shoe", minPrice: 100, maxPrice: 200) { products { name } facets { priceRanges { min count } } }
+	}`, nil, &got)
+	if len(got.SearchProducts.Products) != 1 || got.SearchProducts.Products[0].Name != product.Name {
+		t.Errorf("products from 100 to 200 = %+v, want the shoe at its variant price", got.SearchProducts.Products)
+	}
+	// The price facet leaves out the price filter, so the shoe horn is counted too
+	ranges := got.SearchProducts.Facets.PriceRanges
+	if len(ranges) != 2 || ranges[0].Min != 0 || ranges[0].Count != 1 || ranges[1].Min != 100 || ranges[1].Count != 1 {
+		t.Errorf("price facets = %+v, want the shoe horn below 50 and the shoe counted from 100", ranges)
+	}
+}
@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"ai-catalog/repository"
+	"fmt"
+	"strings"
+)
+
+// Page sizes of the connection fields
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// ErrInvalidCursor is returned for after and before cursors the server did not issue
+var ErrInvalidCursor = repository.ErrInvalidCursor
+
+// pageArgs validates the Relay pagination arguments of a connection field.
+// first pages forward from after and last pages backward from before; without
+// either the page holds defaultPageSize items.
+func pageArgs(first *int, after *string, last *int, before *string) (repository.Page, error) {
+	problems := NewValidationError()
+	page := repository.Page{After: stringValue(after), Before: stringValue(before)}
+
+	checkSize := func(field string, size int) int {
+		if size < 1 || size > maxPageSize {
+			problems.Add(field, fmt.Sprintf("must be between 1 and %d", maxPageSize))
+		}
+		return size
+	}
+	switch {
+	case first != nil && last != nil:
+		problems.Add("last", "cannot be combined with first")
+	case last != nil:
+		page.Last = checkSize("last", *last)
+	case first != nil:
+		page.First = checkSize("first", *first)
+	case page.Before != "":
+		page.Last = defaultPageSize
+	default:
+		page.First = defaultPageSize
+	}
+
+	if page.After != "" && page.Before != "" {
+		problems.Add("before", "cannot be combined with after")
+	} else if page.Last > 0 && page.After != "" {
+		problems.Add("after", "use before with last")
+	} else if page.First > 0 && page.Before != "" {
+		problems.Add("before", "use after with first")
+	}
+	return page, problems.OrNil()
+}
+
+// productSort validates the sort argument of productsConnection
+func productSort(sort string) (repository.ProductSort, error) {
+	names := make([]string, len(repository.ProductSorts))
+	for i, valid := range repository.ProductSorts {
+		if repository.ProductSort(sort) == valid {
+			return valid, nil
+		}
+		names[i] = string(valid)
+	}
+	return "", &ValidationError{Fields: map[string]string{"sort": "unsupported sort, use " + strings.Join(names, ", ")}}
+}
+
+// connectionProducts returns the products on a page of a connection
+func connectionProducts(connection *ProductConnection) []*Product {
+	products := make([]*Product, len(connection.Edges))
+	for i, edge := range connection.Edges {
+		products[i] = edge.Node
+	}
+	return products
+}
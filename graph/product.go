@@ -0,0 +1,238 @@
+package graph
+
+import (
+	"ai-catalog/repository"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ErrProductNotFound is returned when a product does not exist
+var ErrProductNotFound = repository.ErrProductNotFound
+
+// productField maps a product input field to its column and validation rules
+type productField struct {
+	input     string
+	column    string
+	nullable  bool
+	maxLength int
+}
+
+// productFields lists the product input fields in column order
+var productFields = []productField{
+	{input: "name", column: "name", maxLength: 255},
+	{input: "price", column: "price"},
+	{input: "originalPrice", column: "original_price", nullable: true},
+	{input: "categoryId", column: "category_id"},
+	{input: "description", column: "description", nullable: true},
+	{input: "shortDescription", column: "short_description", nullable: true, maxLength: 500},
+	{input: "imageUrl", column: "image_url", nullable: true, maxLength: 500},
+	{input: "stockQuantity", column: "stock_quantity"},
+	{input: "sku", column: "sku", nullable: true, maxLength: 100},
+	{input: "weight", column: "weight", nullable: true},
+	{input: "dimensions", column: "dimensions", nullable: true, maxLength: 100},
+	{input: "isActive", column: "is_active"},
+	{input: "isFeatured", column: "is_featured"},
+}
+
+// productValues validates product input and returns the column values for the
+// fields that were provided. productID is 0 when creating a product.
+func productValues(ctx context.Context, input map[string]interface{}, productID int) (repository.ProductFields, error) {
+	problems := NewValidationError()
+	fields := repository.ProductFields{}
+
+	for _, field := range productFields {
+		value, provided := input[field.input]
+		if !provided {
+			continue
+		}
+
+		if s, ok := value.(string); ok {
+			s = strings.TrimSpace(s)
+			if field.maxLength > 0 && len([]rune(s)) > field.maxLength {
+				problems.Add(field.input, fmt.Sprintf("must be at most %d characters", field.maxLength))
+			}
+			value = s
+			// Empty optional strings are stored as NULL so blank SKUs do not collide
+			if s == "" && field.nullable {
+				value = nil
+			}
+		}
+
+		if value == nil {
+			if !field.nullable {
+				problems.Add(field.input, "must not be null")
+			}
+		} else {
+			validateProductValue(ctx, problems, field.input, value, productID)
+		}
+
+		fields[field.column] = value
+	}
+
+	if productID == 0 {
+		language, tone := descriptionOptions(input, "descriptionLanguage", "descriptionTone")
+		validateDescriptionOptions(problems, "descriptionLanguage", language, "descriptionTone", tone)
+	}
+
+	if err := problems.OrNil(); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// validateProductValue checks the rules for a single non-null product field
+func validateProductValue(ctx context.Context, problems *ValidationError, field string, value interface{}, productID int) {
+	switch field {
+	case "name":
+		if value.(string) == "" {
+			problems.Add(field, "is required")
+		}
+	case "price", "originalPrice", "weight":
+		if value.(float64) < 0 {
+			problems.Add(field, "must not be negative")
+		}
+	case "stockQuantity":
+		if value.(int) < 0 {
+			problems.Add(field, "must not be negative")
+		}
+		// The stock of a product with variants follows the variants' stock
+		if productID != 0 {
+			variants, err := Repos.Variants.ListForProduct(ctx, productID)
+			if err == nil && len(variants) > 0 {
+				problems.Add(field, "is the total stock of the product's variants, update the variants instead")
+			}
+		}
+	case "categoryId":
+		if _, err := Repos.Categories.GetByID(ctx, value.(int)); err != nil {
+			problems.Add(field, "category does not exist")
+		}
+	case "sku":
+		taken, err := Repos.Products.SKUTaken(ctx, value.(string), productID)
+		if err != nil || taken {
+			problems.Add(field, repository.ErrSKUTaken.Error())
+		}
+	}
+}
+
+// skuTakenError reports a SKU taken by another request between validation and
+// write as a validation error on the sku field
+func skuTakenError(err error) error {
+	if err == repository.ErrSKUTaken {
+		return &ValidationError{Fields: map[string]string{"sku": err.Error()}}
+	}
+	return err
+}
+
+// CreateProduct validates the input and inserts a new product. When no
+// description is given, generating one is queued as a job for userID.
+func CreateProduct(ctx context.Context, userID int, input map[string]interface{}) (*Product, error) {
+	fields, err := productValues(ctx, input, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := Repos.Products.Create(ctx, fields)
+	if err != nil {
+		return nil, skuTakenError(err)
+	}
+	queueGeneratedDescription(ctx, userID, product, input)
+	return product, nil
+}
+
+// UpdateProduct validates the input and updates the provided fields of a product
+func UpdateProduct(ctx context.Context, id int, input map[string]interface{}) (*Product, error) {
+	fields, err := productValues(ctx, input, id)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := Repos.Products.Update(ctx, id, fields)
+	if err != nil {
+		return nil, skuTakenError(err)
+	}
+	return product, nil
+}
+
+// searchResultLimit caps the products returned by SearchProducts
+const searchResultLimit = 50
+
+// validateProductFilter checks the filter arguments of the product listings
+func validateProductFilter(filter repository.ProductFilter) error {
+	problems := NewValidationError()
+	if filter.MinRating != nil && (*filter.MinRating < 1 || *filter.MinRating > 5) {
+		problems.Add("minRating", "must be between 1 and 5")
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		problems.Add("maxPrice", "must not be less than minPrice")
+	}
+	return problems.OrNil()
+}
+
+// SearchProducts runs a full-text search of the active products matching
+// filter, most relevant first, and finds the categories whose name contains
+// query. Total and the facets count every matching product, including those
+// beyond the returned page.
+func SearchProducts(ctx context.Context, query string, filter repository.ProductFilter, locale string) (*SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, &ValidationError{Fields: map[string]string{"query": "is required"}}
+	}
+	if err := validateProductFilter(filter); err != nil {
+		return nil, err
+	}
+
+	filter.Search, filter.ActiveOnly, filter.FeaturedFirst, filter.Limit = query, true, true, searchResultLimit
+	result := &SearchResult{}
+	var err error
+	result.Facets, err = Repos.Products.Facets(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	result.Total = result.Facets.Total
+	recordSearch(ctx, query, result.Total, locale)
+	if err := facetCategories(ctx, result.Facets, locale); err != nil {
+		return nil, err
+	}
+
+	result.Products, err = Repos.Products.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeProducts(ctx, result.Products, locale); err != nil {
+		return nil, err
+	}
+
+	result.Categories, err = Repos.Categories.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeCategories(ctx, result.Categories, locale); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// facetCategories fills in the categories of the category facets in locale
+func facetCategories(ctx context.Context, facets *ProductFacets, locale string) error {
+	categories, err := Repos.Categories.List(ctx)
+	if err != nil {
+		return err
+	}
+	byID := make(map[int]*Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	counted := make([]*Category, 0, len(facets.Categories))
+	kept := facets.Categories[:0]
+	for _, facet := range facets.Categories {
+		// A category deleted since the count was taken has nothing to show
+		if facet.Category = byID[facet.CategoryID]; facet.Category != nil {
+			counted = append(counted, facet.Category)
+			kept = append(kept, facet)
+		}
+	}
+	facets.Categories = kept
+	return localizeCategories(ctx, counted, locale)
+}
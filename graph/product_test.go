@@ -0,0 +1,104 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/model"
+	"ai-catalog/repository"
+	"reflect"
+	"testing"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// validationFields returns the field problems a GraphQL error reports, or nil
+// when it is not a validation error
+func validationFields(err error) map[string]string {
+	formatted, ok := err.(gqlerrors.FormattedError)
+	if !ok || formatted.Extensions["code"] != "BAD_USER_INPUT" {
+		return nil
+	}
+	fields, _ := formatted.Extensions["fields"].(map[string]string)
+	return fields
+}
+
+func TestProductInputIsValidatedPerField(t *testing.T) {
+	store := useMemory(t)
+	admin := createUser(t, "admin@example.com", auth.RoleAdmin)
+	category := store.AddCategory(model.Category{Name: "Lighting"})
+	lamp := store.AddProduct(model.Product{Name: "Lamp", Price: 30, CategoryID: category.ID, IsActive: true, StockQuantity: 5, SKU: "LAMP"})
+	desk := store.AddProduct(model.Product{Name: "Desk", Price: 80, CategoryID: category.ID, IsActive: true, StockQuantity: 2, SKU: "DESK"})
+	skuTaken := repository.ErrSKUTaken.Error()
+
+	create := `mutation($input: CreateProductInput!) { createProduct(input: $input) { id } }`
+	update := `mutation($id: Int!, $input: UpdateProductInput!) { updateProduct(id: $id, input: $input) { id } }`
+	newProduct := func(fields map[string]interface{}) map[string]interface{} {
+		input := map[string]interface{}{"name": "Chair", "price": 45.0, "categoryId": category.ID, "description": "A chair"}
+		for name, value := range fields {
+			input[name] = value
+		}
+		return map[string]interface{}{"input": input}
+	}
+	changes := func(id int, input map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"id": id, "input": input}
+	}
+
+	tests := []struct {
+		name       string
+		operation  string
+		variables  map[string]interface{}
+		wantFields map[string]string
+	}{
+		{"create with a duplicate SKU", create, newProduct(map[string]interface{}{"sku": "LAMP"}),
+			map[string]string{"sku": skuTaken}},
+		{"create with a padded duplicate SKU", create, newProduct(map[string]interface{}{"sku": "  LAMP "}),
+			map[string]string{"sku": skuTaken}},
+		{"create with a negative price", create, newProduct(map[string]interface{}{"price": -1.0}),
+			map[string]string{"price": "must not be negative"}},
+		{"create with negative stock", create, newProduct(map[string]interface{}{"stockQuantity": -3}),
+			map[string]string{"stockQuantity": "must not be negative"}},
+		{"create with several problems", create, newProduct(map[string]interface{}{"name": " ", "originalPrice": -5.0, "weight": -1.0, "categoryId": 999}),
+			map[string]string{
+				"name":          "is required",
+				"originalPrice": "must not be negative",
+				"weight":        "must not be negative",
+				"categoryId":    "category does not exist",
+			}},
+		{"create with a blank SKU", create, newProduct(map[string]interface{}{"sku": ""}), nil},
+		{"create with another blank SKU", create, newProduct(map[string]interface{}{"sku": ""}), nil},
+		{"update to another product's SKU", update, changes(desk.ID, map[string]interface{}{"sku": "LAMP"}),
+			map[string]string{"sku": skuTaken}},
+		{"update keeping the product's own SKU", update, changes(lamp.ID, map[string]interface{}{"sku": "LAMP", "price": 32.0}), nil},
+		{"update to a negative price", update, changes(lamp.ID, map[string]interface{}{"price": -0.01}),
+			map[string]string{"price": "must not be negative"}},
+		{"update to negative stock", update, changes(lamp.ID, map[string]interface{}{"stockQuantity": -1}),
+			map[string]string{"stockQuantity": "must not be negative"}},
+		{"update clearing the name", update, changes(lamp.ID, map[string]interface{}{"name": "", "price": -2.0}),
+			map[string]string{"name": "is required", "price": "must not be negative"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := run(t, asUser(admin), tt.operation, tt.variables, nil)
+			if tt.wantFields == nil {
+				if len(errs) > 0 {
+					t.Fatalf("unexpected errors: %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("got %v, want one validation error", errs)
+			}
+			if got := validationFields(errs[0]); !reflect.DeepEqual(got, tt.wantFields) {
+				t.Errorf("fields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+
+	// Rejected updates leave the product as it was
+	stored, err := Repos.Products.GetByID(asUser(admin), lamp.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Price != 32 || stored.StockQuantity != 5 || stored.SKU != "LAMP" {
+		t.Errorf("lamp = %+v, want only the valid update applied", stored)
+	}
+}
@@ -1,960 +1,1341 @@
-package graph
-
-import (
-	"ai-catalog/auth"
-	"ai-catalog/handlers"
-	"database/sql"
-	"fmt"
-	"strconv"
-	"time"
-
-	"github.com/graphql-go/graphql"
-	_ "github.com/lib/pq"
-)
-
-// Global database connection
-var DB *sql.DB
-
-// SetDB sets the database connection for the resolver
-func SetDB(db *sql.DB) {
-	DB = db
-}
-
-// GraphQL Types
-var UserType = graphql.NewObject(graphql.ObjectConfig{
-	Name: "User",
-	Fields: graphql.Fields{
-		"id":        &graphql.Field{Type: graphql.Int},
-		"email":     &graphql.Field{Type: graphql.String},
-		"firstName": &graphql.Field{Type: graphql.String},
-		"lastName":  &graphql.Field{Type: graphql.String},
-		"phone":     &graphql.Field{Type: graphql.String},
-		"address":   &graphql.Field{Type: graphql.String},
-		"city":      &graphql.Field{Type: graphql.String},
-		"country":   &graphql.Field{Type: graphql.String},
-		"createdAt": &graphql.Field{Type: graphql.String},
-		"updatedAt": &graphql.Field{Type: graphql.String},
-	},
-})
-
-var CategoryType = graphql.NewObject(graphql.ObjectConfig{
-	Name: "Category",
-	Fields: graphql.Fields{
-		"id":          &graphql.Field{Type: graphql.Int},
-		"name":        &graphql.Field{Type: graphql.String},
-		"description": &graphql.Field{Type: graphql.String},
-		"imageUrl":    &graphql.Field{Type: graphql.String},
-		"parentId":    &graphql.Field{Type: graphql.Int},
-		"createdAt":   &graphql.Field{Type: graphql.String},
-	},
-})
-
-var ProductType = graphql.NewObject(graphql.ObjectConfig{
-	Name: "Product",
-	Fields: graphql.Fields{
-		"id":               &graphql.Field{Type: graphql.Int},
-		"name":             &graphql.Field{Type: graphql.String},
-		"price":            &graphql.Field{Type: graphql.Float},
-		"originalPrice":    &graphql.Field{Type: graphql.Float},
-		"categoryId":       &graphql.Field{Type: graphql.Int},
-		"category":         &graphql.Field{Type: CategoryType},
-		"description":      &graphql.Field{Type: graphql.String},
-		"shortDescription": &graphql.Field{Type: graphql.String},
-		"imageUrl":         &graphql.Field{Type: graphql.String},
-		"stockQuantity":    &graphql.Field{Type: graphql.Int},
-		"sku":              &graphql.Field{Type: graphql.String},
-		"weight":           &graphql.Field{Type: graphql.Float},
-		"dimensions":       &graphql.Field{Type: graphql.String},
-		"isActive":         &graphql.Field{Type: graphql.Boolean},
-		"isFeatured":       &graphql.Field{Type: graphql.Boolean},
-		"createdAt":        &graphql.Field{Type: graphql.String},
-		"updatedAt":        &graphql.Field{Type: graphql.String},
-		"averageRating":    &graphql.Field{Type: graphql.Float},
-		"reviewCount":      &graphql.Field{Type: graphql.Int},
-		"isInWishlist":     &graphql.Field{Type: graphql.Boolean},
-		"isLiked":          &graphql.Field{Type: graphql.Boolean},
-	},
-})
-
-var CartItemType = graphql.NewObject(graphql.ObjectConfig{
-	Name: "CartItem",
-	Fields: graphql.Fields{
-		"id":        &graphql.Field{Type: graphql.Int},
-		"userId":    &graphql.Field{Type: graphql.Int},
-		"productId": &graphql.Field{Type: graphql.Int},
-		"product":   &graphql.Field{Type: ProductType},
-		"quantity":  &graphql.Field{Type: graphql.Int},
-		"createdAt": &graphql.Field{Type: graphql.String},
-		"updatedAt": &graphql.Field{Type: graphql.String},
-	},
-})
-
-var CartSummaryType = graphql.NewObject(graphql.ObjectConfig{
-	Name: "CartSummary",
-	Fields: graphql.Fields{
-		"items":      &graphql.Field{Type: graphql.NewList(CartItemType)},
-		"totalItems": &graphql.Field{Type: graphql.Int},
-		"totalPrice": &graphql.Field{Type: graphql.Float},
-	},
-})
-
-var WishlistItemType = graphql.NewObject(graphql.ObjectConfig{
-	Name: "WishlistItem",
-	Fields: graphql.Fields{
-		"id":        &graphql.Field{Type: graphql.Int},
-		"userId":    &graphql.Field{Type: graphql.Int},
-		"productId": &graphql.Field{Type: graphql.Int},
-		"product":   &graphql.Field{Type: ProductType},
-		"createdAt": &graphql.Field{Type: graphql.String},
-	},
-})
-
-var OrderItemType = graphql.NewObject(graphql.ObjectConfig{
-	Name: "OrderItem",
-	Fields: graphql.Fields{
-		"id":           &graphql.Field{Type: graphql.Int},
-		"orderId":      &graphql.Field{Type: graphql.Int},
-		"productId":    &graphql.Field{Type: graphql.Int},
-		"productName":  &graphql.Field{Type: graphql.String},
-		"productPrice": &graphql.Field{Type: graphql.Float},
-		"quantity":     &graphql.Field{Type: graphql.Int},
-		"totalPrice":   &graphql.Field{Type: graphql.Float},
-		"createdAt":    &graphql.Field{Type: graphql.String},
-	},
-})
-
-var OrderType = graphql.NewObject(graphql.ObjectConfig{
-	Name: "Order",
-	Fields: graphql.Fields{
-		"id":              &graphql.Field{Type: graphql.Int},
-		"userId":          &graphql.Field{Type: graphql.Int},
-		"user":            &graphql.Field{Type: UserType},
-		"orderNumber":     &graphql.Field{Type: graphql.String},
-		"status":          &graphql.Field{Type: graphql.String},
-		"totalAmount":     &graphql.Field{Type: graphql.Float},
-		"shippingAddress": &graphql.Field{Type: graphql.String},
-		"shippingCity":    &graphql.Field{Type: graphql.String},
-		"shippingCountry": &graphql.Field{Type: graphql.String},
-		"shippingPhone":   &graphql.Field{Type: graphql.String},
-		"paymentMethod":   &graphql.Field{Type: graphql.String},
-		"paymentStatus":   &graphql.Field{Type: graphql.String},
-		"notes":           &graphql.Field{Type: graphql.String},
-		"createdAt":       &graphql.Field{Type: graphql.String},
-		"updatedAt":       &graphql.Field{Type: graphql.String},
-		"items":           &graphql.Field{Type: graphql.NewList(OrderItemType)},
-	},
-})
-
-var ReviewType = graphql.NewObject(graphql.ObjectConfig{
-	Name: "Review",
-	Fields: graphql.Fields{
-		"id":                &graphql.Field{Type: graphql.Int},
-		"userId":            &graphql.Field{Type: graphql.Int},
-		"user":              &graphql.Field{Type: UserType},
-		"productId":         &graphql.Field{Type: graphql.Int},
-		"rating":            &graphql.Field{Type: graphql.Int},
-		"title":             &graphql.Field{Type: graphql.String},
-		"comment":           &graphql.Field{Type: graphql.String},
-		"isVerifiedPurchase": &graphql.Field{Type: graphql.Boolean},
-		"createdAt":         &graphql.Field{Type: graphql.String},
-		"updatedAt":         &graphql.Field{Type: graphql.String},
-		"likeCount":         &graphql.Field{Type: graphql.Int},
-		"isLiked":           &graphql.Field{Type: graphql.Boolean},
-	},
-})
-
-var AuthResponseType = graphql.NewObject(graphql.ObjectConfig{
-	Name: "AuthResponse",
-	Fields: graphql.Fields{
-		"user":  &graphql.Field{Type: UserType},
-		"token": &graphql.Field{Type: graphql.String},
-	},
-})
-
-// Root Query
-var RootQuery = graphql.NewObject(graphql.ObjectConfig{
-	Name: "Query",
-	Fields: graphql.Fields{
-		"me": &graphql.Field{
-			Type: UserType,
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				if user, ok := p.Context.Value("user").(*User); ok {
-					return user, nil
-				}
-				return nil, nil
-			},
-		},
-		"categories": &graphql.Field{
-			Type: graphql.NewList(CategoryType),
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				rows, err := DB.Query("SELECT id, name, description, image_url, parent_id, created_at FROM categories ORDER BY name")
-				if err != nil {
-					return nil, err
-				}
-				defer rows.Close()
-
-				var categories []Category
-				for rows.Next() {
-					var c Category
-					err := rows.Scan(&c.ID, &c.Name, &c.Description, &c.ImageURL, &c.ParentID, &c.CreatedAt)
-					if err != nil {
-						return nil, err
-					}
-					categories = append(categories, c)
-				}
-				return categories, nil
-			},
-		},
-		"products": &graphql.Field{
-			Type: graphql.NewList(ProductType),
-			Args: graphql.FieldConfigArgument{
-				"categoryId":  &graphql.ArgumentConfig{Type: graphql.Int},
-				"search":      &graphql.ArgumentConfig{Type: graphql.String},
-				"minPrice":    &graphql.ArgumentConfig{Type: graphql.Float},
-				"maxPrice":    &graphql.ArgumentConfig{Type: graphql.Float},
-				"isFeatured":  &graphql.ArgumentConfig{Type: graphql.Boolean},
-				"page":        &graphql.ArgumentConfig{Type: graphql.Int},
-				"limit":       &graphql.ArgumentConfig{Type: graphql.Int},
-			},
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				query := "SELECT id, name, price, original_price, category_id, description, short_description, image_url, stock_quantity, sku, weight, dimensions, is_active, is_featured, created_at, updated_at FROM products WHERE is_active = true"
-				var args []interface{}
-				argCount := 1
-
-				if categoryID, ok := p.Args["categoryId"].(int); ok {
-					query += fmt.Sprintf(" AND category_id = $%d", argCount)
-					args = append(args, categoryID)
-					argCount++
-				}
-
-				if search, ok := p.Args["search"].(string); ok && search != "" {
-					query += fmt.Sprintf(" AND (name ILIKE $%d OR description ILIKE $%d)", argCount, argCount)
-					args = append(args, "%"+search+"%")
-					argCount++
-				}
-
-				if minPrice, ok := p.Args["minPrice"].(float64); ok {
-					query += fmt.Sprintf(" AND price >= $%d", argCount)
-					args = append(args, minPrice)
-					argCount++
-				}
-
-				if maxPrice, ok := p.Args["maxPrice"].(float64); ok {
-					query += fmt.Sprintf(" AND price <= $%d", argCount)
-					args = append(args, maxPrice)
-					argCount++
-				}
-
-				if isFeatured, ok := p.Args["isFeatured"].(bool); ok {
-					query += fmt.Sprintf(" AND is_featured = $%d", argCount)
-					args = append(args, isFeatured)
-					argCount++
-				}
-
-				query += " ORDER BY created_at DESC"
-
-				// Add pagination
-				if limit, ok := p.Args["limit"].(int); ok && limit > 0 {
-					query += fmt.Sprintf(" LIMIT $%d", argCount)
-					args = append(args, limit)
-					argCount++
-				}
-
-				rows, err := DB.Query(query, args...)
-				if err != nil {
-					return nil, err
-				}
-				defer rows.Close()
-
-				var products []Product
-				for rows.Next() {
-					var p Product
-					err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.OriginalPrice, &p.CategoryID, &p.Description, &p.ShortDescription, &p.ImageURL, &p.StockQuantity, &p.SKU, &p.Weight, &p.Dimensions, &p.IsActive, &p.IsFeatured, &p.CreatedAt, &p.UpdatedAt)
-					if err != nil {
-						return nil, err
-					}
-					products = append(products, p)
-				}
-				return products, nil
-			},
-		},
-		"product": &graphql.Field{
-			Type: ProductType,
-			Args: graphql.FieldConfigArgument{
-				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
-			},
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				id := p.Args["id"].(int)
-				var product Product
-				err := DB.QueryRow("SELECT id, name, price, original_price, category_id, description, short_description, image_url, stock_quantity, sku, weight, dimensions, is_active, is_featured, created_at, updated_at FROM products WHERE id = $1", id).Scan(&product.ID, &product.Name, &product.Price, &product.OriginalPrice, &product.CategoryID, &product.Description, &product.ShortDescription, &product.ImageURL, &product.StockQuantity, &product.SKU, &product.Weight, &product.Dimensions, &product.IsActive, &product.IsFeatured, &product.CreatedAt, &product.UpdatedAt)
-				if err != nil {
-					return nil, err
-				}
-				return product, nil
-			},
-		},
-		"featuredProducts": &graphql.Field{
-			Type: graphql.NewList(ProductType),
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				rows, err := DB.Query("SELECT id, name, price, original_price, category_id, description, short_description, image_url, stock_quantity, sku, weight, dimensions, is_active, is_featured, created_at, updated_at FROM products WHERE is_active = true AND is_featured = true ORDER BY created_at DESC LIMIT 10")
-				if err != nil {
-					return nil, err
-				}
-				defer rows.Close()
-
-				var products []Product
-				for rows.Next() {
-					var p Product
-					err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.OriginalPrice, &p.CategoryID, &p.Description, &p.ShortDescription, &p.ImageURL, &p.StockQuantity, &p.SKU, &p.Weight, &p.Dimensions, &p.IsActive, &p.IsFeatured, &p.CreatedAt, &p.UpdatedAt)
-					if err != nil {
-						return nil, err
-					}
-					products = append(products, p)
-				}
-				return products, nil
-			},
-		},
-		"cart": &graphql.Field{
-			Type: CartSummaryType,
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				user, ok := p.Context.Value("user").(*User)
-				if !ok {
-					return nil, fmt.Errorf("user not authenticated")
-				}
-
-				rows, err := DB.Query(`
-					SELECT c.id, c.user_id, c.product_id, c.quantity, c.created_at, c.updated_at,
-						   p.id, p.name, p.price, p.original_price, p.category_id, p.description, p.short_description, p.image_url, p.stock_quantity, p.sku, p.weight, p.dimensions, p.is_active, p.is_featured, p.created_at, p.updated_at
-					FROM cart c
-					JOIN products p ON c.product_id = p.id
-					WHERE c.user_id = $1
-				`, user.ID)
-				if err != nil {
-					return nil, err
-				}
-				defer rows.Close()
-
-				var items []*CartItem
-				var totalPrice float64
-				var totalItems int
-				for rows.Next() {
-					var item CartItem
-					var product Product
-					err := rows.Scan(
-						&item.ID, &item.UserID, &item.ProductID, &item.Quantity, &item.CreatedAt, &item.UpdatedAt,
-						&product.ID, &product.Name, &product.Price, &product.OriginalPrice, &product.CategoryID, &product.Description, &product.ShortDescription, &product.ImageURL, &product.StockQuantity, &product.SKU, &product.Weight, &product.Dimensions, &product.IsActive, &product.IsFeatured, &product.CreatedAt, &product.UpdatedAt,
-					)
-					if err != nil {
-						return nil, err
-					}
-					item.Product = &product
-					items = append(items, &item)
-					totalPrice += product.Price * float64(item.Quantity)
-					totalItems += item.Quantity
-				}
-
-				return CartSummary{
-					Items:      items,
-					TotalItems: totalItems,
-					TotalPrice: totalPrice,
-				}, nil
-			},
-		},
-		"wishlist": &graphql.Field{
-			Type: graphql.NewList(WishlistItemType),
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				user, ok := p.Context.Value("user").(*User)
-				if !ok {
-					return nil, fmt.Errorf("user not authenticated")
-				}
-
-				rows, err := DB.Query(`
-					SELECT w.id, w.user_id, w.product_id, w.created_at,
-						   p.id, p.name, p.price, p.original_price, p.category_id, p.description, p.short_description, p.image_url, p.stock_quantity, p.sku, p.weight, p.dimensions, p.is_active, p.is_featured, p.created_at, p.updated_at
-					FROM wishlist w
-					JOIN products p ON w.product_id = p.id
-					WHERE w.user_id = $1
-					ORDER BY w.created_at DESC
-				`, user.ID)
-				if err != nil {
-					return nil, err
-				}
-				defer rows.Close()
-
-				var items []WishlistItem
-				for rows.Next() {
-					var item WishlistItem
-					var product Product
-					err := rows.Scan(
-						&item.ID, &item.UserID, &item.ProductID, &item.CreatedAt,
-						&product.ID, &product.Name, &product.Price, &product.OriginalPrice, &product.CategoryID, &product.Description, &product.ShortDescription, &product.ImageURL, &product.StockQuantity, &product.SKU, &product.Weight, &product.Dimensions, &product.IsActive, &product.IsFeatured, &product.CreatedAt, &product.UpdatedAt,
-					)
-					if err != nil {
-						return nil, err
-					}
-					item.Product = &product
-					items = append(items, item)
-				}
-
-				return items, nil
-			},
-		},
-		"orders": &graphql.Field{
-			Type: graphql.NewList(OrderType),
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				user, ok := p.Context.Value("user").(*User)
-				if !ok {
-					return nil, fmt.Errorf("user not authenticated")
-				}
-
-				rows, err := DB.Query(`
-					SELECT id, user_id, order_number, status, total_amount, shipping_address, shipping_city, shipping_country, shipping_phone, payment_method, payment_status, notes, created_at, updated_at
-					FROM orders
-					WHERE user_id = $1
-					ORDER BY created_at DESC
-				`, user.ID)
-				if err != nil {
-					return nil, err
-				}
-				defer rows.Close()
-
-				var orders []Order
-				for rows.Next() {
-					var order Order
-					err := rows.Scan(&order.ID, &order.UserID, &order.OrderNumber, &order.Status, &order.TotalAmount, &order.ShippingAddress, &order.ShippingCity, &order.ShippingCountry, &order.ShippingPhone, &order.PaymentMethod, &order.PaymentStatus, &order.Notes, &order.CreatedAt, &order.UpdatedAt)
-					if err != nil {
-						return nil, err
-					}
-					orders = append(orders, order)
-				}
-
-				return orders, nil
-			},
-		},
-		"productReviews": &graphql.Field{
-			Type: graphql.NewList(ReviewType),
-			Args: graphql.FieldConfigArgument{
-				"productId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
-			},
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				productID := p.Args["productId"].(int)
-
-				rows, err := DB.Query(`
-					SELECT r.id, r.user_id, r.product_id, r.rating, r.title, r.comment, r.is_verified_purchase, r.created_at, r.updated_at,
-						   u.id, u.email, u.first_name, u.last_name, u.phone, u.address, u.city, u.country, u.created_at, u.updated_at
-					FROM reviews r
-					JOIN users u ON r.user_id = u.id
-					WHERE r.product_id = $1
-					ORDER BY r.created_at DESC
-				`, productID)
-				if err != nil {
-					return nil, err
-				}
-				defer rows.Close()
-
-				var reviews []Review
-				for rows.Next() {
-					var review Review
-					var user User
-					err := rows.Scan(
-						&review.ID, &review.UserID, &review.ProductID, &review.Rating, &review.Title, &review.Comment, &review.IsVerifiedPurchase, &review.CreatedAt, &review.UpdatedAt,
-						&user.ID, &user.Email, &user.FirstName, &user.LastName, &user.Phone, &user.Address, &user.City, &user.Country, &user.CreatedAt, &user.UpdatedAt,
-					)
-					if err != nil {
-						return nil, err
-					}
-					review.User = &user
-					reviews = append(reviews, review)
-				}
-
-				return reviews, nil
-			},
-		},
-	},
-})
-
-// Root Mutation
-var RootMutation = graphql.NewObject(graphql.ObjectConfig{
-	Name: "Mutation",
-	Fields: graphql.Fields{
-		"register": &graphql.Field{
-			Type: AuthResponseType,
-			Args: graphql.FieldConfigArgument{
-				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewInputObject(graphql.InputObjectConfig{
-					Name: "RegisterInput",
-					Fields: graphql.InputObjectConfigFieldMap{
-						"email":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
-						"password":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
-						"firstName": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
-						"lastName":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
-						"phone":     &graphql.InputObjectFieldConfig{Type: graphql.String},
-						"address":   &graphql.InputObjectFieldConfig{Type: graphql.String},
-						"city":      &graphql.InputObjectFieldConfig{Type: graphql.String},
-					},
-				}))},
-			},
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				input := p.Args["input"].(map[string]interface{})
-				
-				user, err := CreateUser(
-					input["email"].(string),
-					input["password"].(string),
-					input["firstName"].(string),
-					input["lastName"].(string),
-					input["phone"].(string),
-					input["address"].(string),
-					input["city"].(string),
-				)
-				if err != nil {
-					return nil, err
-				}
-
-				// Convert graph.User to auth.User
-				authUser := &auth.User{
-					ID:    user.ID,
-					Email: user.Email,
-				}
-				token, err := auth.GenerateToken(authUser)
-				if err != nil {
-					return nil, err
-				}
-
-				return AuthResponse{
-					User:  user,
-					Token: token,
-				}, nil
-			},
-		},
-		"login": &graphql.Field{
-			Type: AuthResponseType,
-			Args: graphql.FieldConfigArgument{
-				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewInputObject(graphql.InputObjectConfig{
-					Name: "LoginInput",
-					Fields: graphql.InputObjectConfigFieldMap{
-						"email":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
-						"password": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
-					},
-				}))},
-			},
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				input := p.Args["input"].(map[string]interface{})
-				
-				user, err := AuthenticateUser(input["email"].(string), input["password"].(string))
-				if err != nil {
-					return nil, err
-				}
-
-				// Convert graph.User to auth.User
-				authUser := &auth.User{
-					ID:    user.ID,
-					Email: user.Email,
-				}
-				token, err := auth.GenerateToken(authUser)
-				if err != nil {
-					return nil, err
-				}
-
-				return AuthResponse{
-					User:  user,
-					Token: token,
-				}, nil
-			},
-		},
-		"addToCart": &graphql.Field{
-			Type: CartItemType,
-			Args: graphql.FieldConfigArgument{
-				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewInputObject(graphql.InputObjectConfig{
-					Name: "AddToCartInput",
-					Fields: graphql.InputObjectConfigFieldMap{
-						"productId": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
-						"quantity":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
-					},
-				}))},
-			},
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				user, ok := p.Context.Value("user").(*User)
-				if !ok {
-					return nil, fmt.Errorf("user not authenticated")
-				}
-
-				input := p.Args["input"].(map[string]interface{})
-				productID := input["productId"].(int)
-				quantity := input["quantity"].(int)
-
-				// Check if product exists and has stock
-				var stockQuantity int
-				err := DB.QueryRow("SELECT stock_quantity FROM products WHERE id = $1 AND is_active = true", productID).Scan(&stockQuantity)
-				if err != nil {
-					return nil, fmt.Errorf("product not found")
-				}
-
-				if stockQuantity < quantity {
-					return nil, fmt.Errorf("insufficient stock")
-				}
-
-				// Add to cart (upsert)
-				var cartItem CartItem
-				query := `
-					INSERT INTO cart (user_id, product_id, quantity)
-					VALUES ($1, $2, $3)
-					ON CONFLICT (user_id, product_id)
-					DO UPDATE SET quantity = cart.quantity + $3, updated_at = CURRENT_TIMESTAMP
-					RETURNING id, user_id, product_id, quantity, created_at, updated_at
-				`
-				err = DB.QueryRow(query, user.ID, productID, quantity).Scan(
-					&cartItem.ID, &cartItem.UserID, &cartItem.ProductID, &cartItem.Quantity, &cartItem.CreatedAt, &cartItem.UpdatedAt,
-				)
-				if err != nil {
-					return nil, err
-				}
-
-				return cartItem, nil
-			},
-		},
-		"removeFromCart": &graphql.Field{
-			Type: graphql.Boolean,
-			Args: graphql.FieldConfigArgument{
-				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
-			},
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				user, ok := p.Context.Value("user").(*User)
-				if !ok {
-					return nil, fmt.Errorf("user not authenticated")
-				}
-
-				id := p.Args["id"].(int)
-				result, err := DB.Exec("DELETE FROM cart WHERE id = $1 AND user_id = $2", id, user.ID)
-				if err != nil {
-					return nil, err
-				}
-
-				rowsAffected, _ := result.RowsAffected()
-				return rowsAffected > 0, nil
-			},
-		},
-		"addToWishlist": &graphql.Field{
-			Type: WishlistItemType,
-			Args: graphql.FieldConfigArgument{
-				"productId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
-			},
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				user, ok := p.Context.Value("user").(*User)
-				if !ok {
-					return nil, fmt.Errorf("user not authenticated")
-				}
-
-				productID := p.Args["productId"].(int)
-
-				// Check if product exists
-				var productExists bool
-				err := DB.QueryRow("SELECT EXISTS(SELECT 1 FROM products WHERE id = $1 AND is_active = true)", productID).Scan(&productExists)
-				if err != nil || !productExists {
-					return nil, fmt.Errorf("product not found")
-				}
-
-				var wishlistItem WishlistItem
-				query := `
-					INSERT INTO wishlist (user_id, product_id)
-					VALUES ($1, $2)
-					ON CONFLICT (user_id, product_id) DO NOTHING
-					RETURNING id, user_id, product_id, created_at
-				`
-				err = DB.QueryRow(query, user.ID, productID).Scan(
-					&wishlistItem.ID, &wishlistItem.UserID, &wishlistItem.ProductID, &wishlistItem.CreatedAt,
-				)
-				if err != nil {
-					return nil, err
-				}
-
-				return wishlistItem, nil
-			},
-		},
-		"createOrder": &graphql.Field{
-			Type: OrderType,
-			Args: graphql.FieldConfigArgument{
-				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewInputObject(graphql.InputObjectConfig{
-					Name: "CreateOrderInput",
-					Fields: graphql.InputObjectConfigFieldMap{
-						"shippingAddress": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
-						"shippingCity":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
-						"shippingPhone":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
-						"paymentMethod":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
-						"notes":           &graphql.InputObjectFieldConfig{Type: graphql.String},
-					},
-				}))},
-			},
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				user, ok := p.Context.Value("user").(*User)
-				if !ok {
-					return nil, fmt.Errorf("user not authenticated")
-				}
-
-				input := p.Args["input"].(map[string]interface{})
-
-				// Start transaction
-				tx, err := DB.Begin()
-				if err != nil {
-					return nil, err
-				}
-				defer tx.Rollback()
-
-				// Get cart items
-				cartRows, err := tx.Query(`
-					SELECT c.id, c.product_id, c.quantity, p.name, p.price
-					FROM cart c
-					JOIN products p ON c.product_id = p.id
-					WHERE c.user_id = $1
-				`, user.ID)
-				if err != nil {
-					return nil, err
-				}
-				defer cartRows.Close()
-
-				var cartItems []struct {
-					ID       int
-					ProductID int
-					Quantity int
-					Name     string
-					Price    float64
-				}
-				var totalAmount float64
-
-				for cartRows.Next() {
-					var item struct {
-						ID       int
-						ProductID int
-						Quantity int
-						Name     string
-						Price    float64
-					}
-					err := cartRows.Scan(&item.ID, &item.ProductID, &item.Quantity, &item.Name, &item.Price)
-					if err != nil {
-						return nil, err
-					}
-					cartItems = append(cartItems, item)
-					totalAmount += item.Price * float64(item.Quantity)
-				}
-
-				if len(cartItems) == 0 {
-					return nil, fmt.Errorf("cart is empty")
-				}
-
-				// Generate order number
-				orderNumber := fmt.Sprintf("ORD-%d-%s", time.Now().Year(), strconv.FormatInt(time.Now().Unix(), 10))
-
-				// Create order
-				var order Order
-				err = tx.QueryRow(`
-					INSERT INTO orders (user_id, order_number, status, total_amount, shipping_address, shipping_city, shipping_phone, payment_method, notes)
-					VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-					RETURNING id, user_id, order_number, status, total_amount, shipping_address, shipping_city, shipping_country, shipping_phone, payment_method, payment_status, notes, created_at, updated_at
-				`, user.ID, orderNumber, "pending", totalAmount, input["shippingAddress"], input["shippingCity"], input["shippingPhone"], input["paymentMethod"], input["notes"]).Scan(
-					&order.ID, &order.UserID, &order.OrderNumber, &order.Status, &order.TotalAmount, &order.ShippingAddress, &order.ShippingCity, &order.ShippingCountry, &order.ShippingPhone, &order.PaymentMethod, &order.PaymentStatus, &order.Notes, &order.CreatedAt, &order.UpdatedAt,
-				)
-				if err != nil {
-					return nil, err
-				}
-
-				// Create order items
-				for _, item := range cartItems {
-					_, err = tx.Exec(`
-						INSERT INTO order_items (order_id, product_id, product_name, product_price, quantity, total_price)
-						VALUES ($1, $2, $3, $4, $5, $6)
-					`, order.ID, item.ProductID, item.Name, item.Price, item.Quantity, item.Price*float64(item.Quantity))
-					if err != nil {
-						return nil, err
-					}
-
-					// Update product stock
-					_, err = tx.Exec("UPDATE products SET stock_quantity = stock_quantity - $1 WHERE id = $2", item.Quantity, item.ProductID)
-					if err != nil {
-						return nil, err
-					}
-				}
-
-				// Clear cart
-				_, err = tx.Exec("DELETE FROM cart WHERE user_id = $1", user.ID)
-				if err != nil {
-					return nil, err
-				}
-
-				// Commit transaction
-				if err = tx.Commit(); err != nil {
-					return nil, err
-				}
-
-				return order, nil
-			},
-		},
-		"createReview": &graphql.Field{
-			Type: ReviewType,
-			Args: graphql.FieldConfigArgument{
-				"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewInputObject(graphql.InputObjectConfig{
-					Name: "CreateReviewInput",
-					Fields: graphql.InputObjectConfigFieldMap{
-						"productId": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
-						"rating":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
-						"title":     &graphql.InputObjectFieldConfig{Type: graphql.String},
-						"comment":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
-					},
-				}))},
-			},
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				user, ok := p.Context.Value("user").(*User)
-				if !ok {
-					return nil, fmt.Errorf("user not authenticated")
-				}
-
-				input := p.Args["input"].(map[string]interface{})
-				productID := input["productId"].(int)
-				rating := input["rating"].(int)
-				title := input["title"].(string)
-				comment := input["comment"].(string)
-
-				// Validate rating
-				if rating < 1 || rating > 5 {
-					return nil, fmt.Errorf("rating must be between 1 and 5")
-				}
-
-				// Check if user has purchased the product
-				var hasPurchased bool
-				err := DB.QueryRow(`
-					SELECT EXISTS(
-						SELECT 1 FROM order_items oi
-						JOIN orders o ON oi.order_id = o.id
-						WHERE o.user_id = $1 AND oi.product_id = $2 AND o.status = 'delivered'
-					)
-				`, user.ID, productID).Scan(&hasPurchased)
-				if err != nil {
-					return nil, err
-				}
-
-				var review Review
-				query := `
-					INSERT INTO reviews (user_id, product_id, rating, title, comment, is_verified_purchase)
-					VALUES ($1, $2, $3, $4, $5, $6)
-					RETURNING id, user_id, product_id, rating, title, comment, is_verified_purchase, created_at, updated_at
-				`
-				err = DB.QueryRow(query, user.ID, productID, rating, title, comment, hasPurchased).Scan(
-					&review.ID, &review.UserID, &review.ProductID, &review.Rating, &review.Title, &review.Comment, &review.IsVerifiedPurchase, &review.CreatedAt, &review.UpdatedAt,
-				)
-				if err != nil {
-					return nil, err
-				}
-
-				return review, nil
-			},
-		},
-		"translateText": &graphql.Field{
-			Type: graphql.String,
-			Args: graphql.FieldConfigArgument{
-				"text": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
-				"from": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
-				"to":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
-			},
-			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
-				text := p.Args["text"].(string)
-				from := p.Args["from"].(string)
-				to := p.Args["to"].(string)
-
-				translatedText, err := handlers.TranslateText(text, from, to)
-				if err != nil {
-					return nil, fmt.Errorf("translation failed: %v", err)
-				}
-
-				return translatedText, nil
-			},
-		},
-	},
-})
-
-// Schema creates the GraphQL schema
-func Schema() (*graphql.Schema, error) {
-	schemaConfig := graphql.SchemaConfig{
-		Query:    RootQuery,
-		Mutation: RootMutation,
-	}
-	schema, err := graphql.NewSchema(schemaConfig)
-	if err != nil {
-		return nil, err
-	}
-	return &schema, nil
-} 
-
-// Database functions moved from db package to avoid circular dependency
-
-// GetUserByEmail retrieves a user by email
-func GetUserByEmail(email string) (*User, error) {
-	query := `
-		SELECT id, email, first_name, last_name, phone, address, city, country, created_at, updated_at
-		FROM users WHERE email = $1
-	`
-	user := &User{}
-	err := DB.QueryRow(query, email).Scan(
-		&user.ID, &user.Email, &user.FirstName, &user.LastName,
-		&user.Phone, &user.Address, &user.City, &user.Country,
-		&user.CreatedAt, &user.UpdatedAt,
-	)
-	if err != nil {
-		return nil, err
-	}
-	return user, nil
-}
-
-// GetUserByID retrieves a user by ID
-func GetUserByID(id int) (*User, error) {
-	query := `
-		SELECT id, email, first_name, last_name, phone, address, city, country, created_at, updated_at
-		FROM users WHERE id = $1
-	`
-	user := &User{}
-	err := DB.QueryRow(query, id).Scan(
-		&user.ID, &user.Email, &user.FirstName, &user.LastName,
-		&user.Phone, &user.Address, &user.City, &user.Country,
-		&user.CreatedAt, &user.UpdatedAt,
-	)
-	if err != nil {
-		return nil, err
-	}
-	return user, nil
-}
-
-// CreateUser creates a new user
-func CreateUser(email, password, firstName, lastName, phone, address, city string) (*User, error) {
-	passwordHash, err := auth.HashPassword(password)
-	if err != nil {
-		return nil, fmt.Errorf("failed to hash password: %v", err)
-	}
-
-	query := `
-		INSERT INTO users (email, password_hash, first_name, last_name, phone, address, city)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING id, email, first_name, last_name, phone, address, city, country, created_at, updated_at
-	`
-	user := &User{}
-	err = DB.QueryRow(query, email, passwordHash, firstName, lastName, phone, address, city).Scan(
-		&user.ID, &user.Email, &user.FirstName, &user.LastName,
-		&user.Phone, &user.Address, &user.City, &user.Country,
-		&user.CreatedAt, &user.UpdatedAt,
-	)
-	if err != nil {
-		return nil, err
-	}
-	return user, nil
-}
-
-// AuthenticateUser authenticates a user with email and password
-func AuthenticateUser(email, password string) (*User, error) {
-	query := `SELECT password_hash FROM users WHERE email = $1`
-	var passwordHash string
-	err := DB.QueryRow(query, email).Scan(&passwordHash)
-	if err != nil {
-		return nil, fmt.Errorf("user not found")
-	}
-
-	if !auth.CheckPassword(password, passwordHash) {
-		return nil, fmt.Errorf("invalid password")
-	}
-
-	return GetUserByEmail(email)
-} 
\ No newline at end of file
+package graph
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/jobs"
+	"ai-catalog/repository"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	_ "github.com/lib/pq"
+)
+
+// Repos is the data access the resolvers use
+var Repos *repository.Repositories
+
+// SetRepositories replaces the repositories, for example with an in-memory store
+func SetRepositories(repos *repository.Repositories) {
+	Repos = repos
+}
+
+// Resolver implements ResolverRoot, the resolver interfaces generated from
+// schema.graphqls
+type Resolver struct{}
+
+func (r *Resolver) Query() QueryResolver                   { return &queryResolver{r} }
+func (r *Resolver) Mutation() MutationResolver             { return &mutationResolver{r} }
+func (r *Resolver) Category() CategoryResolver             { return &categoryResolver{r} }
+func (r *Resolver) Product() ProductResolver               { return &productResolver{r} }
+func (r *Resolver) ProductVariant() ProductVariantResolver { return &productVariantResolver{r} }
+func (r *Resolver) Order() OrderResolver                   { return &orderResolver{r} }
+func (r *Resolver) AuthResponse() AuthResponseResolver     { return &authResponseResolver{r} }
+func (r *Resolver) Job() JobResolver                       { return &jobResolver{r} }
+
+type queryResolver struct{ *Resolver }
+type mutationResolver struct{ *Resolver }
+type categoryResolver struct{ *Resolver }
+type productResolver struct{ *Resolver }
+type productVariantResolver struct{ *Resolver }
+type orderResolver struct{ *Resolver }
+type authResponseResolver struct{ *Resolver }
+type jobResolver struct{ *Resolver }
+
+// Schema creates the GraphQL schema
+func Schema() (*graphql.Schema, error) {
+	schema, err := NewSchema(&Resolver{})
+	if err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// stringValue returns the string s points to, or "" when s is nil
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// nonEmpty returns a pointer to s, or nil when s is empty so the field resolves to null
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// localeOrDefault returns locale, or the default locale for text that was not translated
+func localeOrDefault(locale string) string {
+	if locale == "" {
+		return DefaultLocale()
+	}
+	return locale
+}
+
+func (r *categoryResolver) Locale(p graphql.ResolveParams, obj *Category) (string, error) {
+	return localeOrDefault(obj.Locale), nil
+}
+
+func (r *productResolver) Locale(p graphql.ResolveParams, obj *Product) (string, error) {
+	return localeOrDefault(obj.Locale), nil
+}
+
+func (r *productResolver) Category(p graphql.ResolveParams, obj *Product) (*Category, error) {
+	if obj.Category != nil {
+		return obj.Category, nil
+	}
+	if obj.CategoryID == 0 {
+		return nil, nil
+	}
+
+	category, err := Repos.Categories.GetByID(p.Context, obj.CategoryID)
+	if err == ErrCategoryNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Show the category in the same language as the product
+	if obj.Locale != "" {
+		if err := localizeCategories(p.Context, []*Category{category}, obj.Locale); err != nil {
+			return nil, err
+		}
+	}
+	return category, nil
+}
+
+func (r *productResolver) Options(p graphql.ResolveParams, obj *Product) ([]*ProductOption, error) {
+	variants, err := activeVariants(p.Context, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return productOptions(variants), nil
+}
+
+func (r *productResolver) Variants(p graphql.ResolveParams, obj *Product) ([]*ProductVariant, error) {
+	return activeVariants(p.Context, obj.ID)
+}
+
+func (r *productVariantResolver) Available(p graphql.ResolveParams, obj *ProductVariant) (bool, error) {
+	return variantAvailable(obj), nil
+}
+
+// treeLocale returns the locale to show the categories around obj in: the
+// locale obj is shown in, else the one of the request
+func treeLocale(p graphql.ResolveParams, obj *Category) (string, error) {
+	if obj.Locale != "" {
+		return obj.Locale, nil
+	}
+	return requestLocale(p)
+}
+
+func (r *categoryResolver) Parent(p graphql.ResolveParams, obj *Category) (*Category, error) {
+	if obj.ParentID == nil {
+		return nil, nil
+	}
+	locale, err := treeLocale(p, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := Repos.Categories.GetByID(p.Context, *obj.ParentID)
+	if err == ErrCategoryNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeCategories(p.Context, []*Category{parent}, locale); err != nil {
+		return nil, err
+	}
+	return parent, nil
+}
+
+func (r *categoryResolver) Children(p graphql.ResolveParams, obj *Category) ([]*Category, error) {
+	locale, err := treeLocale(p, obj)
+	if err != nil {
+		return nil, err
+	}
+	return categoryChildren(p.Context, &obj.ID, locale)
+}
+
+func (r *categoryResolver) Ancestors(p graphql.ResolveParams, obj *Category) ([]*Category, error) {
+	locale, err := treeLocale(p, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	ancestors, err := Repos.Categories.Ancestors(p.Context, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeCategories(p.Context, ancestors, locale); err != nil {
+		return nil, err
+	}
+	return ancestors, nil
+}
+
+func (r *categoryResolver) Depth(p graphql.ResolveParams, obj *Category) (int, error) {
+	ancestors, err := Repos.Categories.Ancestors(p.Context, obj.ID)
+	if err != nil {
+		return 0, err
+	}
+	return len(ancestors), nil
+}
+
+func (r *orderResolver) User(p graphql.ResolveParams, obj *Order) (*User, error) {
+	if obj.User != nil {
+		return obj.User, nil
+	}
+	return Repos.Users.GetByID(p.Context, obj.UserID)
+}
+
+func (r *orderResolver) Items(p graphql.ResolveParams, obj *Order) ([]*OrderItem, error) {
+	if obj.Items != nil {
+		return obj.Items, nil
+	}
+	return Repos.Orders.Items(p.Context, obj.ID)
+}
+
+func (r *authResponseResolver) Token(p graphql.ResolveParams, obj *AuthResponse) (*string, error) {
+	return nonEmpty(obj.Token), nil
+}
+
+func (r *authResponseResolver) RefreshToken(p graphql.ResolveParams, obj *AuthResponse) (*string, error) {
+	return nonEmpty(obj.RefreshToken), nil
+}
+
+func (r *authResponseResolver) ChallengeToken(p graphql.ResolveParams, obj *AuthResponse) (*string, error) {
+	return nonEmpty(obj.ChallengeToken), nil
+}
+
+func (r *jobResolver) Result(p graphql.ResolveParams, obj *jobs.Job) (*string, error) {
+	return jobResult(obj), nil
+}
+
+// Root Query
+
+func (r *queryResolver) Me(p graphql.ResolveParams) (*User, error) {
+	if user, ok := p.Context.Value("user").(*User); ok {
+		return user, nil
+	}
+	return nil, nil
+}
+
+func (r *queryResolver) MyIdentities(p graphql.ResolveParams) ([]*UserIdentity, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetUserIdentities(p.Context, user.ID)
+}
+
+func (r *queryResolver) ApiKeys(p graphql.ResolveParams, args QueryApiKeysArgs) ([]*auth.APIKey, error) {
+	// Admins can list the keys of any user
+	if args.UserID != nil {
+		if _, err := RequireAdmin(p, auth.ScopeUsersRead); err != nil {
+			return nil, err
+		}
+		return auth.ListAPIKeys(p.Context, *args.UserID)
+	}
+
+	user, err := RequireUser(p)
+	if err != nil {
+		return nil, err
+	}
+	return auth.ListAPIKeys(p.Context, user.ID)
+}
+
+func (r *queryResolver) Job(p graphql.ResolveParams, args QueryJobArgs) (*jobs.Job, error) {
+	user, err := RequireScope(p, auth.ScopeCatalogRead)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetJobForUser(p.Context, args.ID, user)
+}
+
+func (r *queryResolver) TranslationMemory(p graphql.ResolveParams, args QueryTranslationMemoryArgs) ([]*TranslationMemoryEntry, error) {
+	if _, err := RequireAdmin(p, auth.ScopeCatalogRead); err != nil {
+		return nil, err
+	}
+
+	return ListTranslationMemory(p.Context, stringValue(args.Search), stringValue(args.SourceLang), stringValue(args.TargetLang),
+		args.OverridesOnly, args.Limit)
+}
+
+func (r *queryResolver) Categories(p graphql.ResolveParams, args QueryCategoriesArgs) ([]*Category, error) {
+	locale, err := requestLocale(p)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := Repos.Categories.List(p.Context)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeCategories(p.Context, categories, locale); err != nil {
+		return nil, err
+	}
+
+	// Order by the name shown to the client
+	sort.SliceStable(categories, func(i, j int) bool { return categories[i].Name < categories[j].Name })
+	return categories, nil
+}
+
+func (r *queryResolver) RootCategories(p graphql.ResolveParams, args QueryRootCategoriesArgs) ([]*Category, error) {
+	locale, err := requestLocale(p)
+	if err != nil {
+		return nil, err
+	}
+	return categoryChildren(p.Context, nil, locale)
+}
+
+func (r *queryResolver) Category(p graphql.ResolveParams, args QueryCategoryArgs) (*Category, error) {
+	locale, err := requestLocale(p)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := Repos.Categories.GetByID(p.Context, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeCategories(p.Context, []*Category{category}, locale); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (r *queryResolver) Products(p graphql.ResolveParams, args QueryProductsArgs) ([]*Product, error) {
+	locale, err := requestLocale(p)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := repository.ProductFilter{
+		CategoryID:         args.CategoryID,
+		IncludeDescendants: args.IncludeDescendants,
+		// Match the text in any language
+		Search:     stringValue(args.Search),
+		MinPrice:   args.MinPrice,
+		MaxPrice:   args.MaxPrice,
+		IsFeatured: args.IsFeatured,
+		MinRating:  args.MinRating,
+		InStock:    args.InStock,
+		ActiveOnly: true,
+	}
+	if err := validateProductFilter(filter); err != nil {
+		return nil, err
+	}
+	if args.Limit != nil && *args.Limit > 0 {
+		filter.Limit = *args.Limit
+	}
+	if args.Page != nil && *args.Page > 1 {
+		if filter.Limit == 0 {
+			filter.Limit = defaultPageSize
+		}
+		filter.Offset = (*args.Page - 1) * filter.Limit
+	}
+
+	products, err := Repos.Products.List(p.Context, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeProducts(p.Context, products, locale); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *queryResolver) ProductsConnection(p graphql.ResolveParams, args QueryProductsConnectionArgs) (*ProductConnection, error) {
+	locale, err := requestLocale(p)
+	if err != nil {
+		return nil, err
+	}
+	sortBy, err := productSort(args.Sort)
+	if err != nil {
+		return nil, err
+	}
+	page, err := pageArgs(args.First, args.After, args.Last, args.Before)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := repository.ProductFilter{
+		CategoryID:         args.CategoryID,
+		IncludeDescendants: args.IncludeDescendants,
+		Search:             stringValue(args.Search),
+		MinPrice:           args.MinPrice,
+		MaxPrice:           args.MaxPrice,
+		IsFeatured:         args.IsFeatured,
+		MinRating:          args.MinRating,
+		InStock:            args.InStock,
+		ActiveOnly:         true,
+	}
+	if err := validateProductFilter(filter); err != nil {
+		return nil, err
+	}
+	connection, err := Repos.Products.ListPage(p.Context, filter, sortBy, page)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeProducts(p.Context, connectionProducts(connection), locale); err != nil {
+		return nil, err
+	}
+	return connection, nil
+}
+
+func (r *queryResolver) Product(p graphql.ResolveParams, args QueryProductArgs) (*Product, error) {
+	locale, err := requestLocale(p)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := Repos.Products.GetByID(p.Context, args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Archived products are only visible to admins
+	if !product.IsActive {
+		if user, ok := p.Context.Value("user").(*User); !ok || user.Role != auth.RoleAdmin {
+			return nil, ErrProductNotFound
+		}
+	}
+
+	if err := localizeProducts(p.Context, []*Product{product}, locale); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func (r *queryResolver) FeaturedProducts(p graphql.ResolveParams, args QueryFeaturedProductsArgs) ([]*Product, error) {
+	locale, err := requestLocale(p)
+	if err != nil {
+		return nil, err
+	}
+
+	featured := true
+	products, err := Repos.Products.List(p.Context, repository.ProductFilter{IsFeatured: &featured, ActiveOnly: true, Limit: 10})
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeProducts(p.Context, products, locale); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *queryResolver) SearchProducts(p graphql.ResolveParams, args QuerySearchProductsArgs) (*SearchResult, error) {
+	locale, err := requestLocale(p)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := repository.ProductFilter{
+		CategoryID:         args.CategoryID,
+		IncludeDescendants: args.IncludeDescendants,
+		MinPrice:           args.MinPrice,
+		MaxPrice:           args.MaxPrice,
+		IsFeatured:         args.IsFeatured,
+		MinRating:          args.MinRating,
+		InStock:            args.InStock,
+	}
+	return SearchProducts(p.Context, args.Query, filter, locale)
+}
+
+func (r *queryResolver) SearchSuggestions(p graphql.ResolveParams, args QuerySearchSuggestionsArgs) (*SearchSuggestions, error) {
+	locale, err := requestLocale(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return SuggestSearches(p.Context, args.Prefix, args.Limit, locale)
+}
+
+func (r *queryResolver) Cart(p graphql.ResolveParams) (*CartSummary, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := Repos.Carts.List(p.Context, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &CartSummary{Items: items}
+	for _, item := range items {
+		if !item.Available {
+			continue
+		}
+		price := item.Product.Price
+		if item.Variant != nil {
+			price = item.Variant.Price
+		}
+		summary.TotalPrice += price * float64(item.Quantity)
+		summary.TotalItems += item.Quantity
+	}
+	return summary, nil
+}
+
+func (r *queryResolver) Wishlist(p graphql.ResolveParams) ([]*WishlistItem, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return Repos.Wishlists.List(p.Context, user.ID)
+}
+
+func (r *queryResolver) WishlistConnection(p graphql.ResolveParams, args QueryWishlistConnectionArgs) (*WishlistConnection, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return nil, err
+	}
+	page, err := pageArgs(args.First, args.After, args.Last, args.Before)
+	if err != nil {
+		return nil, err
+	}
+
+	return Repos.Wishlists.ListPage(p.Context, user.ID, page)
+}
+
+func (r *queryResolver) Orders(p graphql.ResolveParams) ([]*Order, error) {
+	user, err := RequireScope(p, auth.ScopeOrdersRead)
+	if err != nil {
+		return nil, err
+	}
+
+	return Repos.Orders.ListForUser(p.Context, user.ID)
+}
+
+func (r *queryResolver) OrdersConnection(p graphql.ResolveParams, args QueryOrdersConnectionArgs) (*OrderConnection, error) {
+	user, err := RequireScope(p, auth.ScopeOrdersRead)
+	if err != nil {
+		return nil, err
+	}
+	page, err := pageArgs(args.First, args.After, args.Last, args.Before)
+	if err != nil {
+		return nil, err
+	}
+
+	return Repos.Orders.ListPageForUser(p.Context, user.ID, page)
+}
+
+func (r *queryResolver) Order(p graphql.ResolveParams, args QueryOrderArgs) (*Order, error) {
+	user, err := RequireScope(p, auth.ScopeOrdersRead)
+	if err != nil {
+		return nil, err
+	}
+
+	return GetOrderForUser(p.Context, args.ID, user, hasAdminAccess(p, auth.ScopeOrdersRead))
+}
+
+func (r *queryResolver) ProductReviews(p graphql.ResolveParams, args QueryProductReviewsArgs) ([]*Review, error) {
+	viewer, _ := p.Context.Value("user").(*User)
+	return Repos.Reviews.List(p.Context, viewerID(viewer), repository.ReviewFilter{ProductID: args.ProductID})
+}
+
+func (r *queryResolver) ProductReviewsConnection(p graphql.ResolveParams, args QueryProductReviewsConnectionArgs) (*ReviewConnection, error) {
+	page, err := pageArgs(args.First, args.After, args.Last, args.Before)
+	if err != nil {
+		return nil, err
+	}
+
+	viewer, _ := p.Context.Value("user").(*User)
+	return Repos.Reviews.ListPage(p.Context, viewerID(viewer), repository.ReviewFilter{ProductID: args.ProductID}, page)
+}
+
+func (r *queryResolver) MyReviews(p graphql.ResolveParams) ([]*Review, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return Repos.Reviews.List(p.Context, user.ID, repository.ReviewFilter{UserID: user.ID})
+}
+
+func (r *queryResolver) MyReviewsConnection(p graphql.ResolveParams, args QueryMyReviewsConnectionArgs) (*ReviewConnection, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return nil, err
+	}
+	page, err := pageArgs(args.First, args.After, args.Last, args.Before)
+	if err != nil {
+		return nil, err
+	}
+
+	return Repos.Reviews.ListPage(p.Context, user.ID, repository.ReviewFilter{UserID: user.ID}, page)
+}
+
+func (r *queryResolver) LoginAttempts(p graphql.ResolveParams, args QueryLoginAttemptsArgs) ([]*LoginAttempt, error) {
+	if _, err := RequireAdmin(p, auth.ScopeUsersRead); err != nil {
+		return nil, err
+	}
+
+	limit := 100
+	if args.Limit != nil && *args.Limit > 0 && *args.Limit <= 1000 {
+		limit = *args.Limit
+	}
+	return ListLoginAttempts(p.Context, stringValue(args.Email), stringValue(args.IPAddress), args.Success, limit)
+}
+
+func (r *queryResolver) SearchStats(p graphql.ResolveParams, args QuerySearchStatsArgs) ([]*SearchStat, error) {
+	if _, err := RequireAdmin(p, auth.ScopeCatalogRead); err != nil {
+		return nil, err
+	}
+
+	return SearchStats(p.Context, args.Days, args.ZeroResults, args.Limit)
+}
+
+// Root Mutation
+
+func (r *mutationResolver) Register(p graphql.ResolveParams, args MutationRegisterArgs) (*AuthResponse, error) {
+	input := args.Input
+
+	if err := auth.ValidatePassword(input.Password); err != nil {
+		return nil, err
+	}
+
+	user, err := CreateUser(
+		p.Context,
+		input.Email,
+		input.Password,
+		input.FirstName,
+		input.LastName,
+		stringValue(input.Phone),
+		stringValue(input.Address),
+		stringValue(input.City),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	logMailError("verification", user, sendVerificationEmail(p.Context, user))
+
+	return newAuthResponse(p.Context, user)
+}
+
+func (r *mutationResolver) Login(p graphql.ResolveParams, args MutationLoginArgs) (*AuthResponse, error) {
+	user, err := loginWithPassword(p, args.Input.Email, args.Input.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	return completeLogin(p.Context, user)
+}
+
+func (r *mutationResolver) RefreshToken(p graphql.ResolveParams, args MutationRefreshTokenArgs) (*AuthResponse, error) {
+	session, refreshToken, err := auth.RotateRefreshToken(p.Context, args.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := LoadAuthenticatedUser(p.Context, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return sessionAuthResponse(user, session.ID, refreshToken)
+}
+
+func (r *mutationResolver) Logout(p graphql.ResolveParams) (bool, error) {
+	if _, err := RequireUser(p); err != nil {
+		return false, err
+	}
+
+	sessionID, _ := p.Context.Value("sessionID").(string)
+	if err := auth.RevokeSession(p.Context, sessionID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) LogoutAllDevices(p graphql.ResolveParams) (bool, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return false, err
+	}
+
+	if err := auth.RevokeAllSessions(p.Context, user.ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) UnlinkIdentity(p graphql.ResolveParams, args MutationUnlinkIdentityArgs) (bool, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return false, err
+	}
+
+	unlinked, err := UnlinkIdentity(p.Context, user.ID, args.Provider)
+	if err != nil {
+		return false, err
+	}
+	if !unlinked {
+		return false, fmt.Errorf("no %s account is linked", args.Provider)
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) CreateApiKey(p graphql.ResolveParams, args MutationCreateApiKeyArgs) (*CreateApiKeyPayload, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var ttl time.Duration
+	if args.ExpiresInDays != nil {
+		days := *args.ExpiresInDays
+		if days <= 0 {
+			return nil, fmt.Errorf("expiresInDays must be positive")
+		}
+		ttl = time.Duration(days) * 24 * time.Hour
+	}
+
+	key, rawKey, err := auth.CreateAPIKey(p.Context, user.ID, args.Name, args.Scopes, ttl)
+	if err != nil {
+		return nil, err
+	}
+	return &CreateApiKeyPayload{APIKey: key, Key: rawKey}, nil
+}
+
+func (r *mutationResolver) RevokeApiKey(p graphql.ResolveParams, args MutationRevokeApiKeyArgs) (bool, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return false, err
+	}
+
+	// Admins can revoke any user's key
+	ownerID := user.ID
+	if user.Role == auth.RoleAdmin && !twoFactorSetupRequired(user) {
+		ownerID = 0
+	}
+
+	revoked, err := auth.RevokeAPIKey(p.Context, args.ID, ownerID)
+	if err != nil {
+		return false, err
+	}
+	if !revoked {
+		return false, fmt.Errorf("API key not found")
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) RequestPasswordReset(p graphql.ResolveParams, args MutationRequestPasswordResetArgs) (bool, error) {
+	// The account is looked up and mailed in the background, and success is
+	// always reported, so neither the response nor its timing reveals which
+	// emails are registered
+	if _, err := jobs.Enqueue(p.Context, JobSendPasswordReset, passwordResetPayload{Email: args.Email}, 0); err != nil {
+		return false, fmt.Errorf("failed to request password reset: %v", err)
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) ResetPassword(p graphql.ResolveParams, args MutationResetPasswordArgs) (bool, error) {
+	if err := auth.ValidatePassword(args.NewPassword); err != nil {
+		return false, err
+	}
+
+	userID, err := auth.ConsumeUserToken(p.Context, args.Token, auth.TokenPurposePasswordReset)
+	if err != nil {
+		return false, err
+	}
+
+	if err := UpdatePassword(p.Context, userID, args.NewPassword); err != nil {
+		return false, err
+	}
+
+	// The reset link proves ownership of the mailbox
+	if err := MarkEmailVerified(p.Context, userID); err != nil {
+		return false, err
+	}
+
+	// Sign out every device that may have used the old password
+	if err := auth.RevokeAllSessions(p.Context, userID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) ChangePassword(p graphql.ResolveParams, args MutationChangePasswordArgs) (bool, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return false, err
+	}
+
+	if err := auth.ValidatePassword(args.NewPassword); err != nil {
+		return false, err
+	}
+
+	passwordHash, err := Repos.Users.PasswordHash(p.Context, user.ID)
+	if err != nil {
+		return false, err
+	}
+	if !auth.CheckPassword(args.CurrentPassword, passwordHash) {
+		return false, fmt.Errorf("current password is incorrect")
+	}
+
+	if err := UpdatePassword(p.Context, user.ID, args.NewPassword); err != nil {
+		return false, err
+	}
+
+	// Keep the current device signed in and revoke the rest
+	sessionID, _ := p.Context.Value("sessionID").(string)
+	if err := auth.RevokeOtherSessions(p.Context, user.ID, sessionID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) VerifyEmail(p graphql.ResolveParams, args MutationVerifyEmailArgs) (bool, error) {
+	userID, err := auth.ConsumeUserToken(p.Context, args.Token, auth.TokenPurposeEmailVerification)
+	if err != nil {
+		return false, err
+	}
+
+	if err := MarkEmailVerified(p.Context, userID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) ResendVerificationEmail(p graphql.ResolveParams) (bool, error) {
+	current, err := RequireUser(p)
+	if err != nil {
+		return false, err
+	}
+
+	user, err := Repos.Users.GetByID(p.Context, current.ID)
+	if err != nil {
+		return false, err
+	}
+	if user.EmailVerified {
+		return true, nil
+	}
+
+	if err := sendVerificationEmail(p.Context, user); err != nil {
+		return false, fmt.Errorf("failed to send verification email: %v", err)
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) UpdateProfile(p graphql.ResolveParams, args MutationUpdateProfileArgs) (*User, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return UpdateProfile(p.Context, user.ID, args.Input)
+}
+
+func (r *mutationResolver) EnableTwoFactor(p graphql.ResolveParams) (*TwoFactorSetup, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return startTwoFactorSetup(p.Context, user)
+}
+
+func (r *mutationResolver) ConfirmTwoFactor(p graphql.ResolveParams, args MutationConfirmTwoFactorArgs) ([]string, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return confirmTwoFactorSetup(p.Context, user, args.Code)
+}
+
+func (r *mutationResolver) VerifyTwoFactor(p graphql.ResolveParams, args MutationVerifyTwoFactorArgs) (*AuthResponse, error) {
+	return verifyTwoFactorLogin(p, args.ChallengeToken, args.Code)
+}
+
+func (r *mutationResolver) RegenerateRecoveryCodes(p graphql.ResolveParams, args MutationRegenerateRecoveryCodesArgs) ([]string, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTwoFactorCode(p.Context, user.ID, args.Code); err != nil {
+		return nil, err
+	}
+	return replaceRecoveryCodes(p.Context, user.ID)
+}
+
+func (r *mutationResolver) DisableTwoFactor(p graphql.ResolveParams, args MutationDisableTwoFactorArgs) (bool, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return false, err
+	}
+
+	if user.TwoFactorRequired || (user.Role == auth.RoleAdmin && adminsRequireTwoFactor()) {
+		return false, fmt.Errorf("two-factor authentication is required for this account")
+	}
+	if err := checkTwoFactorCode(p.Context, user.ID, args.Code); err != nil {
+		return false, err
+	}
+
+	if err := disableTwoFactor(p.Context, user.ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) AddToCart(p graphql.ResolveParams, args MutationAddToCartArgs) (*CartItem, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return Repos.Carts.Add(p.Context, user.ID, args.Input.ProductID, args.Input.VariantID, args.Input.Quantity)
+}
+
+func (r *mutationResolver) UpdateCartItem(p graphql.ResolveParams, args MutationUpdateCartItemArgs) (*CartItem, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return UpdateCartItem(p.Context, user.ID, args.ID, args.Quantity)
+}
+
+func (r *mutationResolver) RemoveFromCart(p graphql.ResolveParams, args MutationRemoveFromCartArgs) (bool, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return false, err
+	}
+
+	return Repos.Carts.Remove(p.Context, user.ID, args.ID)
+}
+
+func (r *mutationResolver) ClearCart(p graphql.ResolveParams) (bool, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return false, err
+	}
+
+	if err := Repos.Carts.Clear(p.Context, user.ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) AddToWishlist(p graphql.ResolveParams, args MutationAddToWishlistArgs) (*WishlistItem, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return Repos.Wishlists.Add(p.Context, user.ID, args.ProductID)
+}
+
+func (r *mutationResolver) RemoveFromWishlist(p graphql.ResolveParams, args MutationRemoveFromWishlistArgs) (bool, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return false, err
+	}
+
+	return Repos.Wishlists.Remove(p.Context, user.ID, args.ProductID)
+}
+
+func (r *mutationResolver) CreateOrder(p graphql.ResolveParams, args MutationCreateOrderArgs) (*Order, error) {
+	user, err := RequireScope(p, auth.ScopeOrdersWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	input := args.Input
+
+	return Repos.Orders.CreateFromCart(p.Context, repository.NewOrder{
+		UserID:          user.ID,
+		OrderNumber:     fmt.Sprintf("ORD-%d-%s", time.Now().Year(), strconv.FormatInt(time.Now().Unix(), 10)),
+		ShippingAddress: input.ShippingAddress,
+		ShippingCity:    input.ShippingCity,
+		ShippingPhone:   input.ShippingPhone,
+		PaymentMethod:   input.PaymentMethod,
+		Notes:           input.Notes,
+	})
+}
+
+func (r *mutationResolver) UpdateOrderStatus(p graphql.ResolveParams, args MutationUpdateOrderStatusArgs) (*Order, error) {
+	if _, err := RequireAdmin(p, auth.ScopeOrdersWrite); err != nil {
+		return nil, err
+	}
+
+	switch args.Status {
+	case "pending", "processing", "shipped", "delivered", "cancelled":
+	default:
+		return nil, fmt.Errorf("invalid order status: %s", args.Status)
+	}
+
+	return Repos.Orders.UpdateStatus(p.Context, args.ID, args.Status)
+}
+
+func (r *mutationResolver) CreateReview(p graphql.ResolveParams, args MutationCreateReviewArgs) (*Review, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return nil, err
+	}
+
+	input := args.Input
+
+	// Validate rating
+	if input.Rating < 1 || input.Rating > 5 {
+		return nil, fmt.Errorf("rating must be between 1 and 5")
+	}
+
+	return Repos.Reviews.Create(p.Context, repository.NewReview{
+		UserID:    user.ID,
+		ProductID: input.ProductID,
+		Rating:    input.Rating,
+		Title:     stringValue(input.Title),
+		Comment:   input.Comment,
+	})
+}
+
+func (r *mutationResolver) UpdateReview(p graphql.ResolveParams, args MutationUpdateReviewArgs) (*Review, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return UpdateReview(p.Context, user, args.ID, args.Rating, stringValue(args.Title), args.Comment)
+}
+
+func (r *mutationResolver) DeleteReview(p graphql.ResolveParams, args MutationDeleteReviewArgs) (bool, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return false, err
+	}
+
+	if err := DeleteReview(p.Context, user, args.ID, hasAdminAccess(p, auth.ScopeCatalogWrite)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) LikeReview(p graphql.ResolveParams, args MutationLikeReviewArgs) (bool, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return false, err
+	}
+
+	if err := Repos.Reviews.SetLiked(p.Context, user.ID, args.ID, true); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) UnlikeReview(p graphql.ResolveParams, args MutationUnlikeReviewArgs) (bool, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return false, err
+	}
+
+	if err := Repos.Reviews.SetLiked(p.Context, user.ID, args.ID, false); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) LikeProduct(p graphql.ResolveParams, args MutationLikeProductArgs) (bool, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return false, err
+	}
+
+	if err := Repos.Products.SetLiked(p.Context, user.ID, args.ProductID, true); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) UnlikeProduct(p graphql.ResolveParams, args MutationUnlikeProductArgs) (bool, error) {
+	user, err := RequireUser(p)
+	if err != nil {
+		return false, err
+	}
+
+	if err := Repos.Products.SetLiked(p.Context, user.ID, args.ProductID, false); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) CreateProduct(p graphql.ResolveParams, args MutationCreateProductArgs) (*Product, error) {
+	user, err := RequireAdmin(p, auth.ScopeCatalogWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateProduct(p.Context, user.ID, args.Input)
+}
+
+func (r *mutationResolver) UpdateProduct(p graphql.ResolveParams, args MutationUpdateProductArgs) (*Product, error) {
+	if _, err := RequireAdmin(p, auth.ScopeCatalogWrite); err != nil {
+		return nil, err
+	}
+
+	return UpdateProduct(p.Context, args.ID, args.Input)
+}
+
+func (r *mutationResolver) ArchiveProduct(p graphql.ResolveParams, args MutationArchiveProductArgs) (*Product, error) {
+	if _, err := RequireAdmin(p, auth.ScopeCatalogWrite); err != nil {
+		return nil, err
+	}
+
+	return Repos.Products.SetActive(p.Context, args.ID, false)
+}
+
+func (r *mutationResolver) RestoreProduct(p graphql.ResolveParams, args MutationRestoreProductArgs) (*Product, error) {
+	if _, err := RequireAdmin(p, auth.ScopeCatalogWrite); err != nil {
+		return nil, err
+	}
+
+	return Repos.Products.SetActive(p.Context, args.ID, true)
+}
+
+func (r *mutationResolver) DeleteProduct(p graphql.ResolveParams, args MutationDeleteProductArgs) (bool, error) {
+	if _, err := RequireAdmin(p, auth.ScopeCatalogWrite); err != nil {
+		return false, err
+	}
+
+	// Ordered products must be archived instead so order history stays intact
+	if err := Repos.Products.Delete(p.Context, args.ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) CreateProductVariant(p graphql.ResolveParams, args MutationCreateProductVariantArgs) (*ProductVariant, error) {
+	if _, err := RequireAdmin(p, auth.ScopeCatalogWrite); err != nil {
+		return nil, err
+	}
+
+	return CreateProductVariant(p.Context, args.Input)
+}
+
+func (r *mutationResolver) UpdateProductVariant(p graphql.ResolveParams, args MutationUpdateProductVariantArgs) (*ProductVariant, error) {
+	if _, err := RequireAdmin(p, auth.ScopeCatalogWrite); err != nil {
+		return nil, err
+	}
+
+	return UpdateProductVariant(p.Context, args.ID, args.Input)
+}
+
+func (r *mutationResolver) DeleteProductVariant(p graphql.ResolveParams, args MutationDeleteProductVariantArgs) (bool, error) {
+	if _, err := RequireAdmin(p, auth.ScopeCatalogWrite); err != nil {
+		return false, err
+	}
+
+	if err := Repos.Variants.Delete(p.Context, args.ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) CreateCategory(p graphql.ResolveParams, args MutationCreateCategoryArgs) (*Category, error) {
+	if _, err := RequireAdmin(p, auth.ScopeCatalogWrite); err != nil {
+		return nil, err
+	}
+
+	return CreateCategory(p.Context, args.Input)
+}
+
+func (r *mutationResolver) MoveCategory(p graphql.ResolveParams, args MutationMoveCategoryArgs) (*Category, error) {
+	if _, err := RequireAdmin(p, auth.ScopeCatalogWrite); err != nil {
+		return nil, err
+	}
+
+	return MoveCategory(p.Context, args.ID, args.ParentID)
+}
+
+func (r *mutationResolver) DeleteCategory(p graphql.ResolveParams, args MutationDeleteCategoryArgs) (bool, error) {
+	if _, err := RequireAdmin(p, auth.ScopeCatalogWrite); err != nil {
+		return false, err
+	}
+
+	// Subcategories and products must be moved first so none are left orphaned
+	if err := Repos.Categories.Delete(p.Context, args.ID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *mutationResolver) SetProductTranslation(p graphql.ResolveParams, args MutationSetProductTranslationArgs) (*Product, error) {
+	if _, err := RequireAdmin(p, auth.ScopeCatalogWrite); err != nil {
+		return nil, err
+	}
+
+	return SetProductTranslation(p.Context, args.ProductID, args.Locale, args.Name, stringValue(args.Description),
+		stringValue(args.ShortDescription))
+}
+
+func (r *mutationResolver) SetCategoryTranslation(p graphql.ResolveParams, args MutationSetCategoryTranslationArgs) (*Category, error) {
+	if _, err := RequireAdmin(p, auth.ScopeCatalogWrite); err != nil {
+		return nil, err
+	}
+
+	return SetCategoryTranslation(p.Context, args.CategoryID, args.Locale, args.Name, stringValue(args.Description))
+}
+
+func (r *mutationResolver) FillMissingTranslations(p graphql.ResolveParams, args MutationFillMissingTranslationsArgs) (*jobs.Job, error) {
+	user, err := RequireAdmin(p, auth.ScopeCatalogWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	return enqueueFillTranslations(p.Context, user.ID, args.Locale)
+}
+
+func (r *mutationResolver) SetTranslationOverride(p graphql.ResolveParams, args MutationSetTranslationOverrideArgs) (*TranslationMemoryEntry, error) {
+	user, err := RequireAdmin(p, auth.ScopeCatalogWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	return SetTranslationOverride(p.Context, args.Text, args.From, args.To, args.Translation, user.ID)
+}
+
+func (r *mutationResolver) DeleteTranslationMemoryEntry(p graphql.ResolveParams, args MutationDeleteTranslationMemoryEntryArgs) (bool, error) {
+	if _, err := RequireAdmin(p, auth.ScopeCatalogWrite); err != nil {
+		return false, err
+	}
+
+	return DeleteTranslationMemoryEntry(p.Context, args.ID)
+}
+
+func (r *mutationResolver) UpdateUserRole(p graphql.ResolveParams, args MutationUpdateUserRoleArgs) (*User, error) {
+	admin, err := RequireAdmin(p, auth.ScopeUsersWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	if !auth.IsValidRole(args.Role) {
+		return nil, fmt.Errorf("invalid role: %s", args.Role)
+	}
+	if args.UserID == admin.ID {
+		return nil, fmt.Errorf("cannot change your own role")
+	}
+
+	if err := Repos.Users.SetRole(p.Context, args.UserID, args.Role); err != nil {
+		return nil, err
+	}
+	InvalidateAuthenticatedUser(args.UserID)
+
+	return Repos.Users.GetByID(p.Context, args.UserID)
+}
+
+func (r *mutationResolver) UpdateUserStatus(p graphql.ResolveParams, args MutationUpdateUserStatusArgs) (*User, error) {
+	admin, err := RequireAdmin(p, auth.ScopeUsersWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	if !auth.IsValidStatus(args.Status) {
+		return nil, fmt.Errorf("invalid status: %s", args.Status)
+	}
+	if args.UserID == admin.ID {
+		return nil, fmt.Errorf("cannot change your own status")
+	}
+
+	if err := Repos.Users.SetStatus(p.Context, args.UserID, args.Status); err != nil {
+		return nil, err
+	}
+	InvalidateAuthenticatedUser(args.UserID)
+
+	// Suspended and deleted accounts lose every session immediately
+	if args.Status != auth.StatusActive {
+		if err := auth.RevokeAllSessions(p.Context, args.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	return Repos.Users.GetByID(p.Context, args.UserID)
+}
+
+func (r *mutationResolver) SetTwoFactorRequired(p graphql.ResolveParams, args MutationSetTwoFactorRequiredArgs) (*User, error) {
+	if _, err := RequireAdmin(p, auth.ScopeUsersWrite); err != nil {
+		return nil, err
+	}
+
+	if err := Repos.Users.SetTwoFactorRequired(p.Context, args.UserID, args.Required); err != nil {
+		return nil, err
+	}
+	InvalidateAuthenticatedUser(args.UserID)
+
+	return Repos.Users.GetByID(p.Context, args.UserID)
+}
+
+func (r *mutationResolver) AddProduct(p graphql.ResolveParams, args MutationAddProductArgs) (*Product, error) {
+	user, err := RequireAdmin(p, auth.ScopeCatalogWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	return CreateProduct(p.Context, user.ID, map[string]interface{}{
+		"name":       args.Name,
+		"price":      args.Price,
+		"categoryId": args.CategoryID,
+	})
+}
+
+func (r *mutationResolver) EnqueueProductDescription(p graphql.ResolveParams, args MutationEnqueueProductDescriptionArgs) (*jobs.Job, error) {
+	user, err := RequireAdmin(p, auth.ScopeCatalogWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	language, tone := defaultDescriptionOptions(args.Language, args.Tone)
+	return enqueueDescriptionJob(p.Context, user.ID, args.ProductID, language, tone)
+}
+
+func (r *mutationResolver) EnqueueTranslation(p graphql.ResolveParams, args MutationEnqueueTranslationArgs) (*jobs.Job, error) {
+	user, err := RequireScope(p, auth.ScopeCatalogRead)
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs.Enqueue(p.Context, JobTranslateText, translateTextPayload{
+		Text: args.Text,
+		From: args.From,
+		To:   args.To,
+	}, user.ID)
+}
+
+func (r *mutationResolver) RetryJob(p graphql.ResolveParams, args MutationRetryJobArgs) (*jobs.Job, error) {
+	if _, err := RequireAdmin(p, auth.ScopeCatalogWrite); err != nil {
+		return nil, err
+	}
+
+	return jobs.Retry(p.Context, args.ID)
+}
+
+func (r *mutationResolver) RegenerateProductDescription(p graphql.ResolveParams, args MutationRegenerateProductDescriptionArgs) (*Product, error) {
+	if _, err := RequireAdmin(p, auth.ScopeCatalogWrite); err != nil {
+		return nil, err
+	}
+
+	language, tone := defaultDescriptionOptions(args.Language, args.Tone)
+	return generateDescription(p.Context, args.ProductID, language, tone, false)
+}
+
+func (r *mutationResolver) TranslateText(p graphql.ResolveParams, args MutationTranslateTextArgs) (string, error) {
+	translatedText, err := translate(p.Context, args.Text, args.From, args.To)
+	if err != nil {
+		return "", fmt.Errorf("translation failed: %v", err)
+	}
+
+	return translatedText, nil
+}
@@ -0,0 +1,205 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/jobs"
+	"ai-catalog/model"
+	"ai-catalog/repository"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+// useMemory backs the resolvers with an empty in-memory store for the test
+func useMemory(t *testing.T) *repository.Memory {
+	t.Helper()
+	store := repository.NewMemory()
+	previous, previousAuth, previousJobs := Repos, auth.Repos, jobs.Repos
+	repos := store.Repositories()
+	SetRepositories(repos)
+	auth.SetRepositories(repos)
+	jobs.SetRepositories(repos)
+
+	// IDs restart with every store, so cached users of other tests must go
+	authUsersMu.Lock()
+	authUsers = map[int]cachedUser{}
+	authUsersMu.Unlock()
+
+	t.Cleanup(func() {
+		Repos, auth.Repos, jobs.Repos = previous, previousAuth, previousJobs
+	})
+	return store
+}
+
+// useSigningKey configures the key access tokens are signed with
+func useSigningKey(t *testing.T) {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "graph-test-secret")
+	if err := auth.LoadKeysFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// createUser registers an account with the given role
+func createUser(t *testing.T, email, role string) *User {
+	t.Helper()
+	ctx := context.Background()
+	user, err := Repos.Users.Create(ctx, repository.NewUser{Email: email, FirstName: "Test", LastName: "User"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role != user.Role {
+		if err := Repos.Users.SetRole(ctx, user.ID, role); err != nil {
+			t.Fatal(err)
+		}
+		user.Role = role
+	}
+	return user
+}
+
+// asUser returns a request context authenticated as user
+func asUser(user *User) context.Context {
+	return context.WithValue(context.Background(), "user", user)
+}
+
+// execute runs a GraphQL operation and decodes its data into result, failing
+// the test on errors
+func execute(t *testing.T, ctx context.Context, query string, variables map[string]interface{}, result interface{}) {
+	t.Helper()
+	if errs := run(t, ctx, query, variables, result); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+}
+
+// run runs a GraphQL operation, decodes its data into result when given and
+// returns its errors
+func run(t *testing.T, ctx context.Context, query string, variables map[string]interface{}, result interface{}) []error {
+	t.Helper()
+	schema, err := Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := graphql.Do(graphql.Params{
+		Schema:         *schema,
+		RequestString:  query,
+		VariableValues: variables,
+		Context:        ctx,
+	})
+	if result != nil && response.Data != nil {
+		data, err := json.Marshal(response.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	errs := make([]error, len(response.Errors))
+	for i, e := range response.Errors {
+		errs[i] = e
+	}
+	return errs
+}
+
+func TestProductTranslationsAreServedInTheRequestedLocale(t *testing.T) {
+	t.Setenv("CATALOG_DEFAULT_LOCALE", "en")
+	store := useMemory(t)
+	category := store.AddCategory(model.Category{Name: "Phones"})
+	product := store.AddProduct(model.Product{
+		Name: "Phone", Description: "A phone", ShortDescription: "Calls", Price: 100,
+		CategoryID: category.ID, IsActive: true, StockQuantity: 5, SKU: "PHONE-1",
+	})
+	admin := createUser(t, "admin@example.com", auth.RoleAdmin)
+
+	var set struct {
+		SetProductTranslation struct{ Name, Locale string }
+	}
+	execute(t, asUser(admin), `mutation($id: Int!) {
+		setProductTranslation(productId: $id, locale: "ar", name: "هاتف", description: "هاتف ذكي") { name locale }
+	}`, map[string]interface{}{"id": product.ID}, &set)
+	if set.SetProductTranslation.Name != "هاتف" || set.SetProductTranslation.Locale != "ar" {
+		t.Errorf("setProductTranslation returned %+v", set.SetProductTranslation)
+	}
+
+	var got struct {
+		Product struct{ Name, Description, ShortDescription string }
+	}
+	execute(t, context.Background(), `query($id: Int!) {
+		product(id: $id, locale: "ar") { name description shortDescription }
+	}`, map[string]interface{}{"id": product.ID}, &got)
+	want := struct{ Name, Description, ShortDescription string }{"هاتف", "هاتف ذكي", "Calls"}
+	if got.Product != want {
+		t.Errorf("product in ar = %+v, want %+v (untranslated fields fall back)", got.Product, want)
+	}
+
+	// Accept-Language reaches the resolvers as the request locale
+	ctx := context.WithValue(context.Background(), "locale", "en")
+	execute(t, ctx, `query($id: Int!) { product(id: $id) { name description shortDescription } }`,
+		map[string]interface{}{"id": product.ID}, &got)
+	if got.Product.Name != "Phone" {
+		t.Errorf("product in en = %+v, want the default locale text", got.Product)
+	}
+}
+
+func TestLinkedIdentitiesCanBeListedAndUnlinked(t *testing.T) {
+	useMemory(t)
+	user := createUser(t, "user@example.com", auth.RoleCustomer)
+	if err := Repos.Users.MarkEmailVerified(context.Background(), user.ID); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Repos.Identities.Link(context.Background(),
+		repository.NewIdentity{Provider: "google", Subject: "123", Email: "user@example.com"}, repository.NewUser{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var listed struct {
+		MyIdentities []struct{ Provider, Subject string }
+	}
+	execute(t, asUser(user), `{ myIdentities { provider subject } }`, nil, &listed)
+	if len(listed.MyIdentities) != 1 || listed.MyIdentities[0].Provider != "google" {
+		t.Fatalf("myIdentities = %+v", listed.MyIdentities)
+	}
+
+	var unlinked struct{ UnlinkIdentity bool }
+	execute(t, asUser(user), `mutation { unlinkIdentity(provider: "google") }`, nil, &unlinked)
+	if !unlinked.UnlinkIdentity {
+		t.Error("unlinkIdentity reported no linked identity")
+	}
+	execute(t, asUser(user), `{ myIdentities { provider subject } }`, nil, &listed)
+	if len(listed.MyIdentities) != 0 {
+		t.Errorf("myIdentities after unlinking = %+v", listed.MyIdentities)
+	}
+}
+
+func TestLoginAttemptsAreListedForAdmins(t *testing.T) {
+	useMemory(t)
+	admin := createUser(t, "admin@example.com", auth.RoleAdmin)
+	customer := createUser(t, "customer@example.com", auth.RoleCustomer)
+	ctx := context.Background()
+	recordLoginAttempt(ctx, " Customer@Example.com", "198.51.100.1", "test", nil, false, auth.LoginReasonInvalidCredentials)
+	recordLoginAttempt(ctx, "customer@example.com", "198.51.100.1", "test", &customer.ID, true, auth.LoginReasonSuccess)
+	recordLoginAttempt(ctx, "other@example.com", "198.51.100.2", "test", nil, false, auth.LoginReasonInvalidCredentials)
+
+	query := `{ loginAttempts(email: "CUSTOMER@example.com") { email success reason } }`
+	var got struct {
+		LoginAttempts []struct {
+			Email   string
+			Success bool
+			Reason  string
+		}
+	}
+	execute(t, asUser(admin), query, nil, &got)
+	if len(got.LoginAttempts) != 2 {
+		t.Fatalf("loginAttempts = %+v, want the customer's two attempts", got.LoginAttempts)
+	}
+	if !got.LoginAttempts[0].Success || got.LoginAttempts[1].Reason != auth.LoginReasonInvalidCredentials {
+		t.Errorf("loginAttempts = %+v, want newest first", got.LoginAttempts)
+	}
+
+	if errs := run(t, asUser(customer), query, nil, nil); len(errs) == 0 {
+		t.Error("customers can list login attempts")
+	}
+}
@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"ai-catalog/repository"
+	"context"
+	"errors"
+	"strings"
+)
+
+// Errors returned by review operations
+var (
+	ErrReviewNotFound = repository.ErrReviewNotFound
+	ErrReviewNotOwned = errors.New("you can only change your own reviews")
+)
+
+// viewerID returns the ID of the user making the request, or 0 if anonymous
+func viewerID(user *User) int {
+	if user == nil {
+		return 0
+	}
+	return user.ID
+}
+
+// UpdateReview replaces the rating, title and comment of one of user's reviews
+func UpdateReview(ctx context.Context, user *User, id, rating int, title, comment string) (*Review, error) {
+	problems := NewValidationError()
+	if rating < 1 || rating > 5 {
+		problems.Add("rating", "must be between 1 and 5")
+	}
+	if strings.TrimSpace(comment) == "" {
+		problems.Add("comment", "is required")
+	}
+	if err := problems.OrNil(); err != nil {
+		return nil, err
+	}
+
+	review, err := Repos.Reviews.GetByID(ctx, user.ID, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != user.ID {
+		return nil, ErrReviewNotOwned
+	}
+
+	if err := Repos.Reviews.Update(ctx, id, rating, strings.TrimSpace(title), strings.TrimSpace(comment)); err != nil {
+		return nil, err
+	}
+	return Repos.Reviews.GetByID(ctx, user.ID, id)
+}
+
+// DeleteReview deletes a review. Users can delete their own reviews and, with
+// asAdmin from RequireAdmin, any review.
+func DeleteReview(ctx context.Context, user *User, id int, asAdmin bool) error {
+	review, err := Repos.Reviews.GetByID(ctx, user.ID, id)
+	if err != nil {
+		return err
+	}
+	if review.UserID != user.ID && !asAdmin {
+		return ErrReviewNotOwned
+	}
+	return Repos.Reviews.Delete(ctx, id)
+}
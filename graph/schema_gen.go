@@ -0,0 +1,3363 @@
+// Code generated by schemagen from schema.graphqls. DO NOT EDIT.
+
+package graph
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/jobs"
+
+	"github.com/graphql-go/graphql"
+)
+
+// ResolverRoot provides the resolvers for the fields of the schema that are not
+// read directly from the models
+type ResolverRoot interface {
+	Category() CategoryResolver
+	Product() ProductResolver
+	ProductVariant() ProductVariantResolver
+	Order() OrderResolver
+	AuthResponse() AuthResponseResolver
+	Job() JobResolver
+	Query() QueryResolver
+	Mutation() MutationResolver
+}
+
+// CategoryResolver resolves the fields of Category
+type CategoryResolver interface {
+	Locale(p graphql.ResolveParams, obj *Category) (string, error)
+	Parent(p graphql.ResolveParams, obj *Category) (*Category, error)
+	Children(p graphql.ResolveParams, obj *Category) ([]*Category, error)
+	Ancestors(p graphql.ResolveParams, obj *Category) ([]*Category, error)
+	Depth(p graphql.ResolveParams, obj *Category) (int, error)
+}
+
+// ProductResolver resolves the fields of Product
+type ProductResolver interface {
+	Category(p graphql.ResolveParams, obj *Product) (*Category, error)
+	Locale(p graphql.ResolveParams, obj *Product) (string, error)
+	Options(p graphql.ResolveParams, obj *Product) ([]*ProductOption, error)
+	Variants(p graphql.ResolveParams, obj *Product) ([]*ProductVariant, error)
+}
+
+// ProductVariantResolver resolves the fields of ProductVariant
+type ProductVariantResolver interface {
+	Available(p graphql.ResolveParams, obj *ProductVariant) (bool, error)
+}
+
+// OrderResolver resolves the fields of Order
+type OrderResolver interface {
+	User(p graphql.ResolveParams, obj *Order) (*User, error)
+	Items(p graphql.ResolveParams, obj *Order) ([]*OrderItem, error)
+}
+
+// AuthResponseResolver resolves the fields of AuthResponse
+type AuthResponseResolver interface {
+	Token(p graphql.ResolveParams, obj *AuthResponse) (*string, error)
+	RefreshToken(p graphql.ResolveParams, obj *AuthResponse) (*string, error)
+	ChallengeToken(p graphql.ResolveParams, obj *AuthResponse) (*string, error)
+}
+
+// JobResolver resolves the fields of Job
+type JobResolver interface {
+	Result(p graphql.ResolveParams, obj *jobs.Job) (*string, error)
+}
+
+// QueryResolver resolves the fields of Query
+type QueryResolver interface {
+	Me(p graphql.ResolveParams) (*User, error)
+	MyIdentities(p graphql.ResolveParams) ([]*UserIdentity, error)
+	ApiKeys(p graphql.ResolveParams, args QueryApiKeysArgs) ([]*auth.APIKey, error)
+	Categories(p graphql.ResolveParams, args QueryCategoriesArgs) ([]*Category, error)
+	RootCategories(p graphql.ResolveParams, args QueryRootCategoriesArgs) ([]*Category, error)
+	Category(p graphql.ResolveParams, args QueryCategoryArgs) (*Category, error)
+	Products(p graphql.ResolveParams, args QueryProductsArgs) ([]*Product, error)
+	ProductsConnection(p graphql.ResolveParams, args QueryProductsConnectionArgs) (*ProductConnection, error)
+	Product(p graphql.ResolveParams, args QueryProductArgs) (*Product, error)
+	FeaturedProducts(p graphql.ResolveParams, args QueryFeaturedProductsArgs) ([]*Product, error)
+	SearchProducts(p graphql.ResolveParams, args QuerySearchProductsArgs) (*SearchResult, error)
+	SearchSuggestions(p graphql.ResolveParams, args QuerySearchSuggestionsArgs) (*SearchSuggestions, error)
+	Cart(p graphql.ResolveParams) (*CartSummary, error)
+	Wishlist(p graphql.ResolveParams) ([]*WishlistItem, error)
+	WishlistConnection(p graphql.ResolveParams, args QueryWishlistConnectionArgs) (*WishlistConnection, error)
+	Orders(p graphql.ResolveParams) ([]*Order, error)
+	OrdersConnection(p graphql.ResolveParams, args QueryOrdersConnectionArgs) (*OrderConnection, error)
+	Order(p graphql.ResolveParams, args QueryOrderArgs) (*Order, error)
+	ProductReviews(p graphql.ResolveParams, args QueryProductReviewsArgs) ([]*Review, error)
+	ProductReviewsConnection(p graphql.ResolveParams, args QueryProductReviewsConnectionArgs) (*ReviewConnection, error)
+	MyReviews(p graphql.ResolveParams) ([]*Review, error)
+	MyReviewsConnection(p graphql.ResolveParams, args QueryMyReviewsConnectionArgs) (*ReviewConnection, error)
+	Job(p graphql.ResolveParams, args QueryJobArgs) (*jobs.Job, error)
+	TranslationMemory(p graphql.ResolveParams, args QueryTranslationMemoryArgs) ([]*TranslationMemoryEntry, error)
+	LoginAttempts(p graphql.ResolveParams, args QueryLoginAttemptsArgs) ([]*LoginAttempt, error)
+	SearchStats(p graphql.ResolveParams, args QuerySearchStatsArgs) ([]*SearchStat, error)
+}
+
+// QueryApiKeysArgs holds the arguments of Query.apiKeys
+type QueryApiKeysArgs struct {
+	UserID *int
+}
+
+// QueryCategoriesArgs holds the arguments of Query.categories
+type QueryCategoriesArgs struct {
+	Locale *string
+}
+
+// QueryRootCategoriesArgs holds the arguments of Query.rootCategories
+type QueryRootCategoriesArgs struct {
+	Locale *string
+}
+
+// QueryCategoryArgs holds the arguments of Query.category
+type QueryCategoryArgs struct {
+	ID     int
+	Locale *string
+}
+
+// QueryProductsArgs holds the arguments of Query.products
+type QueryProductsArgs struct {
+	CategoryID         *int
+	IncludeDescendants bool
+	Search             *string
+	MinPrice           *float64
+	MaxPrice           *float64
+	IsFeatured         *bool
+	MinRating          *int
+	InStock            *bool
+	Page               *int
+	Limit              *int
+	Locale             *string
+}
+
+// QueryProductsConnectionArgs holds the arguments of Query.productsConnection
+type QueryProductsConnectionArgs struct {
+	CategoryID         *int
+	IncludeDescendants bool
+	Search             *string
+	MinPrice           *float64
+	MaxPrice           *float64
+	IsFeatured         *bool
+	MinRating          *int
+	InStock            *bool
+	Sort               string
+	First              *int
+	After              *string
+	Last               *int
+	Before             *string
+	Locale             *string
+}
+
+// QueryProductArgs holds the arguments of Query.product
+type QueryProductArgs struct {
+	ID     int
+	Locale *string
+}
+
+// QueryFeaturedProductsArgs holds the arguments of Query.featuredProducts
+type QueryFeaturedProductsArgs struct {
+	Locale *string
+}
+
+// QuerySearchProductsArgs holds the arguments of Query.searchProducts
+type QuerySearchProductsArgs struct {
+	Query              string
+	CategoryID         *int
+	IncludeDescendants bool
+	MinPrice           *float64
+	MaxPrice           *float64
+	IsFeatured         *bool
+	MinRating          *int
+	InStock            *bool
+	Locale             *string
+}
+
+// QuerySearchSuggestionsArgs holds the arguments of Query.searchSuggestions
+type QuerySearchSuggestionsArgs struct {
+	Prefix string
+	Limit  int
+	Locale *string
+}
+
+// QueryWishlistConnectionArgs holds the arguments of Query.wishlistConnection
+type QueryWishlistConnectionArgs struct {
+	First  *int
+	After  *string
+	Last   *int
+	Before *string
+}
+
+// QueryOrdersConnectionArgs holds the arguments of Query.ordersConnection
+type QueryOrdersConnectionArgs struct {
+	First  *int
+	After  *string
+	Last   *int
+	Before *string
+}
+
+// QueryOrderArgs holds the arguments of Query.order
+type QueryOrderArgs struct {
+	ID int
+}
+
+// QueryProductReviewsArgs holds the arguments of Query.productReviews
+type QueryProductReviewsArgs struct {
+	ProductID int
+}
+
+// QueryProductReviewsConnectionArgs holds the arguments of Query.productReviewsConnection
+type QueryProductReviewsConnectionArgs struct {
+	ProductID int
+	First     *int
+	After     *string
+	Last      *int
+	Before    *string
+}
+
+// QueryMyReviewsConnectionArgs holds the arguments of Query.myReviewsConnection
+type QueryMyReviewsConnectionArgs struct {
+	First  *int
+	After  *string
+	Last   *int
+	Before *string
+}
+
+// QueryJobArgs holds the arguments of Query.job
+type QueryJobArgs struct {
+	ID int
+}
+
+// QueryTranslationMemoryArgs holds the arguments of Query.translationMemory
+type QueryTranslationMemoryArgs struct {
+	Search        *string
+	SourceLang    *string
+	TargetLang    *string
+	OverridesOnly bool
+	Limit         int
+}
+
+// QueryLoginAttemptsArgs holds the arguments of Query.loginAttempts
+type QueryLoginAttemptsArgs struct {
+	Email     *string
+	IPAddress *string
+	Success   *bool
+	Limit     *int
+}
+
+// QuerySearchStatsArgs holds the arguments of Query.searchStats
+type QuerySearchStatsArgs struct {
+	Days        int
+	ZeroResults bool
+	Limit       int
+}
+
+// MutationResolver resolves the fields of Mutation
+type MutationResolver interface {
+	Register(p graphql.ResolveParams, args MutationRegisterArgs) (*AuthResponse, error)
+	Login(p graphql.ResolveParams, args MutationLoginArgs) (*AuthResponse, error)
+	RefreshToken(p graphql.ResolveParams, args MutationRefreshTokenArgs) (*AuthResponse, error)
+	Logout(p graphql.ResolveParams) (bool, error)
+	LogoutAllDevices(p graphql.ResolveParams) (bool, error)
+	UnlinkIdentity(p graphql.ResolveParams, args MutationUnlinkIdentityArgs) (bool, error)
+	RequestPasswordReset(p graphql.ResolveParams, args MutationRequestPasswordResetArgs) (bool, error)
+	ResetPassword(p graphql.ResolveParams, args MutationResetPasswordArgs) (bool, error)
+	ChangePassword(p graphql.ResolveParams, args MutationChangePasswordArgs) (bool, error)
+	VerifyEmail(p graphql.ResolveParams, args MutationVerifyEmailArgs) (bool, error)
+	ResendVerificationEmail(p graphql.ResolveParams) (bool, error)
+	UpdateProfile(p graphql.ResolveParams, args MutationUpdateProfileArgs) (*User, error)
+	EnableTwoFactor(p graphql.ResolveParams) (*TwoFactorSetup, error)
+	ConfirmTwoFactor(p graphql.ResolveParams, args MutationConfirmTwoFactorArgs) ([]string, error)
+	VerifyTwoFactor(p graphql.ResolveParams, args MutationVerifyTwoFactorArgs) (*AuthResponse, error)
+	RegenerateRecoveryCodes(p graphql.ResolveParams, args MutationRegenerateRecoveryCodesArgs) ([]string, error)
+	DisableTwoFactor(p graphql.ResolveParams, args MutationDisableTwoFactorArgs) (bool, error)
+	CreateApiKey(p graphql.ResolveParams, args MutationCreateApiKeyArgs) (*CreateApiKeyPayload, error)
+	RevokeApiKey(p graphql.ResolveParams, args MutationRevokeApiKeyArgs) (bool, error)
+	AddToCart(p graphql.ResolveParams, args MutationAddToCartArgs) (*CartItem, error)
+	UpdateCartItem(p graphql.ResolveParams, args MutationUpdateCartItemArgs) (*CartItem, error)
+	RemoveFromCart(p graphql.ResolveParams, args MutationRemoveFromCartArgs) (bool, error)
+	ClearCart(p graphql.ResolveParams) (bool, error)
+	AddToWishlist(p graphql.ResolveParams, args MutationAddToWishlistArgs) (*WishlistItem, error)
+	RemoveFromWishlist(p graphql.ResolveParams, args MutationRemoveFromWishlistArgs) (bool, error)
+	CreateOrder(p graphql.ResolveParams, args MutationCreateOrderArgs) (*Order, error)
+	UpdateOrderStatus(p graphql.ResolveParams, args MutationUpdateOrderStatusArgs) (*Order, error)
+	CreateReview(p graphql.ResolveParams, args MutationCreateReviewArgs) (*Review, error)
+	UpdateReview(p graphql.ResolveParams, args MutationUpdateReviewArgs) (*Review, error)
+	DeleteReview(p graphql.ResolveParams, args MutationDeleteReviewArgs) (bool, error)
+	LikeReview(p graphql.ResolveParams, args MutationLikeReviewArgs) (bool, error)
+	UnlikeReview(p graphql.ResolveParams, args MutationUnlikeReviewArgs) (bool, error)
+	LikeProduct(p graphql.ResolveParams, args MutationLikeProductArgs) (bool, error)
+	UnlikeProduct(p graphql.ResolveParams, args MutationUnlikeProductArgs) (bool, error)
+	CreateProduct(p graphql.ResolveParams, args MutationCreateProductArgs) (*Product, error)
+	UpdateProduct(p graphql.ResolveParams, args MutationUpdateProductArgs) (*Product, error)
+	ArchiveProduct(p graphql.ResolveParams, args MutationArchiveProductArgs) (*Product, error)
+	RestoreProduct(p graphql.ResolveParams, args MutationRestoreProductArgs) (*Product, error)
+	DeleteProduct(p graphql.ResolveParams, args MutationDeleteProductArgs) (bool, error)
+	CreateProductVariant(p graphql.ResolveParams, args MutationCreateProductVariantArgs) (*ProductVariant, error)
+	UpdateProductVariant(p graphql.ResolveParams, args MutationUpdateProductVariantArgs) (*ProductVariant, error)
+	DeleteProductVariant(p graphql.ResolveParams, args MutationDeleteProductVariantArgs) (bool, error)
+	CreateCategory(p graphql.ResolveParams, args MutationCreateCategoryArgs) (*Category, error)
+	MoveCategory(p graphql.ResolveParams, args MutationMoveCategoryArgs) (*Category, error)
+	DeleteCategory(p graphql.ResolveParams, args MutationDeleteCategoryArgs) (bool, error)
+	SetProductTranslation(p graphql.ResolveParams, args MutationSetProductTranslationArgs) (*Product, error)
+	SetCategoryTranslation(p graphql.ResolveParams, args MutationSetCategoryTranslationArgs) (*Category, error)
+	FillMissingTranslations(p graphql.ResolveParams, args MutationFillMissingTranslationsArgs) (*jobs.Job, error)
+	SetTranslationOverride(p graphql.ResolveParams, args MutationSetTranslationOverrideArgs) (*TranslationMemoryEntry, error)
+	DeleteTranslationMemoryEntry(p graphql.ResolveParams, args MutationDeleteTranslationMemoryEntryArgs) (bool, error)
+	UpdateUserRole(p graphql.ResolveParams, args MutationUpdateUserRoleArgs) (*User, error)
+	UpdateUserStatus(p graphql.ResolveParams, args MutationUpdateUserStatusArgs) (*User, error)
+	SetTwoFactorRequired(p graphql.ResolveParams, args MutationSetTwoFactorRequiredArgs) (*User, error)
+	EnqueueProductDescription(p graphql.ResolveParams, args MutationEnqueueProductDescriptionArgs) (*jobs.Job, error)
+	EnqueueTranslation(p graphql.ResolveParams, args MutationEnqueueTranslationArgs) (*jobs.Job, error)
+	RetryJob(p graphql.ResolveParams, args MutationRetryJobArgs) (*jobs.Job, error)
+	AddProduct(p graphql.ResolveParams, args MutationAddProductArgs) (*Product, error)
+	RegenerateProductDescription(p graphql.ResolveParams, args MutationRegenerateProductDescriptionArgs) (*Product, error)
+	TranslateText(p graphql.ResolveParams, args MutationTranslateTextArgs) (string, error)
+}
+
+// MutationRegisterArgs holds the arguments of Mutation.register
+type MutationRegisterArgs struct {
+	Input RegisterInput
+}
+
+// MutationLoginArgs holds the arguments of Mutation.login
+type MutationLoginArgs struct {
+	Input LoginInput
+}
+
+// MutationRefreshTokenArgs holds the arguments of Mutation.refreshToken
+type MutationRefreshTokenArgs struct {
+	RefreshToken string
+}
+
+// MutationUnlinkIdentityArgs holds the arguments of Mutation.unlinkIdentity
+type MutationUnlinkIdentityArgs struct {
+	Provider string
+}
+
+// MutationRequestPasswordResetArgs holds the arguments of Mutation.requestPasswordReset
+type MutationRequestPasswordResetArgs struct {
+	Email string
+}
+
+// MutationResetPasswordArgs holds the arguments of Mutation.resetPassword
+type MutationResetPasswordArgs struct {
+	Token       string
+	NewPassword string
+}
+
+// MutationChangePasswordArgs holds the arguments of Mutation.changePassword
+type MutationChangePasswordArgs struct {
+	CurrentPassword string
+	NewPassword     string
+}
+
+// MutationVerifyEmailArgs holds the arguments of Mutation.verifyEmail
+type MutationVerifyEmailArgs struct {
+	Token string
+}
+
+// MutationUpdateProfileArgs holds the arguments of Mutation.updateProfile
+type MutationUpdateProfileArgs struct {
+	Input UpdateUserInput
+}
+
+// MutationConfirmTwoFactorArgs holds the arguments of Mutation.confirmTwoFactor
+type MutationConfirmTwoFactorArgs struct {
+	Code string
+}
+
+// MutationVerifyTwoFactorArgs holds the arguments of Mutation.verifyTwoFactor
+type MutationVerifyTwoFactorArgs struct {
+	ChallengeToken string
+	Code           string
+}
+
+// MutationRegenerateRecoveryCodesArgs holds the arguments of Mutation.regenerateRecoveryCodes
+type MutationRegenerateRecoveryCodesArgs struct {
+	Code string
+}
+
+// MutationDisableTwoFactorArgs holds the arguments of Mutation.disableTwoFactor
+type MutationDisableTwoFactorArgs struct {
+	Code string
+}
+
+// MutationCreateApiKeyArgs holds the arguments of Mutation.createApiKey
+type MutationCreateApiKeyArgs struct {
+	Name          string
+	Scopes        []string
+	ExpiresInDays *int
+}
+
+// MutationRevokeApiKeyArgs holds the arguments of Mutation.revokeApiKey
+type MutationRevokeApiKeyArgs struct {
+	ID int
+}
+
+// MutationAddToCartArgs holds the arguments of Mutation.addToCart
+type MutationAddToCartArgs struct {
+	Input AddToCartInput
+}
+
+// MutationUpdateCartItemArgs holds the arguments of Mutation.updateCartItem
+type MutationUpdateCartItemArgs struct {
+	ID       int
+	Quantity int
+}
+
+// MutationRemoveFromCartArgs holds the arguments of Mutation.removeFromCart
+type MutationRemoveFromCartArgs struct {
+	ID int
+}
+
+// MutationAddToWishlistArgs holds the arguments of Mutation.addToWishlist
+type MutationAddToWishlistArgs struct {
+	ProductID int
+}
+
+// MutationRemoveFromWishlistArgs holds the arguments of Mutation.removeFromWishlist
+type MutationRemoveFromWishlistArgs struct {
+	ProductID int
+}
+
+// MutationCreateOrderArgs holds the arguments of Mutation.createOrder
+type MutationCreateOrderArgs struct {
+	Input CreateOrderInput
+}
+
+// MutationUpdateOrderStatusArgs holds the arguments of Mutation.updateOrderStatus
+type MutationUpdateOrderStatusArgs struct {
+	ID     int
+	Status string
+}
+
+// MutationCreateReviewArgs holds the arguments of Mutation.createReview
+type MutationCreateReviewArgs struct {
+	Input CreateReviewInput
+}
+
+// MutationUpdateReviewArgs holds the arguments of Mutation.updateReview
+type MutationUpdateReviewArgs struct {
+	ID      int
+	Rating  int
+	Title   *string
+	Comment string
+}
+
+// MutationDeleteReviewArgs holds the arguments of Mutation.deleteReview
+type MutationDeleteReviewArgs struct {
+	ID int
+}
+
+// MutationLikeReviewArgs holds the arguments of Mutation.likeReview
+type MutationLikeReviewArgs struct {
+	ID int
+}
+
+// MutationUnlikeReviewArgs holds the arguments of Mutation.unlikeReview
+type MutationUnlikeReviewArgs struct {
+	ID int
+}
+
+// MutationLikeProductArgs holds the arguments of Mutation.likeProduct
+type MutationLikeProductArgs struct {
+	ProductID int
+}
+
+// MutationUnlikeProductArgs holds the arguments of Mutation.unlikeProduct
+type MutationUnlikeProductArgs struct {
+	ProductID int
+}
+
+// MutationCreateProductArgs holds the arguments of Mutation.createProduct
+type MutationCreateProductArgs struct {
+	Input map[string]interface{}
+}
+
+// MutationUpdateProductArgs holds the arguments of Mutation.updateProduct
+type MutationUpdateProductArgs struct {
+	ID    int
+	Input map[string]interface{}
+}
+
+// MutationArchiveProductArgs holds the arguments of Mutation.archiveProduct
+type MutationArchiveProductArgs struct {
+	ID int
+}
+
+// MutationRestoreProductArgs holds the arguments of Mutation.restoreProduct
+type MutationRestoreProductArgs struct {
+	ID int
+}
+
+// MutationDeleteProductArgs holds the arguments of Mutation.deleteProduct
+type MutationDeleteProductArgs struct {
+	ID int
+}
+
+// MutationCreateProductVariantArgs holds the arguments of Mutation.createProductVariant
+type MutationCreateProductVariantArgs struct {
+	Input CreateProductVariantInput
+}
+
+// MutationUpdateProductVariantArgs holds the arguments of Mutation.updateProductVariant
+type MutationUpdateProductVariantArgs struct {
+	ID    int
+	Input UpdateProductVariantInput
+}
+
+// MutationDeleteProductVariantArgs holds the arguments of Mutation.deleteProductVariant
+type MutationDeleteProductVariantArgs struct {
+	ID int
+}
+
+// MutationCreateCategoryArgs holds the arguments of Mutation.createCategory
+type MutationCreateCategoryArgs struct {
+	Input CreateCategoryInput
+}
+
+// MutationMoveCategoryArgs holds the arguments of Mutation.moveCategory
+type MutationMoveCategoryArgs struct {
+	ID       int
+	ParentID *int
+}
+
+// MutationDeleteCategoryArgs holds the arguments of Mutation.deleteCategory
+type MutationDeleteCategoryArgs struct {
+	ID int
+}
+
+// MutationSetProductTranslationArgs holds the arguments of Mutation.setProductTranslation
+type MutationSetProductTranslationArgs struct {
+	ProductID        int
+	Locale           string
+	Name             string
+	Description      *string
+	ShortDescription *string
+}
+
+// MutationSetCategoryTranslationArgs holds the arguments of Mutation.setCategoryTranslation
+type MutationSetCategoryTranslationArgs struct {
+	CategoryID  int
+	Locale      string
+	Name        string
+	Description *string
+}
+
+// MutationFillMissingTranslationsArgs holds the arguments of Mutation.fillMissingTranslations
+type MutationFillMissingTranslationsArgs struct {
+	Locale string
+}
+
+// MutationSetTranslationOverrideArgs holds the arguments of Mutation.setTranslationOverride
+type MutationSetTranslationOverrideArgs struct {
+	Text        string
+	From        string
+	To          string
+	Translation string
+}
+
+// MutationDeleteTranslationMemoryEntryArgs holds the arguments of Mutation.deleteTranslationMemoryEntry
+type MutationDeleteTranslationMemoryEntryArgs struct {
+	ID int
+}
+
+// MutationUpdateUserRoleArgs holds the arguments of Mutation.updateUserRole
+type MutationUpdateUserRoleArgs struct {
+	UserID int
+	Role   string
+}
+
+// MutationUpdateUserStatusArgs holds the arguments of Mutation.updateUserStatus
+type MutationUpdateUserStatusArgs struct {
+	UserID int
+	Status string
+}
+
+// MutationSetTwoFactorRequiredArgs holds the arguments of Mutation.setTwoFactorRequired
+type MutationSetTwoFactorRequiredArgs struct {
+	UserID   int
+	Required bool
+}
+
+// MutationEnqueueProductDescriptionArgs holds the arguments of Mutation.enqueueProductDescription
+type MutationEnqueueProductDescriptionArgs struct {
+	ProductID int
+	Language  string
+	Tone      string
+}
+
+// MutationEnqueueTranslationArgs holds the arguments of Mutation.enqueueTranslation
+type MutationEnqueueTranslationArgs struct {
+	Text string
+	From string
+	To   string
+}
+
+// MutationRetryJobArgs holds the arguments of Mutation.retryJob
+type MutationRetryJobArgs struct {
+	ID int
+}
+
+// MutationAddProductArgs holds the arguments of Mutation.addProduct
+type MutationAddProductArgs struct {
+	Name       string
+	Price      float64
+	CategoryID int
+}
+
+// MutationRegenerateProductDescriptionArgs holds the arguments of Mutation.regenerateProductDescription
+type MutationRegenerateProductDescriptionArgs struct {
+	ProductID int
+	Language  string
+	Tone      string
+}
+
+// MutationTranslateTextArgs holds the arguments of Mutation.translateText
+type MutationTranslateTextArgs struct {
+	Text string
+	From string
+	To   string
+}
+
+// RegisterInput is the RegisterInput input type
+type RegisterInput struct {
+	Email     string
+	Password  string
+	FirstName string
+	LastName  string
+	Phone     *string
+	Address   *string
+	City      *string
+}
+
+// decodeRegisterInput converts a RegisterInput argument value from graphql-go
+func decodeRegisterInput(m map[string]interface{}) RegisterInput {
+	var in RegisterInput
+	in.Email, _ = m["email"].(string)
+	in.Password, _ = m["password"].(string)
+	in.FirstName, _ = m["firstName"].(string)
+	in.LastName, _ = m["lastName"].(string)
+	if v1, ok := m["phone"].(string); ok {
+		in.Phone = &v1
+	}
+	if v1, ok := m["address"].(string); ok {
+		in.Address = &v1
+	}
+	if v1, ok := m["city"].(string); ok {
+		in.City = &v1
+	}
+	return in
+}
+
+// LoginInput is the LoginInput input type
+type LoginInput struct {
+	Email    string
+	Password string
+}
+
+// decodeLoginInput converts a LoginInput argument value from graphql-go
+func decodeLoginInput(m map[string]interface{}) LoginInput {
+	var in LoginInput
+	in.Email, _ = m["email"].(string)
+	in.Password, _ = m["password"].(string)
+	return in
+}
+
+// UpdateUserInput is the UpdateUserInput input type
+type UpdateUserInput struct {
+	FirstName string
+	LastName  string
+	Phone     *string
+	Address   *string
+	City      *string
+}
+
+// decodeUpdateUserInput converts a UpdateUserInput argument value from graphql-go
+func decodeUpdateUserInput(m map[string]interface{}) UpdateUserInput {
+	var in UpdateUserInput
+	in.FirstName, _ = m["firstName"].(string)
+	in.LastName, _ = m["lastName"].(string)
+	if v1, ok := m["phone"].(string); ok {
+		in.Phone = &v1
+	}
+	if v1, ok := m["address"].(string); ok {
+		in.Address = &v1
+	}
+	if v1, ok := m["city"].(string); ok {
+		in.City = &v1
+	}
+	return in
+}
+
+// AddToCartInput is the AddToCartInput input type
+type AddToCartInput struct {
+	ProductID int
+	VariantID *int
+	Quantity  int
+}
+
+// decodeAddToCartInput converts a AddToCartInput argument value from graphql-go
+func decodeAddToCartInput(m map[string]interface{}) AddToCartInput {
+	var in AddToCartInput
+	in.ProductID, _ = m["productId"].(int)
+	if v1, ok := m["variantId"].(int); ok {
+		in.VariantID = &v1
+	}
+	in.Quantity, _ = m["quantity"].(int)
+	return in
+}
+
+// CreateOrderInput is the CreateOrderInput input type
+type CreateOrderInput struct {
+	ShippingAddress string
+	ShippingCity    string
+	ShippingPhone   string
+	PaymentMethod   string
+	Notes           *string
+}
+
+// decodeCreateOrderInput converts a CreateOrderInput argument value from graphql-go
+func decodeCreateOrderInput(m map[string]interface{}) CreateOrderInput {
+	var in CreateOrderInput
+	in.ShippingAddress, _ = m["shippingAddress"].(string)
+	in.ShippingCity, _ = m["shippingCity"].(string)
+	in.ShippingPhone, _ = m["shippingPhone"].(string)
+	in.PaymentMethod, _ = m["paymentMethod"].(string)
+	if v1, ok := m["notes"].(string); ok {
+		in.Notes = &v1
+	}
+	return in
+}
+
+// CreateCategoryInput is the CreateCategoryInput input type
+type CreateCategoryInput struct {
+	Name        string
+	Description *string
+	ImageURL    *string
+	ParentID    *int
+}
+
+// decodeCreateCategoryInput converts a CreateCategoryInput argument value from graphql-go
+func decodeCreateCategoryInput(m map[string]interface{}) CreateCategoryInput {
+	var in CreateCategoryInput
+	in.Name, _ = m["name"].(string)
+	if v1, ok := m["description"].(string); ok {
+		in.Description = &v1
+	}
+	if v1, ok := m["imageUrl"].(string); ok {
+		in.ImageURL = &v1
+	}
+	if v1, ok := m["parentId"].(int); ok {
+		in.ParentID = &v1
+	}
+	return in
+}
+
+// CreateReviewInput is the CreateReviewInput input type
+type CreateReviewInput struct {
+	ProductID int
+	Rating    int
+	Title     *string
+	Comment   string
+}
+
+// decodeCreateReviewInput converts a CreateReviewInput argument value from graphql-go
+func decodeCreateReviewInput(m map[string]interface{}) CreateReviewInput {
+	var in CreateReviewInput
+	in.ProductID, _ = m["productId"].(int)
+	in.Rating, _ = m["rating"].(int)
+	if v1, ok := m["title"].(string); ok {
+		in.Title = &v1
+	}
+	in.Comment, _ = m["comment"].(string)
+	return in
+}
+
+// SelectedOptionInput is the SelectedOptionInput input type
+type SelectedOptionInput struct {
+	Name  string
+	Value string
+}
+
+// decodeSelectedOptionInput converts a SelectedOptionInput argument value from graphql-go
+func decodeSelectedOptionInput(m map[string]interface{}) SelectedOptionInput {
+	var in SelectedOptionInput
+	in.Name, _ = m["name"].(string)
+	in.Value, _ = m["value"].(string)
+	return in
+}
+
+// CreateProductVariantInput is the CreateProductVariantInput input type
+type CreateProductVariantInput struct {
+	ProductID     int
+	Options       []SelectedOptionInput
+	SKU           string
+	Price         float64
+	StockQuantity int
+	Images        []string
+}
+
+// decodeCreateProductVariantInput converts a CreateProductVariantInput argument value from graphql-go
+func decodeCreateProductVariantInput(m map[string]interface{}) CreateProductVariantInput {
+	var in CreateProductVariantInput
+	in.ProductID, _ = m["productId"].(int)
+	if v1, ok := m["options"].([]interface{}); ok {
+		in.Options = make([]SelectedOptionInput, 0, len(v1))
+		for _, item1 := range v1 {
+			var elem1 SelectedOptionInput
+			if v2, ok := item1.(map[string]interface{}); ok {
+				elem1 = decodeSelectedOptionInput(v2)
+			}
+			in.Options = append(in.Options, elem1)
+		}
+	}
+	in.SKU, _ = m["sku"].(string)
+	in.Price, _ = m["price"].(float64)
+	in.StockQuantity, _ = m["stockQuantity"].(int)
+	if v1, ok := m["images"].([]interface{}); ok {
+		in.Images = make([]string, 0, len(v1))
+		for _, item1 := range v1 {
+			var elem1 string
+			elem1, _ = item1.(string)
+			in.Images = append(in.Images, elem1)
+		}
+	}
+	return in
+}
+
+// UpdateProductVariantInput is the UpdateProductVariantInput input type
+type UpdateProductVariantInput struct {
+	SKU           *string
+	Price         *float64
+	StockQuantity *int
+	Images        []string
+	IsActive      *bool
+}
+
+// decodeUpdateProductVariantInput converts a UpdateProductVariantInput argument value from graphql-go
+func decodeUpdateProductVariantInput(m map[string]interface{}) UpdateProductVariantInput {
+	var in UpdateProductVariantInput
+	if v1, ok := m["sku"].(string); ok {
+		in.SKU = &v1
+	}
+	if v1, ok := m["price"].(float64); ok {
+		in.Price = &v1
+	}
+	if v1, ok := m["stockQuantity"].(int); ok {
+		in.StockQuantity = &v1
+	}
+	if v1, ok := m["images"].([]interface{}); ok {
+		in.Images = make([]string, 0, len(v1))
+		for _, item1 := range v1 {
+			var elem1 string
+			elem1, _ = item1.(string)
+			in.Images = append(in.Images, elem1)
+		}
+	}
+	if v1, ok := m["isActive"].(bool); ok {
+		in.IsActive = &v1
+	}
+	return in
+}
+
+// NewSchema builds the schema described by schema.graphqls, resolved by r
+func NewSchema(r ResolverRoot) (graphql.Schema, error) {
+	var userType, categoryType, productType, productVariantType, selectedOptionType, productOptionType, productOptionValueType, cartItemType, wishlistItemType, orderItemType, orderType, reviewType, authResponseType, twoFactorSetupType, userIdentityType, apiKeyType, createApiKeyPayloadType, jobType, translationMemoryEntryType, loginAttemptType, cartSummaryType, searchResultType, productFacetsType, searchSuggestionsType, searchStatType, categoryFacetType, priceRangeFacetType, ratingFacetType, pageInfoType, productEdgeType, productConnectionType, orderEdgeType, orderConnectionType, reviewEdgeType, reviewConnectionType, wishlistItemEdgeType, wishlistConnectionType, queryType, mutationType *graphql.Object
+	var registerInputType, loginInputType, updateUserInputType, addToCartInputType, createOrderInputType, createCategoryInputType, createReviewInputType, createProductInputType, updateProductInputType, selectedOptionInputType, createProductVariantInputType, updateProductVariantInputType *graphql.InputObject
+
+	registerInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "RegisterInput",
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			return graphql.InputObjectConfigFieldMap{
+				"email":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"password":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"firstName": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"lastName":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"phone":     &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"address":   &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"city":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+			}
+		}),
+	})
+
+	loginInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "LoginInput",
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			return graphql.InputObjectConfigFieldMap{
+				"email":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"password": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			}
+		}),
+	})
+
+	updateUserInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "UpdateUserInput",
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			return graphql.InputObjectConfigFieldMap{
+				"firstName": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"lastName":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"phone":     &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"address":   &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"city":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+			}
+		}),
+	})
+
+	addToCartInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "AddToCartInput",
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			return graphql.InputObjectConfigFieldMap{
+				"productId": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+				"variantId": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+				"quantity":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+			}
+		}),
+	})
+
+	createOrderInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreateOrderInput",
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			return graphql.InputObjectConfigFieldMap{
+				"shippingAddress": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"shippingCity":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"shippingPhone":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"paymentMethod":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"notes":           &graphql.InputObjectFieldConfig{Type: graphql.String},
+			}
+		}),
+	})
+
+	createCategoryInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreateCategoryInput",
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			return graphql.InputObjectConfigFieldMap{
+				"name":        &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"description": &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"imageUrl":    &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"parentId":    &graphql.InputObjectFieldConfig{Type: graphql.Int},
+			}
+		}),
+	})
+
+	createReviewInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreateReviewInput",
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			return graphql.InputObjectConfigFieldMap{
+				"productId": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+				"rating":    &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+				"title":     &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"comment":   &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			}
+		}),
+	})
+
+	createProductInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreateProductInput",
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			return graphql.InputObjectConfigFieldMap{
+				"name":                &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"price":               &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+				"categoryId":          &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+				"originalPrice":       &graphql.InputObjectFieldConfig{Type: graphql.Float},
+				"description":         &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"shortDescription":    &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"imageUrl":            &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"stockQuantity":       &graphql.InputObjectFieldConfig{Type: graphql.Int},
+				"sku":                 &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"weight":              &graphql.InputObjectFieldConfig{Type: graphql.Float},
+				"dimensions":          &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"isActive":            &graphql.InputObjectFieldConfig{Type: graphql.Boolean},
+				"isFeatured":          &graphql.InputObjectFieldConfig{Type: graphql.Boolean},
+				"descriptionLanguage": &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"descriptionTone":     &graphql.InputObjectFieldConfig{Type: graphql.String},
+			}
+		}),
+	})
+
+	updateProductInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "UpdateProductInput",
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			return graphql.InputObjectConfigFieldMap{
+				"name":             &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"price":            &graphql.InputObjectFieldConfig{Type: graphql.Float},
+				"categoryId":       &graphql.InputObjectFieldConfig{Type: graphql.Int},
+				"originalPrice":    &graphql.InputObjectFieldConfig{Type: graphql.Float},
+				"description":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"shortDescription": &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"imageUrl":         &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"stockQuantity":    &graphql.InputObjectFieldConfig{Type: graphql.Int},
+				"sku":              &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"weight":           &graphql.InputObjectFieldConfig{Type: graphql.Float},
+				"dimensions":       &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"isActive":         &graphql.InputObjectFieldConfig{Type: graphql.Boolean},
+				"isFeatured":       &graphql.InputObjectFieldConfig{Type: graphql.Boolean},
+			}
+		}),
+	})
+
+	selectedOptionInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "SelectedOptionInput",
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			return graphql.InputObjectConfigFieldMap{
+				"name":  &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"value": &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+			}
+		}),
+	})
+
+	createProductVariantInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreateProductVariantInput",
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			return graphql.InputObjectConfigFieldMap{
+				"productId":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Int)},
+				"options":       &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(selectedOptionInputType)))},
+				"sku":           &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+				"price":         &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+				"stockQuantity": &graphql.InputObjectFieldConfig{Type: graphql.Int, DefaultValue: 0},
+				"images":        &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+			}
+		}),
+	})
+
+	updateProductVariantInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "UpdateProductVariantInput",
+		Fields: graphql.InputObjectConfigFieldMapThunk(func() graphql.InputObjectConfigFieldMap {
+			return graphql.InputObjectConfigFieldMap{
+				"sku":           &graphql.InputObjectFieldConfig{Type: graphql.String},
+				"price":         &graphql.InputObjectFieldConfig{Type: graphql.Float},
+				"stockQuantity": &graphql.InputObjectFieldConfig{Type: graphql.Int},
+				"images":        &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+				"isActive":      &graphql.InputObjectFieldConfig{Type: graphql.Boolean},
+			}
+		}),
+	})
+
+	userType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"email": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"firstName": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"lastName": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"phone": &graphql.Field{
+					Type: graphql.String,
+				},
+				"address": &graphql.Field{
+					Type: graphql.String,
+				},
+				"city": &graphql.Field{
+					Type: graphql.String,
+				},
+				"country": &graphql.Field{
+					Type: graphql.String,
+				},
+				"role": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"emailVerified": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+				},
+				"status": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"twoFactorEnabled": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+				},
+				"twoFactorRequired": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+				},
+				"createdAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"updatedAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			}
+		}),
+	})
+
+	categoryType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Category",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"name": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"description": &graphql.Field{
+					Type: graphql.String,
+				},
+				"imageUrl": &graphql.Field{
+					Type: graphql.String,
+				},
+				"parentId": &graphql.Field{
+					Type: graphql.Int,
+				},
+				"createdAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"locale": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						obj, ok := p.Source.(*Category)
+						if !ok {
+							return nil, nil
+						}
+						return r.Category().Locale(p, obj)
+					},
+				},
+				"parent": &graphql.Field{
+					Type: categoryType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						obj, ok := p.Source.(*Category)
+						if !ok {
+							return nil, nil
+						}
+						return r.Category().Parent(p, obj)
+					},
+				},
+				"children": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(categoryType))),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						obj, ok := p.Source.(*Category)
+						if !ok {
+							return nil, nil
+						}
+						return r.Category().Children(p, obj)
+					},
+				},
+				"ancestors": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(categoryType))),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						obj, ok := p.Source.(*Category)
+						if !ok {
+							return nil, nil
+						}
+						return r.Category().Ancestors(p, obj)
+					},
+				},
+				"depth": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						obj, ok := p.Source.(*Category)
+						if !ok {
+							return nil, nil
+						}
+						return r.Category().Depth(p, obj)
+					},
+				},
+			}
+		}),
+	})
+
+	productType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Product",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"name": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"price": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Float),
+				},
+				"originalPrice": &graphql.Field{
+					Type: graphql.Float,
+				},
+				"categoryId": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"category": &graphql.Field{
+					Type: categoryType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						obj, ok := p.Source.(*Product)
+						if !ok {
+							return nil, nil
+						}
+						return r.Product().Category(p, obj)
+					},
+				},
+				"description": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"shortDescription": &graphql.Field{
+					Type: graphql.String,
+				},
+				"imageUrl": &graphql.Field{
+					Type: graphql.String,
+				},
+				"stockQuantity": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"sku": &graphql.Field{
+					Type: graphql.String,
+				},
+				"weight": &graphql.Field{
+					Type: graphql.Float,
+				},
+				"dimensions": &graphql.Field{
+					Type: graphql.String,
+				},
+				"isActive": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+				},
+				"isFeatured": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+				},
+				"createdAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"updatedAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"averageRating": &graphql.Field{
+					Type: graphql.Float,
+				},
+				"reviewCount": &graphql.Field{
+					Type: graphql.Int,
+				},
+				"isInWishlist": &graphql.Field{
+					Type: graphql.Boolean,
+				},
+				"isLiked": &graphql.Field{
+					Type: graphql.Boolean,
+				},
+				"locale": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						obj, ok := p.Source.(*Product)
+						if !ok {
+							return nil, nil
+						}
+						return r.Product().Locale(p, obj)
+					},
+				},
+				"options": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(productOptionType))),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						obj, ok := p.Source.(*Product)
+						if !ok {
+							return nil, nil
+						}
+						return r.Product().Options(p, obj)
+					},
+				},
+				"variants": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(productVariantType))),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						obj, ok := p.Source.(*Product)
+						if !ok {
+							return nil, nil
+						}
+						return r.Product().Variants(p, obj)
+					},
+				},
+			}
+		}),
+	})
+
+	productVariantType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "ProductVariant",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"productId": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"sku": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"price": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Float),
+				},
+				"stockQuantity": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"options": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(selectedOptionType))),
+				},
+				"images": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				},
+				"isActive": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+				},
+				"available": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						obj, ok := p.Source.(*ProductVariant)
+						if !ok {
+							return nil, nil
+						}
+						return r.ProductVariant().Available(p, obj)
+					},
+				},
+				"createdAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"updatedAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			}
+		}),
+	})
+
+	selectedOptionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "SelectedOption",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"name": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"value": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			}
+		}),
+	})
+
+	productOptionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "ProductOption",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"name": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"values": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(productOptionValueType))),
+				},
+			}
+		}),
+	})
+
+	productOptionValueType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "ProductOptionValue",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"value": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"available": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+				},
+			}
+		}),
+	})
+
+	cartItemType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "CartItem",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"userId": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"productId": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"product": &graphql.Field{
+					Type: productType,
+				},
+				"variantId": &graphql.Field{
+					Type: graphql.Int,
+				},
+				"variant": &graphql.Field{
+					Type: productVariantType,
+				},
+				"quantity": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"available": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+				},
+				"createdAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"updatedAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			}
+		}),
+	})
+
+	wishlistItemType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "WishlistItem",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"userId": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"productId": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"product": &graphql.Field{
+					Type: productType,
+				},
+				"createdAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			}
+		}),
+	})
+
+	orderItemType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "OrderItem",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"orderId": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"productId": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"productName": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"productPrice": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Float),
+				},
+				"variantId": &graphql.Field{
+					Type: graphql.Int,
+				},
+				"variantName": &graphql.Field{
+					Type: graphql.String,
+				},
+				"quantity": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"totalPrice": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Float),
+				},
+				"createdAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			}
+		}),
+	})
+
+	orderType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Order",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"userId": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"user": &graphql.Field{
+					Type: userType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						obj, ok := p.Source.(*Order)
+						if !ok {
+							return nil, nil
+						}
+						return r.Order().User(p, obj)
+					},
+				},
+				"orderNumber": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"status": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"totalAmount": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Float),
+				},
+				"shippingAddress": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"shippingCity": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"shippingCountry": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"shippingPhone": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"paymentMethod": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"paymentStatus": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"notes": &graphql.Field{
+					Type: graphql.String,
+				},
+				"createdAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"updatedAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"items": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(orderItemType))),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						obj, ok := p.Source.(*Order)
+						if !ok {
+							return nil, nil
+						}
+						return r.Order().Items(p, obj)
+					},
+				},
+			}
+		}),
+	})
+
+	reviewType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Review",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"userId": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"user": &graphql.Field{
+					Type: userType,
+				},
+				"productId": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"rating": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"title": &graphql.Field{
+					Type: graphql.String,
+				},
+				"comment": &graphql.Field{
+					Type: graphql.String,
+				},
+				"isVerifiedPurchase": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+				},
+				"createdAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"updatedAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"likeCount": &graphql.Field{
+					Type: graphql.Int,
+				},
+				"isLiked": &graphql.Field{
+					Type: graphql.Boolean,
+				},
+			}
+		}),
+	})
+
+	authResponseType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "AuthResponse",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"user": &graphql.Field{
+					Type: userType,
+				},
+				"token": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						obj, ok := p.Source.(*AuthResponse)
+						if !ok {
+							return nil, nil
+						}
+						return r.AuthResponse().Token(p, obj)
+					},
+				},
+				"refreshToken": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						obj, ok := p.Source.(*AuthResponse)
+						if !ok {
+							return nil, nil
+						}
+						return r.AuthResponse().RefreshToken(p, obj)
+					},
+				},
+				"expiresAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"twoFactorRequired": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+				},
+				"challengeToken": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						obj, ok := p.Source.(*AuthResponse)
+						if !ok {
+							return nil, nil
+						}
+						return r.AuthResponse().ChallengeToken(p, obj)
+					},
+				},
+				"twoFactorSetupRequired": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+				},
+			}
+		}),
+	})
+
+	twoFactorSetupType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "TwoFactorSetup",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"secret": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"provisioningUri": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			}
+		}),
+	})
+
+	userIdentityType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "UserIdentity",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"provider": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"subject": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"email": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"lastLoginAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"createdAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			}
+		}),
+	})
+
+	apiKeyType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "ApiKey",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"userId": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"name": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"prefix": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"scopes": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				},
+				"lastUsedAt": &graphql.Field{
+					Type: graphql.String,
+				},
+				"expiresAt": &graphql.Field{
+					Type: graphql.String,
+				},
+				"revokedAt": &graphql.Field{
+					Type: graphql.String,
+				},
+				"createdAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			}
+		}),
+	})
+
+	createApiKeyPayloadType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "CreateApiKeyPayload",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"apiKey": &graphql.Field{
+					Type: graphql.NewNonNull(apiKeyType),
+				},
+				"key": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			}
+		}),
+	})
+
+	jobType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Job",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"type": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"status": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"attempts": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"maxAttempts": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"error": &graphql.Field{
+					Type: graphql.String,
+				},
+				"result": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						obj, ok := p.Source.(*jobs.Job)
+						if !ok {
+							return nil, nil
+						}
+						return r.Job().Result(p, obj)
+					},
+				},
+				"runAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"createdAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"updatedAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"completedAt": &graphql.Field{
+					Type: graphql.String,
+				},
+			}
+		}),
+	})
+
+	translationMemoryEntryType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "TranslationMemoryEntry",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"sourceText": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"sourceLang": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"targetLang": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"model": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"translatedText": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"isOverride": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+				},
+				"hitCount": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"lastUsedAt": &graphql.Field{
+					Type: graphql.String,
+				},
+				"updatedBy": &graphql.Field{
+					Type: graphql.Int,
+				},
+				"createdAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"updatedAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			}
+		}),
+	})
+
+	loginAttemptType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "LoginAttempt",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"email": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"ipAddress": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"userAgent": &graphql.Field{
+					Type: graphql.String,
+				},
+				"userId": &graphql.Field{
+					Type: graphql.Int,
+				},
+				"success": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+				},
+				"reason": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"createdAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			}
+		}),
+	})
+
+	cartSummaryType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "CartSummary",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"items": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(cartItemType))),
+				},
+				"totalItems": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"totalPrice": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Float),
+				},
+			}
+		}),
+	})
+
+	searchResultType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "SearchResult",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"products": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(productType))),
+				},
+				"categories": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(categoryType))),
+				},
+				"total": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"facets": &graphql.Field{
+					Type: graphql.NewNonNull(productFacetsType),
+				},
+			}
+		}),
+	})
+
+	productFacetsType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "ProductFacets",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"categories": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(categoryFacetType))),
+				},
+				"priceRanges": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(priceRangeFacetType))),
+				},
+				"ratings": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ratingFacetType))),
+				},
+				"inStock": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"outOfStock": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+			}
+		}),
+	})
+
+	searchSuggestionsType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "SearchSuggestions",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"queries": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				},
+				"products": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(productType))),
+				},
+				"categories": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(categoryType))),
+				},
+			}
+		}),
+	})
+
+	searchStatType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "SearchStat",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"query": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"searches": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"lastSearchedAt": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+			}
+		}),
+	})
+
+	categoryFacetType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "CategoryFacet",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"category": &graphql.Field{
+					Type: graphql.NewNonNull(categoryType),
+				},
+				"count": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+			}
+		}),
+	})
+
+	priceRangeFacetType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "PriceRangeFacet",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"min": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Float),
+				},
+				"max": &graphql.Field{
+					Type: graphql.Float,
+				},
+				"count": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+			}
+		}),
+	})
+
+	ratingFacetType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "RatingFacet",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"minRating": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+				"count": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+			}
+		}),
+	})
+
+	pageInfoType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"hasNextPage": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+				},
+				"hasPreviousPage": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+				},
+				"startCursor": &graphql.Field{
+					Type: graphql.String,
+				},
+				"endCursor": &graphql.Field{
+					Type: graphql.String,
+				},
+			}
+		}),
+	})
+
+	productEdgeType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "ProductEdge",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"cursor": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"node": &graphql.Field{
+					Type: graphql.NewNonNull(productType),
+				},
+			}
+		}),
+	})
+
+	productConnectionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "ProductConnection",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"edges": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(productEdgeType))),
+				},
+				"pageInfo": &graphql.Field{
+					Type: graphql.NewNonNull(pageInfoType),
+				},
+				"totalCount": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+			}
+		}),
+	})
+
+	orderEdgeType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "OrderEdge",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"cursor": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"node": &graphql.Field{
+					Type: graphql.NewNonNull(orderType),
+				},
+			}
+		}),
+	})
+
+	orderConnectionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "OrderConnection",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"edges": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(orderEdgeType))),
+				},
+				"pageInfo": &graphql.Field{
+					Type: graphql.NewNonNull(pageInfoType),
+				},
+				"totalCount": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+			}
+		}),
+	})
+
+	reviewEdgeType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "ReviewEdge",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"cursor": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"node": &graphql.Field{
+					Type: graphql.NewNonNull(reviewType),
+				},
+			}
+		}),
+	})
+
+	reviewConnectionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "ReviewConnection",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"edges": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(reviewEdgeType))),
+				},
+				"pageInfo": &graphql.Field{
+					Type: graphql.NewNonNull(pageInfoType),
+				},
+				"totalCount": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+			}
+		}),
+	})
+
+	wishlistItemEdgeType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "WishlistItemEdge",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"cursor": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+				},
+				"node": &graphql.Field{
+					Type: graphql.NewNonNull(wishlistItemType),
+				},
+			}
+		}),
+	})
+
+	wishlistConnectionType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "WishlistConnection",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"edges": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(wishlistItemEdgeType))),
+				},
+				"pageInfo": &graphql.Field{
+					Type: graphql.NewNonNull(pageInfoType),
+				},
+				"totalCount": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Int),
+				},
+			}
+		}),
+	})
+
+	queryType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"me": &graphql.Field{
+					Type: userType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return r.Query().Me(p)
+					},
+				},
+				"myIdentities": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(userIdentityType))),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return r.Query().MyIdentities(p)
+					},
+				},
+				"apiKeys": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(apiKeyType))),
+					Args: graphql.FieldConfigArgument{
+						"userId": &graphql.ArgumentConfig{Type: graphql.Int},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QueryApiKeysArgs
+						if v1, ok := p.Args["userId"].(int); ok {
+							args.UserID = &v1
+						}
+						return r.Query().ApiKeys(p, args)
+					},
+				},
+				"categories": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(categoryType))),
+					Args: graphql.FieldConfigArgument{
+						"locale": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QueryCategoriesArgs
+						if v1, ok := p.Args["locale"].(string); ok {
+							args.Locale = &v1
+						}
+						return r.Query().Categories(p, args)
+					},
+				},
+				"rootCategories": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(categoryType))),
+					Args: graphql.FieldConfigArgument{
+						"locale": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QueryRootCategoriesArgs
+						if v1, ok := p.Args["locale"].(string); ok {
+							args.Locale = &v1
+						}
+						return r.Query().RootCategories(p, args)
+					},
+				},
+				"category": &graphql.Field{
+					Type: categoryType,
+					Args: graphql.FieldConfigArgument{
+						"id":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+						"locale": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QueryCategoryArgs
+						args.ID, _ = p.Args["id"].(int)
+						if v1, ok := p.Args["locale"].(string); ok {
+							args.Locale = &v1
+						}
+						return r.Query().Category(p, args)
+					},
+				},
+				"products": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(productType))),
+					Args: graphql.FieldConfigArgument{
+						"categoryId":         &graphql.ArgumentConfig{Type: graphql.Int},
+						"includeDescendants": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+						"search":             &graphql.ArgumentConfig{Type: graphql.String},
+						"minPrice":           &graphql.ArgumentConfig{Type: graphql.Float},
+						"maxPrice":           &graphql.ArgumentConfig{Type: graphql.Float},
+						"isFeatured":         &graphql.ArgumentConfig{Type: graphql.Boolean},
+						"minRating":          &graphql.ArgumentConfig{Type: graphql.Int},
+						"inStock":            &graphql.ArgumentConfig{Type: graphql.Boolean},
+						"page":               &graphql.ArgumentConfig{Type: graphql.Int},
+						"limit":              &graphql.ArgumentConfig{Type: graphql.Int},
+						"locale":             &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QueryProductsArgs
+						if v1, ok := p.Args["categoryId"].(int); ok {
+							args.CategoryID = &v1
+						}
+						args.IncludeDescendants, _ = p.Args["includeDescendants"].(bool)
+						if v1, ok := p.Args["search"].(string); ok {
+							args.Search = &v1
+						}
+						if v1, ok := p.Args["minPrice"].(float64); ok {
+							args.MinPrice = &v1
+						}
+						if v1, ok := p.Args["maxPrice"].(float64); ok {
+							args.MaxPrice = &v1
+						}
+						if v1, ok := p.Args["isFeatured"].(bool); ok {
+							args.IsFeatured = &v1
+						}
+						if v1, ok := p.Args["minRating"].(int); ok {
+							args.MinRating = &v1
+						}
+						if v1, ok := p.Args["inStock"].(bool); ok {
+							args.InStock = &v1
+						}
+						if v1, ok := p.Args["page"].(int); ok {
+							args.Page = &v1
+						}
+						if v1, ok := p.Args["limit"].(int); ok {
+							args.Limit = &v1
+						}
+						if v1, ok := p.Args["locale"].(string); ok {
+							args.Locale = &v1
+						}
+						return r.Query().Products(p, args)
+					},
+				},
+				"productsConnection": &graphql.Field{
+					Type: graphql.NewNonNull(productConnectionType),
+					Args: graphql.FieldConfigArgument{
+						"categoryId":         &graphql.ArgumentConfig{Type: graphql.Int},
+						"includeDescendants": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+						"search":             &graphql.ArgumentConfig{Type: graphql.String},
+						"minPrice":           &graphql.ArgumentConfig{Type: graphql.Float},
+						"maxPrice":           &graphql.ArgumentConfig{Type: graphql.Float},
+						"isFeatured":         &graphql.ArgumentConfig{Type: graphql.Boolean},
+						"minRating":          &graphql.ArgumentConfig{Type: graphql.Int},
+						"inStock":            &graphql.ArgumentConfig{Type: graphql.Boolean},
+						"sort":               &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: "newest"},
+						"first":              &graphql.ArgumentConfig{Type: graphql.Int},
+						"after":              &graphql.ArgumentConfig{Type: graphql.String},
+						"last":               &graphql.ArgumentConfig{Type: graphql.Int},
+						"before":             &graphql.ArgumentConfig{Type: graphql.String},
+						"locale":             &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QueryProductsConnectionArgs
+						if v1, ok := p.Args["categoryId"].(int); ok {
+							args.CategoryID = &v1
+						}
+						args.IncludeDescendants, _ = p.Args["includeDescendants"].(bool)
+						if v1, ok := p.Args["search"].(string); ok {
+							args.Search = &v1
+						}
+						if v1, ok := p.Args["minPrice"].(float64); ok {
+							args.MinPrice = &v1
+						}
+						if v1, ok := p.Args["maxPrice"].(float64); ok {
+							args.MaxPrice = &v1
+						}
+						if v1, ok := p.Args["isFeatured"].(bool); ok {
+							args.IsFeatured = &v1
+						}
+						if v1, ok := p.Args["minRating"].(int); ok {
+							args.MinRating = &v1
+						}
+						if v1, ok := p.Args["inStock"].(bool); ok {
+							args.InStock = &v1
+						}
+						args.Sort, _ = p.Args["sort"].(string)
+						if v1, ok := p.Args["first"].(int); ok {
+							args.First = &v1
+						}
+						if v1, ok := p.Args["after"].(string); ok {
+							args.After = &v1
+						}
+						if v1, ok := p.Args["last"].(int); ok {
+							args.Last = &v1
+						}
+						if v1, ok := p.Args["before"].(string); ok {
+							args.Before = &v1
+						}
+						if v1, ok := p.Args["locale"].(string); ok {
+							args.Locale = &v1
+						}
+						return r.Query().ProductsConnection(p, args)
+					},
+				},
+				"product": &graphql.Field{
+					Type: productType,
+					Args: graphql.FieldConfigArgument{
+						"id":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+						"locale": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QueryProductArgs
+						args.ID, _ = p.Args["id"].(int)
+						if v1, ok := p.Args["locale"].(string); ok {
+							args.Locale = &v1
+						}
+						return r.Query().Product(p, args)
+					},
+				},
+				"featuredProducts": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(productType))),
+					Args: graphql.FieldConfigArgument{
+						"locale": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QueryFeaturedProductsArgs
+						if v1, ok := p.Args["locale"].(string); ok {
+							args.Locale = &v1
+						}
+						return r.Query().FeaturedProducts(p, args)
+					},
+				},
+				"searchProducts": &graphql.Field{
+					Type: graphql.NewNonNull(searchResultType),
+					Args: graphql.FieldConfigArgument{
+						"query":              &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+						"categoryId":         &graphql.ArgumentConfig{Type: graphql.Int},
+						"includeDescendants": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+						"minPrice":           &graphql.ArgumentConfig{Type: graphql.Float},
+						"maxPrice":           &graphql.ArgumentConfig{Type: graphql.Float},
+						"isFeatured":         &graphql.ArgumentConfig{Type: graphql.Boolean},
+						"minRating":          &graphql.ArgumentConfig{Type: graphql.Int},
+						"inStock":            &graphql.ArgumentConfig{Type: graphql.Boolean},
+						"locale":             &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QuerySearchProductsArgs
+						args.Query, _ = p.Args["query"].(string)
+						if v1, ok := p.Args["categoryId"].(int); ok {
+							args.CategoryID = &v1
+						}
+						args.IncludeDescendants, _ = p.Args["includeDescendants"].(bool)
+						if v1, ok := p.Args["minPrice"].(float64); ok {
+							args.MinPrice = &v1
+						}
+						if v1, ok := p.Args["maxPrice"].(float64); ok {
+							args.MaxPrice = &v1
+						}
+						if v1, ok := p.Args["isFeatured"].(bool); ok {
+							args.IsFeatured = &v1
+						}
+						if v1, ok := p.Args["minRating"].(int); ok {
+							args.MinRating = &v1
+						}
+						if v1, ok := p.Args["inStock"].(bool); ok {
+							args.InStock = &v1
+						}
+						if v1, ok := p.Args["locale"].(string); ok {
+							args.Locale = &v1
+						}
+						return r.Query().SearchProducts(p, args)
+					},
+				},
+				"searchSuggestions": &graphql.Field{
+					Type: graphql.NewNonNull(searchSuggestionsType),
+					Args: graphql.FieldConfigArgument{
+						"prefix": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+						"limit":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 5},
+						"locale": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QuerySearchSuggestionsArgs
+						args.Prefix, _ = p.Args["prefix"].(string)
+						args.Limit, _ = p.Args["limit"].(int)
+						if v1, ok := p.Args["locale"].(string); ok {
+							args.Locale = &v1
+						}
+						return r.Query().SearchSuggestions(p, args)
+					},
+				},
+				"cart": &graphql.Field{
+					Type: graphql.NewNonNull(cartSummaryType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return r.Query().Cart(p)
+					},
+				},
+				"wishlist": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(wishlistItemType))),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return r.Query().Wishlist(p)
+					},
+				},
+				"wishlistConnection": &graphql.Field{
+					Type: graphql.NewNonNull(wishlistConnectionType),
+					Args: graphql.FieldConfigArgument{
+						"first":  &graphql.ArgumentConfig{Type: graphql.Int},
+						"after":  &graphql.ArgumentConfig{Type: graphql.String},
+						"last":   &graphql.ArgumentConfig{Type: graphql.Int},
+						"before": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QueryWishlistConnectionArgs
+						if v1, ok := p.Args["first"].(int); ok {
+							args.First = &v1
+						}
+						if v1, ok := p.Args["after"].(string); ok {
+							args.After = &v1
+						}
+						if v1, ok := p.Args["last"].(int); ok {
+							args.Last = &v1
+						}
+						if v1, ok := p.Args["before"].(string); ok {
+							args.Before = &v1
+						}
+						return r.Query().WishlistConnection(p, args)
+					},
+				},
+				"orders": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(orderType))),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return r.Query().Orders(p)
+					},
+				},
+				"ordersConnection": &graphql.Field{
+					Type: graphql.NewNonNull(orderConnectionType),
+					Args: graphql.FieldConfigArgument{
+						"first":  &graphql.ArgumentConfig{Type: graphql.Int},
+						"after":  &graphql.ArgumentConfig{Type: graphql.String},
+						"last":   &graphql.ArgumentConfig{Type: graphql.Int},
+						"before": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QueryOrdersConnectionArgs
+						if v1, ok := p.Args["first"].(int); ok {
+							args.First = &v1
+						}
+						if v1, ok := p.Args["after"].(string); ok {
+							args.After = &v1
+						}
+						if v1, ok := p.Args["last"].(int); ok {
+							args.Last = &v1
+						}
+						if v1, ok := p.Args["before"].(string); ok {
+							args.Before = &v1
+						}
+						return r.Query().OrdersConnection(p, args)
+					},
+				},
+				"order": &graphql.Field{
+					Type: orderType,
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QueryOrderArgs
+						args.ID, _ = p.Args["id"].(int)
+						return r.Query().Order(p, args)
+					},
+				},
+				"productReviews": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(reviewType))),
+					Args: graphql.FieldConfigArgument{
+						"productId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QueryProductReviewsArgs
+						args.ProductID, _ = p.Args["productId"].(int)
+						return r.Query().ProductReviews(p, args)
+					},
+				},
+				"productReviewsConnection": &graphql.Field{
+					Type: graphql.NewNonNull(reviewConnectionType),
+					Args: graphql.FieldConfigArgument{
+						"productId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+						"first":     &graphql.ArgumentConfig{Type: graphql.Int},
+						"after":     &graphql.ArgumentConfig{Type: graphql.String},
+						"last":      &graphql.ArgumentConfig{Type: graphql.Int},
+						"before":    &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QueryProductReviewsConnectionArgs
+						args.ProductID, _ = p.Args["productId"].(int)
+						if v1, ok := p.Args["first"].(int); ok {
+							args.First = &v1
+						}
+						if v1, ok := p.Args["after"].(string); ok {
+							args.After = &v1
+						}
+						if v1, ok := p.Args["last"].(int); ok {
+							args.Last = &v1
+						}
+						if v1, ok := p.Args["before"].(string); ok {
+							args.Before = &v1
+						}
+						return r.Query().ProductReviewsConnection(p, args)
+					},
+				},
+				"myReviews": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(reviewType))),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return r.Query().MyReviews(p)
+					},
+				},
+				"myReviewsConnection": &graphql.Field{
+					Type: graphql.NewNonNull(reviewConnectionType),
+					Args: graphql.FieldConfigArgument{
+						"first":  &graphql.ArgumentConfig{Type: graphql.Int},
+						"after":  &graphql.ArgumentConfig{Type: graphql.String},
+						"last":   &graphql.ArgumentConfig{Type: graphql.Int},
+						"before": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QueryMyReviewsConnectionArgs
+						if v1, ok := p.Args["first"].(int); ok {
+							args.First = &v1
+						}
+						if v1, ok := p.Args["after"].(string); ok {
+							args.After = &v1
+						}
+						if v1, ok := p.Args["last"].(int); ok {
+							args.Last = &v1
+						}
+						if v1, ok := p.Args["before"].(string); ok {
+							args.Before = &v1
+						}
+						return r.Query().MyReviewsConnection(p, args)
+					},
+				},
+				"job": &graphql.Field{
+					Type: jobType,
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QueryJobArgs
+						args.ID, _ = p.Args["id"].(int)
+						return r.Query().Job(p, args)
+					},
+				},
+				"translationMemory": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(translationMemoryEntryType))),
+					Args: graphql.FieldConfigArgument{
+						"search":        &graphql.ArgumentConfig{Type: graphql.String},
+						"sourceLang":    &graphql.ArgumentConfig{Type: graphql.String},
+						"targetLang":    &graphql.ArgumentConfig{Type: graphql.String},
+						"overridesOnly": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+						"limit":         &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 50},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QueryTranslationMemoryArgs
+						if v1, ok := p.Args["search"].(string); ok {
+							args.Search = &v1
+						}
+						if v1, ok := p.Args["sourceLang"].(string); ok {
+							args.SourceLang = &v1
+						}
+						if v1, ok := p.Args["targetLang"].(string); ok {
+							args.TargetLang = &v1
+						}
+						args.OverridesOnly, _ = p.Args["overridesOnly"].(bool)
+						args.Limit, _ = p.Args["limit"].(int)
+						return r.Query().TranslationMemory(p, args)
+					},
+				},
+				"loginAttempts": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(loginAttemptType))),
+					Args: graphql.FieldConfigArgument{
+						"email":     &graphql.ArgumentConfig{Type: graphql.String},
+						"ipAddress": &graphql.ArgumentConfig{Type: graphql.String},
+						"success":   &graphql.ArgumentConfig{Type: graphql.Boolean},
+						"limit":     &graphql.ArgumentConfig{Type: graphql.Int},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QueryLoginAttemptsArgs
+						if v1, ok := p.Args["email"].(string); ok {
+							args.Email = &v1
+						}
+						if v1, ok := p.Args["ipAddress"].(string); ok {
+							args.IPAddress = &v1
+						}
+						if v1, ok := p.Args["success"].(bool); ok {
+							args.Success = &v1
+						}
+						if v1, ok := p.Args["limit"].(int); ok {
+							args.Limit = &v1
+						}
+						return r.Query().LoginAttempts(p, args)
+					},
+				},
+				"searchStats": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(searchStatType))),
+					Args: graphql.FieldConfigArgument{
+						"days":        &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 30},
+						"zeroResults": &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: false},
+						"limit":       &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 20},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args QuerySearchStatsArgs
+						args.Days, _ = p.Args["days"].(int)
+						args.ZeroResults, _ = p.Args["zeroResults"].(bool)
+						args.Limit, _ = p.Args["limit"].(int)
+						return r.Query().SearchStats(p, args)
+					},
+				},
+			}
+		}),
+	})
+
+	mutationType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"register": &graphql.Field{
+					Type: graphql.NewNonNull(authResponseType),
+					Args: graphql.FieldConfigArgument{
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(registerInputType)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationRegisterArgs
+						if v1, ok := p.Args["input"].(map[string]interface{}); ok {
+							args.Input = decodeRegisterInput(v1)
+						}
+						return r.Mutation().Register(p, args)
+					},
+				},
+				"login": &graphql.Field{
+					Type: graphql.NewNonNull(authResponseType),
+					Args: graphql.FieldConfigArgument{
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(loginInputType)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationLoginArgs
+						if v1, ok := p.Args["input"].(map[string]interface{}); ok {
+							args.Input = decodeLoginInput(v1)
+						}
+						return r.Mutation().Login(p, args)
+					},
+				},
+				"refreshToken": &graphql.Field{
+					Type: graphql.NewNonNull(authResponseType),
+					Args: graphql.FieldConfigArgument{
+						"refreshToken": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationRefreshTokenArgs
+						args.RefreshToken, _ = p.Args["refreshToken"].(string)
+						return r.Mutation().RefreshToken(p, args)
+					},
+				},
+				"logout": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return r.Mutation().Logout(p)
+					},
+				},
+				"logoutAllDevices": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return r.Mutation().LogoutAllDevices(p)
+					},
+				},
+				"unlinkIdentity": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"provider": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationUnlinkIdentityArgs
+						args.Provider, _ = p.Args["provider"].(string)
+						return r.Mutation().UnlinkIdentity(p, args)
+					},
+				},
+				"requestPasswordReset": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"email": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationRequestPasswordResetArgs
+						args.Email, _ = p.Args["email"].(string)
+						return r.Mutation().RequestPasswordReset(p, args)
+					},
+				},
+				"resetPassword": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"token":       &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+						"newPassword": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationResetPasswordArgs
+						args.Token, _ = p.Args["token"].(string)
+						args.NewPassword, _ = p.Args["newPassword"].(string)
+						return r.Mutation().ResetPassword(p, args)
+					},
+				},
+				"changePassword": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"currentPassword": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+						"newPassword":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationChangePasswordArgs
+						args.CurrentPassword, _ = p.Args["currentPassword"].(string)
+						args.NewPassword, _ = p.Args["newPassword"].(string)
+						return r.Mutation().ChangePassword(p, args)
+					},
+				},
+				"verifyEmail": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"token": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationVerifyEmailArgs
+						args.Token, _ = p.Args["token"].(string)
+						return r.Mutation().VerifyEmail(p, args)
+					},
+				},
+				"resendVerificationEmail": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return r.Mutation().ResendVerificationEmail(p)
+					},
+				},
+				"updateProfile": &graphql.Field{
+					Type: graphql.NewNonNull(userType),
+					Args: graphql.FieldConfigArgument{
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(updateUserInputType)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationUpdateProfileArgs
+						if v1, ok := p.Args["input"].(map[string]interface{}); ok {
+							args.Input = decodeUpdateUserInput(v1)
+						}
+						return r.Mutation().UpdateProfile(p, args)
+					},
+				},
+				"enableTwoFactor": &graphql.Field{
+					Type: graphql.NewNonNull(twoFactorSetupType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return r.Mutation().EnableTwoFactor(p)
+					},
+				},
+				"confirmTwoFactor": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+					Args: graphql.FieldConfigArgument{
+						"code": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationConfirmTwoFactorArgs
+						args.Code, _ = p.Args["code"].(string)
+						return r.Mutation().ConfirmTwoFactor(p, args)
+					},
+				},
+				"verifyTwoFactor": &graphql.Field{
+					Type: graphql.NewNonNull(authResponseType),
+					Args: graphql.FieldConfigArgument{
+						"challengeToken": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+						"code":           &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationVerifyTwoFactorArgs
+						args.ChallengeToken, _ = p.Args["challengeToken"].(string)
+						args.Code, _ = p.Args["code"].(string)
+						return r.Mutation().VerifyTwoFactor(p, args)
+					},
+				},
+				"regenerateRecoveryCodes": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+					Args: graphql.FieldConfigArgument{
+						"code": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationRegenerateRecoveryCodesArgs
+						args.Code, _ = p.Args["code"].(string)
+						return r.Mutation().RegenerateRecoveryCodes(p, args)
+					},
+				},
+				"disableTwoFactor": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"code": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationDisableTwoFactorArgs
+						args.Code, _ = p.Args["code"].(string)
+						return r.Mutation().DisableTwoFactor(p, args)
+					},
+				},
+				"createApiKey": &graphql.Field{
+					Type: graphql.NewNonNull(createApiKeyPayloadType),
+					Args: graphql.FieldConfigArgument{
+						"name":          &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+						"scopes":        &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+						"expiresInDays": &graphql.ArgumentConfig{Type: graphql.Int},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationCreateApiKeyArgs
+						args.Name, _ = p.Args["name"].(string)
+						if v1, ok := p.Args["scopes"].([]interface{}); ok {
+							args.Scopes = make([]string, 0, len(v1))
+							for _, item1 := range v1 {
+								var elem1 string
+								elem1, _ = item1.(string)
+								args.Scopes = append(args.Scopes, elem1)
+							}
+						}
+						if v1, ok := p.Args["expiresInDays"].(int); ok {
+							args.ExpiresInDays = &v1
+						}
+						return r.Mutation().CreateApiKey(p, args)
+					},
+				},
+				"revokeApiKey": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationRevokeApiKeyArgs
+						args.ID, _ = p.Args["id"].(int)
+						return r.Mutation().RevokeApiKey(p, args)
+					},
+				},
+				"addToCart": &graphql.Field{
+					Type: graphql.NewNonNull(cartItemType),
+					Args: graphql.FieldConfigArgument{
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(addToCartInputType)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationAddToCartArgs
+						if v1, ok := p.Args["input"].(map[string]interface{}); ok {
+							args.Input = decodeAddToCartInput(v1)
+						}
+						return r.Mutation().AddToCart(p, args)
+					},
+				},
+				"updateCartItem": &graphql.Field{
+					Type: graphql.NewNonNull(cartItemType),
+					Args: graphql.FieldConfigArgument{
+						"id":       &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+						"quantity": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationUpdateCartItemArgs
+						args.ID, _ = p.Args["id"].(int)
+						args.Quantity, _ = p.Args["quantity"].(int)
+						return r.Mutation().UpdateCartItem(p, args)
+					},
+				},
+				"removeFromCart": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationRemoveFromCartArgs
+						args.ID, _ = p.Args["id"].(int)
+						return r.Mutation().RemoveFromCart(p, args)
+					},
+				},
+				"clearCart": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return r.Mutation().ClearCart(p)
+					},
+				},
+				"addToWishlist": &graphql.Field{
+					Type: graphql.NewNonNull(wishlistItemType),
+					Args: graphql.FieldConfigArgument{
+						"productId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationAddToWishlistArgs
+						args.ProductID, _ = p.Args["productId"].(int)
+						return r.Mutation().AddToWishlist(p, args)
+					},
+				},
+				"removeFromWishlist": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"productId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationRemoveFromWishlistArgs
+						args.ProductID, _ = p.Args["productId"].(int)
+						return r.Mutation().RemoveFromWishlist(p, args)
+					},
+				},
+				"createOrder": &graphql.Field{
+					Type: graphql.NewNonNull(orderType),
+					Args: graphql.FieldConfigArgument{
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createOrderInputType)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationCreateOrderArgs
+						if v1, ok := p.Args["input"].(map[string]interface{}); ok {
+							args.Input = decodeCreateOrderInput(v1)
+						}
+						return r.Mutation().CreateOrder(p, args)
+					},
+				},
+				"updateOrderStatus": &graphql.Field{
+					Type: graphql.NewNonNull(orderType),
+					Args: graphql.FieldConfigArgument{
+						"id":     &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+						"status": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationUpdateOrderStatusArgs
+						args.ID, _ = p.Args["id"].(int)
+						args.Status, _ = p.Args["status"].(string)
+						return r.Mutation().UpdateOrderStatus(p, args)
+					},
+				},
+				"createReview": &graphql.Field{
+					Type: graphql.NewNonNull(reviewType),
+					Args: graphql.FieldConfigArgument{
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createReviewInputType)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationCreateReviewArgs
+						if v1, ok := p.Args["input"].(map[string]interface{}); ok {
+							args.Input = decodeCreateReviewInput(v1)
+						}
+						return r.Mutation().CreateReview(p, args)
+					},
+				},
+				"updateReview": &graphql.Field{
+					Type: graphql.NewNonNull(reviewType),
+					Args: graphql.FieldConfigArgument{
+						"id":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+						"rating":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+						"title":   &graphql.ArgumentConfig{Type: graphql.String},
+						"comment": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationUpdateReviewArgs
+						args.ID, _ = p.Args["id"].(int)
+						args.Rating, _ = p.Args["rating"].(int)
+						if v1, ok := p.Args["title"].(string); ok {
+							args.Title = &v1
+						}
+						args.Comment, _ = p.Args["comment"].(string)
+						return r.Mutation().UpdateReview(p, args)
+					},
+				},
+				"deleteReview": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationDeleteReviewArgs
+						args.ID, _ = p.Args["id"].(int)
+						return r.Mutation().DeleteReview(p, args)
+					},
+				},
+				"likeReview": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationLikeReviewArgs
+						args.ID, _ = p.Args["id"].(int)
+						return r.Mutation().LikeReview(p, args)
+					},
+				},
+				"unlikeReview": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationUnlikeReviewArgs
+						args.ID, _ = p.Args["id"].(int)
+						return r.Mutation().UnlikeReview(p, args)
+					},
+				},
+				"likeProduct": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"productId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationLikeProductArgs
+						args.ProductID, _ = p.Args["productId"].(int)
+						return r.Mutation().LikeProduct(p, args)
+					},
+				},
+				"unlikeProduct": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"productId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationUnlikeProductArgs
+						args.ProductID, _ = p.Args["productId"].(int)
+						return r.Mutation().UnlikeProduct(p, args)
+					},
+				},
+				"createProduct": &graphql.Field{
+					Type: graphql.NewNonNull(productType),
+					Args: graphql.FieldConfigArgument{
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createProductInputType)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationCreateProductArgs
+						args.Input, _ = p.Args["input"].(map[string]interface{})
+						return r.Mutation().CreateProduct(p, args)
+					},
+				},
+				"updateProduct": &graphql.Field{
+					Type: graphql.NewNonNull(productType),
+					Args: graphql.FieldConfigArgument{
+						"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(updateProductInputType)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationUpdateProductArgs
+						args.ID, _ = p.Args["id"].(int)
+						args.Input, _ = p.Args["input"].(map[string]interface{})
+						return r.Mutation().UpdateProduct(p, args)
+					},
+				},
+				"archiveProduct": &graphql.Field{
+					Type: graphql.NewNonNull(productType),
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationArchiveProductArgs
+						args.ID, _ = p.Args["id"].(int)
+						return r.Mutation().ArchiveProduct(p, args)
+					},
+				},
+				"restoreProduct": &graphql.Field{
+					Type: graphql.NewNonNull(productType),
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationRestoreProductArgs
+						args.ID, _ = p.Args["id"].(int)
+						return r.Mutation().RestoreProduct(p, args)
+					},
+				},
+				"deleteProduct": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationDeleteProductArgs
+						args.ID, _ = p.Args["id"].(int)
+						return r.Mutation().DeleteProduct(p, args)
+					},
+				},
+				"createProductVariant": &graphql.Field{
+					Type: graphql.NewNonNull(productVariantType),
+					Args: graphql.FieldConfigArgument{
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createProductVariantInputType)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationCreateProductVariantArgs
+						if v1, ok := p.Args["input"].(map[string]interface{}); ok {
+							args.Input = decodeCreateProductVariantInput(v1)
+						}
+						return r.Mutation().CreateProductVariant(p, args)
+					},
+				},
+				"updateProductVariant": &graphql.Field{
+					Type: graphql.NewNonNull(productVariantType),
+					Args: graphql.FieldConfigArgument{
+						"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(updateProductVariantInputType)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationUpdateProductVariantArgs
+						args.ID, _ = p.Args["id"].(int)
+						if v1, ok := p.Args["input"].(map[string]interface{}); ok {
+							args.Input = decodeUpdateProductVariantInput(v1)
+						}
+						return r.Mutation().UpdateProductVariant(p, args)
+					},
+				},
+				"deleteProductVariant": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationDeleteProductVariantArgs
+						args.ID, _ = p.Args["id"].(int)
+						return r.Mutation().DeleteProductVariant(p, args)
+					},
+				},
+				"createCategory": &graphql.Field{
+					Type: graphql.NewNonNull(categoryType),
+					Args: graphql.FieldConfigArgument{
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(createCategoryInputType)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationCreateCategoryArgs
+						if v1, ok := p.Args["input"].(map[string]interface{}); ok {
+							args.Input = decodeCreateCategoryInput(v1)
+						}
+						return r.Mutation().CreateCategory(p, args)
+					},
+				},
+				"moveCategory": &graphql.Field{
+					Type: graphql.NewNonNull(categoryType),
+					Args: graphql.FieldConfigArgument{
+						"id":       &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+						"parentId": &graphql.ArgumentConfig{Type: graphql.Int},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationMoveCategoryArgs
+						args.ID, _ = p.Args["id"].(int)
+						if v1, ok := p.Args["parentId"].(int); ok {
+							args.ParentID = &v1
+						}
+						return r.Mutation().MoveCategory(p, args)
+					},
+				},
+				"deleteCategory": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationDeleteCategoryArgs
+						args.ID, _ = p.Args["id"].(int)
+						return r.Mutation().DeleteCategory(p, args)
+					},
+				},
+				"setProductTranslation": &graphql.Field{
+					Type: graphql.NewNonNull(productType),
+					Args: graphql.FieldConfigArgument{
+						"productId":        &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+						"locale":           &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+						"name":             &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+						"description":      &graphql.ArgumentConfig{Type: graphql.String},
+						"shortDescription": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationSetProductTranslationArgs
+						args.ProductID, _ = p.Args["productId"].(int)
+						args.Locale, _ = p.Args["locale"].(string)
+						args.Name, _ = p.Args["name"].(string)
+						if v1, ok := p.Args["description"].(string); ok {
+							args.Description = &v1
+						}
+						if v1, ok := p.Args["shortDescription"].(string); ok {
+							args.ShortDescription = &v1
+						}
+						return r.Mutation().SetProductTranslation(p, args)
+					},
+				},
+				"setCategoryTranslation": &graphql.Field{
+					Type: graphql.NewNonNull(categoryType),
+					Args: graphql.FieldConfigArgument{
+						"categoryId":  &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+						"locale":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+						"name":        &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+						"description": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationSetCategoryTranslationArgs
+						args.CategoryID, _ = p.Args["categoryId"].(int)
+						args.Locale, _ = p.Args["locale"].(string)
+						args.Name, _ = p.Args["name"].(string)
+						if v1, ok := p.Args["description"].(string); ok {
+							args.Description = &v1
+						}
+						return r.Mutation().SetCategoryTranslation(p, args)
+					},
+				},
+				"fillMissingTranslations": &graphql.Field{
+					Type: graphql.NewNonNull(jobType),
+					Args: graphql.FieldConfigArgument{
+						"locale": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationFillMissingTranslationsArgs
+						args.Locale, _ = p.Args["locale"].(string)
+						return r.Mutation().FillMissingTranslations(p, args)
+					},
+				},
+				"setTranslationOverride": &graphql.Field{
+					Type: graphql.NewNonNull(translationMemoryEntryType),
+					Args: graphql.FieldConfigArgument{
+						"text":        &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+						"from":        &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+						"to":          &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+						"translation": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationSetTranslationOverrideArgs
+						args.Text, _ = p.Args["text"].(string)
+						args.From, _ = p.Args["from"].(string)
+						args.To, _ = p.Args["to"].(string)
+						args.Translation, _ = p.Args["translation"].(string)
+						return r.Mutation().SetTranslationOverride(p, args)
+					},
+				},
+				"deleteTranslationMemoryEntry": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.Boolean),
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationDeleteTranslationMemoryEntryArgs
+						args.ID, _ = p.Args["id"].(int)
+						return r.Mutation().DeleteTranslationMemoryEntry(p, args)
+					},
+				},
+				"updateUserRole": &graphql.Field{
+					Type: graphql.NewNonNull(userType),
+					Args: graphql.FieldConfigArgument{
+						"userId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+						"role":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationUpdateUserRoleArgs
+						args.UserID, _ = p.Args["userId"].(int)
+						args.Role, _ = p.Args["role"].(string)
+						return r.Mutation().UpdateUserRole(p, args)
+					},
+				},
+				"updateUserStatus": &graphql.Field{
+					Type: graphql.NewNonNull(userType),
+					Args: graphql.FieldConfigArgument{
+						"userId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+						"status": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationUpdateUserStatusArgs
+						args.UserID, _ = p.Args["userId"].(int)
+						args.Status, _ = p.Args["status"].(string)
+						return r.Mutation().UpdateUserStatus(p, args)
+					},
+				},
+				"setTwoFactorRequired": &graphql.Field{
+					Type: graphql.NewNonNull(userType),
+					Args: graphql.FieldConfigArgument{
+						"userId":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+						"required": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Boolean)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationSetTwoFactorRequiredArgs
+						args.UserID, _ = p.Args["userId"].(int)
+						args.Required, _ = p.Args["required"].(bool)
+						return r.Mutation().SetTwoFactorRequired(p, args)
+					},
+				},
+				"enqueueProductDescription": &graphql.Field{
+					Type: graphql.NewNonNull(jobType),
+					Args: graphql.FieldConfigArgument{
+						"productId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+						"language":  &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: "ar"},
+						"tone":      &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: "professional"},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationEnqueueProductDescriptionArgs
+						args.ProductID, _ = p.Args["productId"].(int)
+						args.Language, _ = p.Args["language"].(string)
+						args.Tone, _ = p.Args["tone"].(string)
+						return r.Mutation().EnqueueProductDescription(p, args)
+					},
+				},
+				"enqueueTranslation": &graphql.Field{
+					Type: graphql.NewNonNull(jobType),
+					Args: graphql.FieldConfigArgument{
+						"text": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+						"from": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+						"to":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationEnqueueTranslationArgs
+						args.Text, _ = p.Args["text"].(string)
+						args.From, _ = p.Args["from"].(string)
+						args.To, _ = p.Args["to"].(string)
+						return r.Mutation().EnqueueTranslation(p, args)
+					},
+				},
+				"retryJob": &graphql.Field{
+					Type: graphql.NewNonNull(jobType),
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationRetryJobArgs
+						args.ID, _ = p.Args["id"].(int)
+						return r.Mutation().RetryJob(p, args)
+					},
+				},
+				"addProduct": &graphql.Field{
+					Type: graphql.NewNonNull(productType),
+					Args: graphql.FieldConfigArgument{
+						"name":       &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+						"price":      &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Float)},
+						"categoryId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationAddProductArgs
+						args.Name, _ = p.Args["name"].(string)
+						args.Price, _ = p.Args["price"].(float64)
+						args.CategoryID, _ = p.Args["categoryId"].(int)
+						return r.Mutation().AddProduct(p, args)
+					},
+				},
+				"regenerateProductDescription": &graphql.Field{
+					Type: graphql.NewNonNull(productType),
+					Args: graphql.FieldConfigArgument{
+						"productId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)},
+						"language":  &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: "ar"},
+						"tone":      &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: "professional"},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationRegenerateProductDescriptionArgs
+						args.ProductID, _ = p.Args["productId"].(int)
+						args.Language, _ = p.Args["language"].(string)
+						args.Tone, _ = p.Args["tone"].(string)
+						return r.Mutation().RegenerateProductDescription(p, args)
+					},
+				},
+				"translateText": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.String),
+					Args: graphql.FieldConfigArgument{
+						"text": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+						"from": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+						"to":   &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						var args MutationTranslateTextArgs
+						args.Text, _ = p.Args["text"].(string)
+						args.From, _ = p.Args["from"].(string)
+						args.To, _ = p.Args["to"].(string)
+						return r.Mutation().TranslateText(p, args)
+					},
+				},
+			}
+		}),
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    queryType,
+		Mutation: mutationType,
+		// Include types that no field returns yet
+		Types: []graphql.Type{userType, categoryType, productType, productVariantType, selectedOptionType, productOptionType, productOptionValueType, cartItemType, wishlistItemType, orderItemType, orderType, reviewType, authResponseType, twoFactorSetupType, userIdentityType, apiKeyType, createApiKeyPayloadType, jobType, translationMemoryEntryType, loginAttemptType, cartSummaryType, searchResultType, productFacetsType, searchSuggestionsType, searchStatType, categoryFacetType, priceRangeFacetType, ratingFacetType, pageInfoType, productEdgeType, productConnectionType, orderEdgeType, orderConnectionType, reviewEdgeType, reviewConnectionType, wishlistItemEdgeType, wishlistConnectionType, registerInputType, loginInputType, updateUserInputType, addToCartInputType, createOrderInputType, createCategoryInputType, createReviewInputType, createProductInputType, updateProductInputType, selectedOptionInputType, createProductVariantInputType, updateProductVariantInputType},
+	})
+}
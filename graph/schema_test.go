@@ -0,0 +1,184 @@
+package graph
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// TestSchemaMatchesSDL fails when the served schema and schema.graphqls differ,
+// which means schema_gen.go is stale and `go generate ./graph` needs to be run
+func TestSchemaMatchesSDL(t *testing.T) {
+	source, err := os.ReadFile("schema.graphqls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	document, err := parser.Parse(parser.ParseParams{Source: string(source)})
+	if err != nil {
+		t.Fatalf("parse schema.graphqls: %v", err)
+	}
+
+	schema, err := Schema()
+	if err != nil {
+		t.Fatalf("build schema: %v", err)
+	}
+
+	declared := map[string]bool{}
+	for _, definition := range document.Definitions {
+		switch def := definition.(type) {
+		case *ast.ObjectDefinition:
+			declared[def.Name.Value] = true
+			compareObject(t, schema, def)
+		case *ast.InputObjectDefinition:
+			declared[def.Name.Value] = true
+			compareInputObject(t, schema, def)
+		default:
+			t.Errorf("schema.graphqls: unsupported definition %s", definition.GetKind())
+		}
+	}
+
+	for name := range schema.TypeMap() {
+		if strings.HasPrefix(name, "__") || isBuiltinScalar(name) {
+			continue
+		}
+		if !declared[name] {
+			t.Errorf("served type %s is not declared in schema.graphqls", name)
+		}
+	}
+}
+
+func compareObject(t *testing.T, schema *graphql.Schema, def *ast.ObjectDefinition) {
+	name := def.Name.Value
+	object, ok := schema.Type(name).(*graphql.Object)
+	if !ok {
+		t.Errorf("type %s: not served as an object type", name)
+		return
+	}
+
+	served := object.Fields()
+	for _, field := range def.Fields {
+		path := name + "." + field.Name.Value
+		definition, ok := served[field.Name.Value]
+		if !ok {
+			t.Errorf("%s: declared but not served", path)
+			continue
+		}
+		if got, want := definition.Type.String(), sdlType(field.Type); got != want {
+			t.Errorf("%s: served as %s, declared as %s", path, got, want)
+		}
+		compareArgs(t, path, definition.Args, field.Arguments)
+	}
+	for fieldName := range served {
+		if !declaresField(def.Fields, fieldName) {
+			t.Errorf("%s.%s: served but not declared", name, fieldName)
+		}
+	}
+}
+
+func compareArgs(t *testing.T, path string, served []*graphql.Argument, declared []*ast.InputValueDefinition) {
+	byName := make(map[string]*graphql.Argument, len(served))
+	for _, arg := range served {
+		byName[arg.Name()] = arg
+	}
+
+	for _, arg := range declared {
+		argPath := path + "(" + arg.Name.Value + ")"
+		servedArg, ok := byName[arg.Name.Value]
+		if !ok {
+			t.Errorf("%s: declared but not served", argPath)
+			continue
+		}
+		delete(byName, arg.Name.Value)
+		if got, want := servedArg.Type.String(), sdlType(arg.Type); got != want {
+			t.Errorf("%s: served as %s, declared as %s", argPath, got, want)
+		}
+		compareDefault(t, argPath, servedArg.DefaultValue, arg.DefaultValue)
+	}
+	for name := range byName {
+		t.Errorf("%s(%s): served but not declared", path, name)
+	}
+}
+
+func compareInputObject(t *testing.T, schema *graphql.Schema, def *ast.InputObjectDefinition) {
+	name := def.Name.Value
+	input, ok := schema.Type(name).(*graphql.InputObject)
+	if !ok {
+		t.Errorf("input %s: not served as an input type", name)
+		return
+	}
+
+	served := input.Fields()
+	for _, field := range def.Fields {
+		path := name + "." + field.Name.Value
+		servedField, ok := served[field.Name.Value]
+		if !ok {
+			t.Errorf("%s: declared but not served", path)
+			continue
+		}
+		if got, want := servedField.Type.String(), sdlType(field.Type); got != want {
+			t.Errorf("%s: served as %s, declared as %s", path, got, want)
+		}
+		compareDefault(t, path, servedField.DefaultValue, field.DefaultValue)
+	}
+	for fieldName := range served {
+		if !declaresInputField(def.Fields, fieldName) {
+			t.Errorf("%s.%s: served but not declared", name, fieldName)
+		}
+	}
+}
+
+func compareDefault(t *testing.T, path string, served interface{}, declared ast.Value) {
+	switch {
+	case declared == nil && served == nil:
+	case declared == nil:
+		t.Errorf("%s: served with default %v, declared without one", path, served)
+	case served == nil:
+		t.Errorf("%s: served without a default, declared with %v", path, declared.GetValue())
+	case fmt.Sprint(served) != fmt.Sprint(declared.GetValue()):
+		t.Errorf("%s: served with default %v, declared with %v", path, served, declared.GetValue())
+	}
+}
+
+// sdlType renders an SDL type reference the way graphql-go prints served types
+func sdlType(typ ast.Type) string {
+	switch typ := typ.(type) {
+	case *ast.NonNull:
+		return sdlType(typ.Type) + "!"
+	case *ast.List:
+		return "[" + sdlType(typ.Type) + "]"
+	case *ast.Named:
+		return typ.Name.Value
+	}
+	return fmt.Sprintf("%T", typ)
+}
+
+func declaresField(fields []*ast.FieldDefinition, name string) bool {
+	for _, field := range fields {
+		if field.Name.Value == name {
+			return true
+		}
+	}
+	return false
+}
+
+func declaresInputField(fields []*ast.InputValueDefinition, name string) bool {
+	for _, field := range fields {
+		if field.Name.Value == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isBuiltinScalar(name string) bool {
+	switch name {
+	case "String", "Int", "Float", "Boolean", "ID":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/repository"
+	"context"
+	"log"
+	"strings"
+	"time"
+)
+
+// Limits of the search suggestions
+const (
+	maxSuggestions = 20
+	// minSuggestedSearchers is how many different customers must have searched
+	// a query for it to be suggested, so one customer's searches, which may hold
+	// private text such as an order number, are not shown to others
+	minSuggestedSearchers = 3
+	// suggestedSearchesWindow is how far back popular searches are counted
+	suggestedSearchesWindow = 30 * 24 * time.Hour
+)
+
+// SuggestSearches returns popular past searches, products and categories
+// completing prefix, up to limit of each, in locale
+func SuggestSearches(ctx context.Context, prefix string, limit int, locale string) (*SearchSuggestions, error) {
+	if limit < 1 || limit > maxSuggestions {
+		return nil, &ValidationError{Fields: map[string]string{"limit": "must be between 1 and 20"}}
+	}
+	suggestions := &SearchSuggestions{Queries: []string{}, Products: []*Product{}, Categories: []*Category{}}
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return suggestions, nil
+	}
+
+	var err error
+	since := time.Now().Add(-suggestedSearchesWindow)
+	suggestions.Queries, err = Repos.Searches.Suggest(ctx, prefix, since, minSuggestedSearchers, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions.Products, err = Repos.Products.Suggest(ctx, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeProducts(ctx, suggestions.Products, locale); err != nil {
+		return nil, err
+	}
+
+	suggestions.Categories, err = Repos.Categories.Suggest(ctx, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeCategories(ctx, suggestions.Categories, locale); err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
+
+// recordSearch adds a search to the search log, logging rather than failing on errors
+func recordSearch(ctx context.Context, query string, results int, locale string) {
+	search := repository.NewSearch{Query: query, Results: results, Locale: locale}
+	if user, ok := ctx.Value("user").(*User); ok {
+		search.UserID = &user.ID
+	} else if _, ipAddress := requestClient(ctx); ipAddress != "" {
+		search.ClientKey = auth.HashToken(ipAddress)
+	}
+	if err := Repos.Searches.Record(ctx, search); err != nil {
+		log.Printf("failed to record search: %v", err)
+	}
+}
+
+// SearchStats returns the most frequent searches of the last days. With
+// zeroResults it returns only the searches that found no products.
+func SearchStats(ctx context.Context, days int, zeroResults bool, limit int) ([]*SearchStat, error) {
+	problems := NewValidationError()
+	if days < 1 || days > 365 {
+		problems.Add("days", "must be between 1 and 365")
+	}
+	if limit < 1 || limit > 100 {
+		problems.Add("limit", "must be between 1 and 100")
+	}
+	if err := problems.OrNil(); err != nil {
+		return nil, err
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	return Repos.Searches.Popular(ctx, since, zeroResults, limit)
+}
@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"ai-catalog/model"
+	"context"
+	"testing"
+)
+
+func TestSearchesAreSuggestedOnceSeveralCustomersMadeThem(t *testing.T) {
+	store := useMemory(t)
+	store.AddProduct(model.Product{Name: "Phone case", Price: 10, IsActive: true, StockQuantity: 5, SKU: "CASE"})
+	search := `{ searchProducts(query: "phone case") { total } }`
+	suggest := `{ searchSuggestions(prefix: "phone") { queries } }`
+	var suggestions struct {
+		SearchSuggestions struct{ Queries []string }
+	}
+
+	// One customer searching again and again is still one customer, signed in
+	// or not
+	customer := createUser(t, "customer@example.com", auth.RoleCustomer)
+	anonymous := clientContext("203.0.113.9:4000", "")
+	for i := 0; i < minSuggestedSearchers+2; i++ {
+		execute(t, asUser(customer), search, nil, nil)
+		execute(t, anonymous, search, nil, nil)
+	}
+	execute(t, context.Background(), suggest, nil, &suggestions)
+	if len(suggestions.SearchSuggestions.Queries) != 0 {
+		t.Fatalf("suggestions after two customers searched = %v, want none", suggestions.SearchSuggestions.Queries)
+	}
+
+	other := createUser(t, "other@example.com", auth.RoleCustomer)
+	execute(t, asUser(other), search, nil, nil)
+	execute(t, context.Background(), suggest, nil, &suggestions)
+	if queries := suggestions.SearchSuggestions.Queries; len(queries) != 1 || queries[0] != "phone case" {
+		t.Errorf("suggestions after three customers searched = %v, want [phone case]", queries)
+	}
+}
+
+func TestFacetsLeaveOutTheirOwnFilter(t *testing.T) {
+	store := useMemory(t)
+	phones := store.AddCategory(model.Category{Name: "Phones"})
+	laptops := store.AddCategory(model.Category{Name: "Laptops"})
+	store.AddProduct(model.Product{Name: "Pro phone", Price: 100, CategoryID: phones.ID, IsActive: true, StockQuantity: 5, SKU: "PHONE"})
+	store.AddProduct(model.Product{Name: "Pro max phone", Price: 600, CategoryID: phones.ID, IsActive: true, SKU: "PHONE-MAX"})
+	store.AddProduct(model.Product{Name: "Pro laptop", Price: 600, CategoryID: laptops.ID, IsActive: true, StockQuantity: 5, SKU: "LAPTOP"})
+
+	var got struct {
+		SearchProducts struct {
+			Total  int
+			Facets struct {
+				Categories []struct {
+					Category struct{ Name string }
+					Count    int
+				}
+				PriceRanges []struct {
+					Min   float64
+					Count int
+				}
+				InStock, OutOfStock int
+			}
+		}
+	}
+	execute(t, context.Background(), `query($category: Int!) {
+		searchProducts(query: "pro", categoryId: $category, minPrice: 500, inStock: true) {
+			total
+			facets { categories { category { name } count } priceRanges { min count } inStock outOfStock }
+		}
+	}`, map[string]interface{}{"category": phones.ID}, &got)
+
+	if got.SearchProducts.Total != 0 {
+		t.Errorf("total = %d, want no phone from 500 in stock", got.SearchProducts.Total)
+	}
+	facets := got.SearchProducts.Facets
+	// Categories with a product from 500 in stock
+	if len(facets.Categories) != 1 || facets.Categories[0].Category.Name != "Laptops" || facets.Categories[0].Count != 1 {
+		t.Errorf("category facets = %+v, want the laptop", facets.Categories)
+	}
+	// Prices of the phones in stock
+	if len(facets.PriceRanges) != 1 || facets.PriceRanges[0].Min != 100 || facets.PriceRanges[0].Count != 1 {
+		t.Errorf("price facets = %+v, want the phone from 100", facets.PriceRanges)
+	}
+	// Stock of the phones from 500
+	if facets.InStock != 0 || facets.OutOfStock != 1 {
+		t.Errorf("stock facets = %d in stock, %d out of stock, want the max phone out of stock", facets.InStock, facets.OutOfStock)
+	}
+}
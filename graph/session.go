@@ -0,0 +1,46 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"context"
+	"time"
+)
+
+// requestClient returns the user agent and IP address AuthMiddleware stored in the context
+func requestClient(ctx context.Context) (userAgent, ipAddress string) {
+	userAgent, _ = ctx.Value("userAgent").(string)
+	ipAddress, _ = ctx.Value("clientIP").(string)
+	return userAgent, ipAddress
+}
+
+// newAuthResponse starts a session for the user and issues its access and refresh tokens
+func newAuthResponse(ctx context.Context, user *User) (*AuthResponse, error) {
+	userAgent, ipAddress := requestClient(ctx)
+	session, refreshToken, err := auth.CreateSession(ctx, user.ID, userAgent, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+	return sessionAuthResponse(user, session.ID, refreshToken)
+}
+
+// sessionAuthResponse issues an access token for an existing session
+func sessionAuthResponse(user *User, sessionID, refreshToken string) (*AuthResponse, error) {
+	// Convert graph.User to auth.User
+	authUser := &auth.User{
+		ID:    user.ID,
+		Email: user.Email,
+		Role:  user.Role,
+	}
+	expiresAt := time.Now().Add(auth.AccessTokenTTL())
+	token, err := auth.GenerateToken(authUser, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthResponse{
+		User:         user,
+		Token:        token,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
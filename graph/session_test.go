@@ -0,0 +1,129 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"context"
+	"strings"
+	"testing"
+)
+
+// authenticate returns the request context AuthMiddleware builds for a bearer
+// access token, or the reason the token is refused
+func authenticate(token string) (context.Context, error) {
+	ctx := clientContext("192.0.2.1:4000", "")
+	claims, err := auth.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	user, err := LoadAuthenticatedUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, "user", user)
+	return context.WithValue(ctx, "sessionID", claims.SessionID), nil
+}
+
+// signIn starts a new session for user and returns its access and refresh tokens
+func signIn(t *testing.T, user *User) (token, refreshToken string) {
+	t.Helper()
+	response, err := newAuthResponse(clientContext("192.0.2.1:4000", ""), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return response.Token, response.RefreshToken
+}
+
+const refreshSession = `mutation($token: String!) { refreshToken(refreshToken: $token) { token refreshToken } }`
+
+// refresh exchanges a refresh token for new tokens, returning the GraphQL errors
+func refresh(t *testing.T, refreshToken string) (token, newRefreshToken string, errs []error) {
+	t.Helper()
+	var got struct {
+		RefreshToken struct{ Token, RefreshToken string }
+	}
+	errs = run(t, clientContext("192.0.2.1:4000", ""), refreshSession, map[string]interface{}{"token": refreshToken}, &got)
+	return got.RefreshToken.Token, got.RefreshToken.RefreshToken, errs
+}
+
+func TestRefreshTokensAreRotated(t *testing.T) {
+	useMemory(t)
+	useSigningKey(t)
+	user := createUser(t, "user@example.com", auth.RoleCustomer)
+	_, first := signIn(t, user)
+
+	token, second, errs := refresh(t, first)
+	if len(errs) > 0 {
+		t.Fatalf("refreshToken: %v", errs)
+	}
+	if second == "" || second == first {
+		t.Fatalf("refreshToken returned %q, want a new refresh token", second)
+	}
+	if _, err := authenticate(token); err != nil {
+		t.Errorf("the refreshed access token was refused: %v", err)
+	}
+
+	if _, third, errs := refresh(t, second); len(errs) > 0 || third == "" {
+		t.Errorf("refreshing with the rotated token: %q, %v", third, errs)
+	}
+}
+
+func TestReplayedRefreshTokensRevokeTheSession(t *testing.T) {
+	useMemory(t)
+	useSigningKey(t)
+	user := createUser(t, "user@example.com", auth.RoleCustomer)
+	_, stolen := signIn(t, user)
+	token, current, errs := refresh(t, stolen)
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	_, _, errs = refresh(t, stolen)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), auth.ErrInvalidRefreshToken.Error()) {
+		t.Fatalf("replaying a rotated refresh token: got %v, want %v", errs, auth.ErrInvalidRefreshToken)
+	}
+
+	// Neither the thief nor the owner can keep using the session
+	if _, _, errs := refresh(t, current); len(errs) == 0 {
+		t.Error("the latest refresh token still works after a replay")
+	}
+	if _, err := authenticate(token); err != auth.ErrSessionRevoked {
+		t.Errorf("access token after a replay: err = %v, want %v", err, auth.ErrSessionRevoked)
+	}
+}
+
+func TestLogoutRevokesAccessTokens(t *testing.T) {
+	useMemory(t)
+	useSigningKey(t)
+	user := createUser(t, "user@example.com", auth.RoleCustomer)
+	laptop, _ := signIn(t, user)
+	phone, phoneRefresh := signIn(t, user)
+	tablet, tabletRefresh := signIn(t, user)
+
+	ctx, err := authenticate(laptop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	execute(t, ctx, `mutation { logout }`, nil, nil)
+	if _, err := authenticate(laptop); err != auth.ErrSessionRevoked {
+		t.Errorf("access token after logout: err = %v, want %v", err, auth.ErrSessionRevoked)
+	}
+	if _, err := authenticate(phone); err != nil {
+		t.Fatalf("logout signed out another device: %v", err)
+	}
+
+	ctx, err = authenticate(phone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	execute(t, ctx, `mutation { logoutAllDevices }`, nil, nil)
+	for name, token := range map[string]string{"phone": phone, "tablet": tablet} {
+		if _, err := authenticate(token); err != auth.ErrSessionRevoked {
+			t.Errorf("%s access token after logoutAllDevices: err = %v, want %v", name, err, auth.ErrSessionRevoked)
+		}
+	}
+	for name, refreshToken := range map[string]string{"phone": phoneRefresh, "tablet": tabletRefresh} {
+		if _, _, errs := refresh(t, refreshToken); len(errs) == 0 {
+			t.Errorf("%s refresh token still works after logoutAllDevices", name)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+package graph
+
+import (
+	"ai-catalog/jobs"
+	"ai-catalog/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// JobFillTranslations translates the catalog entries that lack a translation
+const JobFillTranslations = "fill_translations"
+
+// fillTranslationsPayload is the payload of a JobFillTranslations job
+type fillTranslationsPayload struct {
+	Locale string `json:"locale"`
+}
+
+// localizeProducts replaces the name and descriptions of products with their
+// translations into locale. Fields without a translation keep the default locale text.
+func localizeProducts(ctx context.Context, products []*Product, locale string) error {
+	if locale == DefaultLocale() || len(products) == 0 {
+		return nil
+	}
+	ids := make([]int, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+	translations, err := Repos.Translations.Products(ctx, locale, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		translation, ok := translations[product.ID]
+		if !ok {
+			continue
+		}
+		product.Name = translation.Name
+		product.Locale = locale
+		if translation.Description != "" {
+			product.Description = translation.Description
+		}
+		if translation.ShortDescription != "" {
+			product.ShortDescription = translation.ShortDescription
+		}
+	}
+	return nil
+}
+
+// localizeCategories replaces the name and description of categories with their
+// translations into locale
+func localizeCategories(ctx context.Context, categories []*Category, locale string) error {
+	if locale == DefaultLocale() || len(categories) == 0 {
+		return nil
+	}
+	ids := make([]int, len(categories))
+	for i, category := range categories {
+		ids[i] = category.ID
+	}
+	translations, err := Repos.Translations.Categories(ctx, locale, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, category := range categories {
+		translation, ok := translations[category.ID]
+		if !ok {
+			continue
+		}
+		category.Name = translation.Name
+		category.Locale = locale
+		if translation.Description != "" {
+			category.Description = translation.Description
+		}
+	}
+	return nil
+}
+
+// validateTranslationLocale checks that locale can hold translations
+func validateTranslationLocale(problems *ValidationError, locale string) {
+	if !IsSupportedLocale(locale) {
+		problems.Add("locale", "unsupported locale, use ar or en")
+	} else if locale == DefaultLocale() {
+		problems.Add("locale", "the default locale is stored on the entry itself")
+	}
+}
+
+// SetProductTranslation creates or replaces the translation of a product into a locale
+func SetProductTranslation(ctx context.Context, productID int, locale, name, description, shortDescription string) (*Product, error) {
+	problems := NewValidationError()
+	validateTranslationLocale(problems, locale)
+	if strings.TrimSpace(name) == "" {
+		problems.Add("name", "is required")
+	}
+	if len([]rune(shortDescription)) > 500 {
+		problems.Add("shortDescription", "must be at most 500 characters")
+	}
+	if err := problems.OrNil(); err != nil {
+		return nil, err
+	}
+
+	product, err := Repos.Products.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = Repos.Translations.SetProduct(ctx, locale, repository.Translation{
+		ID:               productID,
+		Name:             strings.TrimSpace(name),
+		Description:      strings.TrimSpace(description),
+		ShortDescription: strings.TrimSpace(shortDescription),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := localizeProducts(ctx, []*Product{product}, locale); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// SetCategoryTranslation creates or replaces the translation of a category into a locale
+func SetCategoryTranslation(ctx context.Context, categoryID int, locale, name, description string) (*Category, error) {
+	problems := NewValidationError()
+	validateTranslationLocale(problems, locale)
+	if strings.TrimSpace(name) == "" {
+		problems.Add("name", "is required")
+	}
+	if err := problems.OrNil(); err != nil {
+		return nil, err
+	}
+
+	category, err := Repos.Categories.GetByID(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = Repos.Translations.SetCategory(ctx, locale, repository.Translation{
+		ID:          categoryID,
+		Name:        strings.TrimSpace(name),
+		Description: strings.TrimSpace(description),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := localizeCategories(ctx, []*Category{category}, locale); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// enqueueFillTranslations validates the locale and queues a job that translates
+// every product and category missing text in it
+func enqueueFillTranslations(ctx context.Context, userID int, locale string) (*jobs.Job, error) {
+	problems := NewValidationError()
+	validateTranslationLocale(problems, locale)
+	if err := problems.OrNil(); err != nil {
+		return nil, err
+	}
+
+	job, err := jobs.Enqueue(ctx, JobFillTranslations, fillTranslationsPayload{Locale: locale}, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue job: %v", err)
+	}
+	return job, nil
+}
+
+// runFillTranslations translates the missing product and category text for a
+// locale. Each entry is saved as soon as it is translated, so a retried job only
+// translates what is still missing.
+func runFillTranslations(ctx context.Context, data json.RawMessage) (interface{}, error) {
+	var payload fillTranslationsPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, jobs.Permanent(err)
+	}
+	problems := NewValidationError()
+	validateTranslationLocale(problems, payload.Locale)
+	if err := problems.OrNil(); err != nil {
+		return nil, jobs.Permanent(err)
+	}
+
+	from, to := localeNames[DefaultLocale()], localeNames[payload.Locale]
+	translateField := func(text string) (string, error) {
+		if text == "" {
+			return "", nil
+		}
+		return translate(ctx, text, from, to)
+	}
+
+	// Entries are loaded before any translation starts, so no connection is
+	// held while waiting on the language model
+	products, err := Repos.Translations.UntranslatedProducts(ctx, payload.Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range products {
+		name, err := translateField(entry.Name)
+		if err != nil {
+			return nil, err
+		}
+		description, err := translateField(entry.Description)
+		if err != nil {
+			return nil, err
+		}
+		shortDescription, err := translateField(entry.ShortDescription)
+		if err != nil {
+			return nil, err
+		}
+
+		// Keep existing translations, which may have been written by hand
+		err = Repos.Translations.FillProduct(ctx, payload.Locale, repository.Translation{
+			ID:               entry.ID,
+			Name:             name,
+			Description:      description,
+			ShortDescription: shortDescription,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	categories, err := Repos.Translations.UntranslatedCategories(ctx, payload.Locale)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range categories {
+		name, err := translateField(entry.Name)
+		if err != nil {
+			return nil, err
+		}
+		description, err := translateField(entry.Description)
+		if err != nil {
+			return nil, err
+		}
+
+		err = Repos.Translations.FillCategory(ctx, payload.Locale, repository.Translation{
+			ID:          entry.ID,
+			Name:        name,
+			Description: description,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return map[string]interface{}{
+		"locale":     payload.Locale,
+		"products":   len(products),
+		"categories": len(categories),
+	}, nil
+}
@@ -0,0 +1,120 @@
+package graph
+
+import (
+	"ai-catalog/handlers"
+	"ai-catalog/repository"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// sourceHash keys the translation memory by source text with whitespace
+// collapsed, so strings that differ only in spacing share an entry
+func sourceHash(text string) string {
+	sum := sha256.Sum256([]byte(strings.Join(strings.Fields(text), " ")))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeLanguage lowercases a language name so "Arabic" and "arabic" match
+func normalizeLanguage(language string) string {
+	return strings.ToLower(strings.TrimSpace(language))
+}
+
+// translate returns the translation of text, using the translation memory before
+// calling the language model and remembering what the model returns
+func translate(ctx context.Context, text, from, to string) (string, error) {
+	text = strings.TrimSpace(text)
+	from, to = normalizeLanguage(from), normalizeLanguage(to)
+	if text == "" {
+		return "", nil
+	}
+	hash := sourceHash(text)
+
+	model, err := handlers.ModelFor(handlers.TaskTranslation)
+	if err != nil {
+		return "", err
+	}
+
+	translated, err := Repos.TranslationMemory.Lookup(ctx, hash, from, to, model)
+	if err == nil {
+		return translated, nil
+	}
+	if err != repository.ErrTranslationNotFound {
+		return "", err
+	}
+
+	translated, err = handlers.TranslateText(ctx, text, from, to)
+	if err != nil {
+		return "", err
+	}
+	translated = strings.TrimSpace(translated)
+
+	err = Repos.TranslationMemory.Remember(ctx, repository.NewTranslationMemoryEntry{
+		SourceHash:     hash,
+		SourceText:     text,
+		SourceLang:     from,
+		TargetLang:     to,
+		Model:          model,
+		TranslatedText: translated,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to store translation: %v", err)
+	}
+	return translated, nil
+}
+
+// SetTranslationOverride stores a linguist's translation of text, which is used
+// from then on instead of any machine translation
+func SetTranslationOverride(ctx context.Context, text, from, to, translation string, userID int) (*TranslationMemoryEntry, error) {
+	text = strings.TrimSpace(text)
+	from, to = normalizeLanguage(from), normalizeLanguage(to)
+	translation = strings.TrimSpace(translation)
+
+	problems := NewValidationError()
+	if text == "" {
+		problems.Add("text", "is required")
+	}
+	if from == "" {
+		problems.Add("from", "is required")
+	}
+	if to == "" {
+		problems.Add("to", "is required")
+	}
+	if translation == "" {
+		problems.Add("translation", "is required")
+	}
+	if err := problems.OrNil(); err != nil {
+		return nil, err
+	}
+
+	return Repos.TranslationMemory.SetOverride(ctx, repository.NewTranslationMemoryEntry{
+		SourceHash:     sourceHash(text),
+		SourceText:     text,
+		SourceLang:     from,
+		TargetLang:     to,
+		TranslatedText: translation,
+	}, userID)
+}
+
+// DeleteTranslationMemoryEntry removes an entry so the text is translated again
+// by the model the next time it is requested
+func DeleteTranslationMemoryEntry(ctx context.Context, id int) (bool, error) {
+	return Repos.TranslationMemory.Delete(ctx, id)
+}
+
+// ListTranslationMemory returns entries matching the filters, overrides and most
+// used entries first
+func ListTranslationMemory(ctx context.Context, search, from, to string, overridesOnly bool, limit int) ([]*TranslationMemoryEntry, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	return Repos.TranslationMemory.List(ctx, repository.TranslationMemoryFilter{
+		Search:        search,
+		SourceLang:    normalizeLanguage(from),
+		TargetLang:    normalizeLanguage(to),
+		OverridesOnly: overridesOnly,
+		Limit:         limit,
+	})
+}
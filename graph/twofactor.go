@@ -0,0 +1,212 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// TwoFactorSetupRequiredError is returned for admin operations while the user
+// is required to use two-factor authentication but has not enrolled yet
+type TwoFactorSetupRequiredError struct {
+	Operation string
+}
+
+func (e *TwoFactorSetupRequiredError) Error() string {
+	return fmt.Sprintf("forbidden: %s requires two-factor authentication to be enabled", e.Operation)
+}
+
+// Extensions exposes a machine-readable error code to GraphQL clients
+func (e *TwoFactorSetupRequiredError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": "TWO_FACTOR_SETUP_REQUIRED",
+	}
+}
+
+// adminsRequireTwoFactor reports whether every admin must use 2FA (ADMIN_REQUIRE_2FA)
+func adminsRequireTwoFactor() bool {
+	required, _ := strconv.ParseBool(os.Getenv("ADMIN_REQUIRE_2FA"))
+	return required
+}
+
+// twoFactorSetupRequired reports whether a user must enroll in 2FA before continuing
+func twoFactorSetupRequired(user *User) bool {
+	if user.TwoFactorEnabled {
+		return false
+	}
+	return user.TwoFactorRequired || (user.Role == auth.RoleAdmin && adminsRequireTwoFactor())
+}
+
+// completeLogin finishes a successful password step: users with 2FA enabled get a
+// challenge to pass to verifyTwoFactor, everyone else gets a session right away
+func completeLogin(ctx context.Context, user *User) (*AuthResponse, error) {
+	if !user.TwoFactorEnabled {
+		response, err := newAuthResponse(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		response.TwoFactorSetupRequired = twoFactorSetupRequired(user)
+		return response, nil
+	}
+
+	challenge, expiresAt, err := auth.GenerateTwoFactorChallenge(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthResponse{
+		ExpiresAt:         expiresAt,
+		TwoFactorRequired: true,
+		ChallengeToken:    challenge,
+	}, nil
+}
+
+// verifyTwoFactorLogin completes a login started with a 2FA challenge. Wrong codes
+// count towards the same lockout as wrong passwords, and each challenge can only
+// complete one login.
+func verifyTwoFactorLogin(p graphql.ResolveParams, challengeToken, code string) (*AuthResponse, error) {
+	challenge, err := auth.ValidateTwoFactorChallenge(challengeToken)
+	if err != nil {
+		return nil, err
+	}
+	user, err := LoadAuthenticatedUser(p.Context, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	userAgent, ipAddress := requestClient(p.Context)
+	if _, err := checkLoginAllowed(p.Context, user.Email, ipAddress); err != nil {
+		if _, locked := err.(*auth.LoginLockedError); locked {
+			recordLoginAttempt(p.Context, user.Email, ipAddress, userAgent, &user.ID, false, auth.LoginReasonLocked)
+		}
+		return nil, err
+	}
+
+	if err := checkTwoFactorCode(p.Context, user.ID, code); err != nil {
+		if err == auth.ErrInvalidTwoFactorCode {
+			recordLoginAttempt(p.Context, user.Email, ipAddress, userAgent, &user.ID, false, auth.LoginReasonInvalidTwoFactor)
+		}
+		return nil, err
+	}
+
+	unused, err := Repos.TwoFactor.UseChallenge(p.Context, challenge.ID, challenge.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if !unused {
+		return nil, auth.ErrInvalidChallenge
+	}
+
+	recordLoginAttempt(p.Context, user.Email, ipAddress, userAgent, &user.ID, true, auth.LoginReasonSuccess)
+	return newAuthResponse(p.Context, user)
+}
+
+// checkTwoFactorCode accepts either a current TOTP code or an unused recovery code.
+// Each TOTP time step can only be used once.
+func checkTwoFactorCode(ctx context.Context, userID int, code string) error {
+	secret, enabled, err := Repos.TwoFactor.Secret(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !enabled || secret == "" {
+		return fmt.Errorf("two-factor authentication is not enabled")
+	}
+
+	if step, ok := auth.VerifyTOTP(secret, code, time.Now()); ok {
+		// A replayed step is rejected like a wrong code
+		used, err := Repos.TwoFactor.UseStep(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return auth.ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	ok, err := Repos.TwoFactor.UseRecoveryCode(ctx, userID, auth.HashToken(auth.NormalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return auth.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// replaceRecoveryCodes discards a user's recovery codes and issues a new set.
+// Only hashes are stored; the plain codes are returned once to show the user.
+func replaceRecoveryCodes(ctx context.Context, userID int) ([]string, error) {
+	codes, err := auth.GenerateRecoveryCodes(auth.RecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = auth.HashToken(code)
+	}
+	if err := Repos.TwoFactor.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// startTwoFactorSetup stores a new pending secret for a user who has not enabled 2FA yet
+func startTwoFactorSetup(ctx context.Context, user *User) (*TwoFactorSetup, error) {
+	if user.TwoFactorEnabled {
+		return nil, fmt.Errorf("two-factor authentication is already enabled")
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := Repos.TwoFactor.SetPendingSecret(ctx, user.ID, secret); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorSetup{
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(secret, user.Email),
+	}, nil
+}
+
+// confirmTwoFactorSetup enables 2FA once the user proves their authenticator works
+// and returns a fresh set of recovery codes
+func confirmTwoFactorSetup(ctx context.Context, user *User, code string) ([]string, error) {
+	secret, enabled, err := Repos.TwoFactor.Secret(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, fmt.Errorf("two-factor authentication is already enabled")
+	}
+	if secret == "" {
+		return nil, fmt.Errorf("call enableTwoFactor before confirming")
+	}
+
+	step, ok := auth.VerifyTOTP(secret, code, time.Now())
+	if !ok {
+		return nil, auth.ErrInvalidTwoFactorCode
+	}
+
+	if err := Repos.TwoFactor.Enable(ctx, user.ID, step); err != nil {
+		return nil, err
+	}
+	InvalidateAuthenticatedUser(user.ID)
+
+	return replaceRecoveryCodes(ctx, user.ID)
+}
+
+// disableTwoFactor turns 2FA off and removes the secret and recovery codes
+func disableTwoFactor(ctx context.Context, userID int) error {
+	if err := Repos.TwoFactor.Disable(ctx, userID); err != nil {
+		return err
+	}
+	InvalidateAuthenticatedUser(userID)
+	return nil
+}
@@ -0,0 +1,144 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// enableTOTP turns on two-factor authentication for a user and returns the secret
+func enableTOTP(t *testing.T, user *User) string {
+	t.Helper()
+	ctx := context.Background()
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Repos.TwoFactor.SetPendingSecret(ctx, user.ID, secret); err != nil {
+		t.Fatal(err)
+	}
+	if err := Repos.TwoFactor.Enable(ctx, user.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	user.TwoFactorEnabled = true
+	return secret
+}
+
+// totpCodeAt returns the code an authenticator app shows for secret at time at
+func totpCodeAt(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(at.Unix()/30))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:])&0x7fffffff)%1000000)
+}
+
+func TestTOTPCodesCannotBeReplayed(t *testing.T) {
+	useMemory(t)
+	ctx := context.Background()
+	user := createUser(t, "user@example.com", auth.RoleCustomer)
+	secret := enableTOTP(t, user)
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		code    string
+		wantErr error
+	}{
+		{"current code", totpCodeAt(t, secret, now), nil},
+		{"current code again", totpCodeAt(t, secret, now), auth.ErrInvalidTwoFactorCode},
+		{"previous code", totpCodeAt(t, secret, now.Add(-30*time.Second)), auth.ErrInvalidTwoFactorCode},
+		{"next code", totpCodeAt(t, secret, now.Add(30*time.Second)), nil},
+		{"next code again", totpCodeAt(t, secret, now.Add(30*time.Second)), auth.ErrInvalidTwoFactorCode},
+	}
+	for _, tt := range tests {
+		if err := checkTwoFactorCode(ctx, user.ID, tt.code); err != tt.wantErr {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRecoveryCodesAreSingleUse(t *testing.T) {
+	useMemory(t)
+	ctx := context.Background()
+	user := createUser(t, "user@example.com", auth.RoleCustomer)
+	enableTOTP(t, user)
+	codes, err := replaceRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Codes are accepted however the user types them, but only once
+	typed := strings.ToUpper(strings.ReplaceAll(codes[0], "-", " "))
+	if err := checkTwoFactorCode(ctx, user.ID, typed); err != nil {
+		t.Fatalf("first use of a recovery code: %v", err)
+	}
+	if err := checkTwoFactorCode(ctx, user.ID, codes[0]); err != auth.ErrInvalidTwoFactorCode {
+		t.Errorf("second use of a recovery code: got %v, want %v", err, auth.ErrInvalidTwoFactorCode)
+	}
+	if err := checkTwoFactorCode(ctx, user.ID, codes[1]); err != nil {
+		t.Errorf("another recovery code: %v", err)
+	}
+
+	if _, err := replaceRecoveryCodes(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkTwoFactorCode(ctx, user.ID, codes[2]); err != auth.ErrInvalidTwoFactorCode {
+		t.Errorf("replaced recovery code: got %v, want %v", err, auth.ErrInvalidTwoFactorCode)
+	}
+}
+
+func TestTwoFactorChallengesCompleteOneLogin(t *testing.T) {
+	useMemory(t)
+	key, err := auth.NewHMACKey("test", []byte("test-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth.UseKeys(key)
+	user := createUser(t, "user@example.com", auth.RoleCustomer)
+	secret := enableTOTP(t, user)
+	response, err := completeLogin(context.Background(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verify := `mutation($challenge: String!, $code: String!) { verifyTwoFactor(challengeToken: $challenge, code: $code) { token } }`
+	ctx := clientContext("203.0.113.9:4000", "")
+
+	// A mistyped code leaves the challenge usable
+	errs := run(t, ctx, verify, map[string]interface{}{"challenge": response.ChallengeToken, "code": "wrong-code"}, nil)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), auth.ErrInvalidTwoFactorCode.Error()) {
+		t.Fatalf("verifyTwoFactor with a wrong code: got %v", errs)
+	}
+	challenge, err := auth.ValidateTwoFactorChallenge(response.ChallengeToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	used, err := Repos.TwoFactor.UseChallenge(context.Background(), challenge.ID, challenge.ExpiresAt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !used {
+		t.Fatal("a wrong code used up the challenge")
+	}
+
+	// The challenge has now completed a login, so even a valid code is refused
+	errs = run(t, ctx, verify, map[string]interface{}{"challenge": response.ChallengeToken, "code": totpCodeAt(t, secret, time.Now())}, nil)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), auth.ErrInvalidChallenge.Error()) {
+		t.Errorf("verifyTwoFactor with a used challenge: got %v, want %v", errs, auth.ErrInvalidChallenge)
+	}
+}
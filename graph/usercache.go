@@ -0,0 +1,74 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Errors returned for tokens whose account can no longer be used
+var (
+	ErrAccountSuspended = errors.New("account is suspended")
+	ErrAccountDeleted   = errors.New("account has been deleted")
+)
+
+// authUserCacheTTL bounds how long a suspended or changed account can keep using a cached record
+const authUserCacheTTL = 30 * time.Second
+
+type cachedUser struct {
+	user     User
+	loadedAt time.Time
+}
+
+var (
+	authUsersMu sync.Mutex
+	authUsers   = map[int]cachedUser{}
+)
+
+// LoadAuthenticatedUser returns the full user record for an authenticated user ID.
+// Records are cached briefly so AuthMiddleware does not query the database on
+// every request. Suspended and deleted accounts are rejected.
+func LoadAuthenticatedUser(ctx context.Context, id int) (*User, error) {
+	authUsersMu.Lock()
+	entry, ok := authUsers[id]
+	authUsersMu.Unlock()
+
+	if !ok || time.Since(entry.loadedAt) > authUserCacheTTL {
+		user, err := Repos.Users.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		entry = cachedUser{user: *user, loadedAt: time.Now()}
+
+		authUsersMu.Lock()
+		authUsers[id] = entry
+		authUsersMu.Unlock()
+	}
+
+	if err := checkAccountStatus(&entry.user); err != nil {
+		return nil, err
+	}
+
+	user := entry.user
+	return &user, nil
+}
+
+// InvalidateAuthenticatedUser drops a cached user so the next request reloads it
+func InvalidateAuthenticatedUser(id int) {
+	authUsersMu.Lock()
+	delete(authUsers, id)
+	authUsersMu.Unlock()
+}
+
+// checkAccountStatus returns an error unless the account is active
+func checkAccountStatus(user *User) error {
+	switch user.Status {
+	case auth.StatusSuspended:
+		return ErrAccountSuspended
+	case auth.StatusDeleted:
+		return ErrAccountDeleted
+	}
+	return nil
+}
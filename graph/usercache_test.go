@@ -0,0 +1,39 @@
+package graph
+
+import (
+	"ai-catalog/auth"
+	"testing"
+)
+
+func TestSuspensionTakesEffectOnTheNextRequest(t *testing.T) {
+	useMemory(t)
+	useSigningKey(t)
+	admin := createUser(t, "admin@example.com", auth.RoleAdmin)
+	user := createUser(t, "user@example.com", auth.RoleCustomer)
+	token, _ := signIn(t, user)
+	rawKey := createAPIKey(t, user, auth.ScopeOrdersRead)
+
+	// Both requests load the user into the cache
+	if _, err := authenticate(token); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := withAPIKey(rawKey); err != nil {
+		t.Fatal(err)
+	}
+
+	updateStatus := `mutation($id: Int!, $status: String!) { updateUserStatus(userId: $id, status: $status) { status } }`
+	execute(t, asUser(admin), updateStatus, map[string]interface{}{"id": user.ID, "status": auth.StatusSuspended}, nil)
+
+	// Well within authUserCacheTTL, the cached active record is not used
+	if _, err := authenticate(token); err == nil {
+		t.Error("an access token of a suspended user was accepted")
+	}
+	if _, err := withAPIKey(rawKey); err != ErrAccountSuspended {
+		t.Errorf("API key of a suspended user: err = %v, want %v", err, ErrAccountSuspended)
+	}
+
+	execute(t, asUser(admin), updateStatus, map[string]interface{}{"id": user.ID, "status": auth.StatusActive}, nil)
+	if _, err := withAPIKey(rawKey); err != nil {
+		t.Errorf("API key after reactivation: %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+)
+
+// ValidationError reports invalid mutation input, keyed by input field
+type ValidationError struct {
+	Fields map[string]string
+}
+
+// NewValidationError returns an empty ValidationError to collect problems into
+func NewValidationError() *ValidationError {
+	return &ValidationError{Fields: map[string]string{}}
+}
+
+// Add records a problem with a field, keeping the first one reported
+func (e *ValidationError) Add(field, message string) {
+	if _, exists := e.Fields[field]; !exists {
+		e.Fields[field] = message
+	}
+}
+
+// OrNil returns the error if any problem was recorded, so callers can write
+// `if err := v.OrNil(); err != nil`
+func (e *ValidationError) OrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	messages := make([]string, len(fields))
+	for i, field := range fields {
+		messages[i] = field + ": " + e.Fields[field]
+	}
+	return "invalid input: " + strings.Join(messages, "; ")
+}
+
+// Extensions exposes a machine-readable error code and the failing fields to GraphQL clients
+func (e *ValidationError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":   "BAD_USER_INPUT",
+		"fields": e.Fields,
+	}
+}
@@ -0,0 +1,209 @@
+package graph
+
+import (
+	"ai-catalog/model"
+	"ai-catalog/repository"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Errors returned by the variant mutations and addToCart
+var (
+	ErrVariantNotFound = repository.ErrVariantNotFound
+	ErrVariantRequired = repository.ErrVariantRequired
+)
+
+// variantAvailable reports whether a variant can be bought
+func variantAvailable(variant *ProductVariant) bool {
+	return variant.IsActive && variant.StockQuantity > 0
+}
+
+// activeVariants returns the active variants of a product
+func activeVariants(ctx context.Context, productID int) ([]*ProductVariant, error) {
+	variants, err := Repos.Variants.ListForProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	active := []*ProductVariant{}
+	for _, variant := range variants {
+		if variant.IsActive {
+			active = append(active, variant)
+		}
+	}
+	return active, nil
+}
+
+// productOptions lists the option types of variants with their values, both in
+// the order the variants introduce them. A value is available when some variant
+// with it is.
+func productOptions(variants []*ProductVariant) []*ProductOption {
+	options := []*ProductOption{}
+	byName := map[string]*ProductOption{}
+	values := map[[2]string]*ProductOptionValue{}
+	for _, variant := range variants {
+		for _, selected := range variant.Options {
+			option, ok := byName[selected.Name]
+			if !ok {
+				option = &ProductOption{Name: selected.Name, Values: []*ProductOptionValue{}}
+				byName[selected.Name] = option
+				options = append(options, option)
+			}
+			key := [2]string{selected.Name, selected.Value}
+			value, ok := values[key]
+			if !ok {
+				value = &ProductOptionValue{Value: selected.Value}
+				values[key] = value
+				option.Values = append(option.Values, value)
+			}
+			value.Available = value.Available || variantAvailable(variant)
+		}
+	}
+	return options
+}
+
+// validateVariantImages trims image URLs in place and checks their length
+func validateVariantImages(problems *ValidationError, images []string) {
+	for i, image := range images {
+		images[i] = strings.TrimSpace(image)
+		if images[i] == "" {
+			problems.Add("images", "must not contain empty URLs")
+		} else if len([]rune(images[i])) > 500 {
+			problems.Add("images", "URLs must be at most 500 characters")
+		}
+	}
+}
+
+// validateVariantSKU checks a trimmed variant SKU. variantID is 0 when creating
+// a variant.
+func validateVariantSKU(ctx context.Context, problems *ValidationError, sku string, variantID int) {
+	if sku == "" {
+		problems.Add("sku", "is required")
+		return
+	}
+	if len([]rune(sku)) > 100 {
+		problems.Add("sku", "must be at most 100 characters")
+		return
+	}
+	taken, err := Repos.Variants.SKUTaken(ctx, sku, variantID)
+	if err != nil || taken {
+		problems.Add("sku", repository.ErrSKUTaken.Error())
+	}
+}
+
+// variantOptions validates the options of a new variant. Every variant of a
+// product has the same option types in the same order, so the first variant
+// fixes them.
+func variantOptions(ctx context.Context, problems *ValidationError, productID int, input []SelectedOptionInput) []*model.SelectedOption {
+	options := make([]*model.SelectedOption, len(input))
+	names := make([]string, len(input))
+	seen := map[string]bool{}
+	for i, option := range input {
+		options[i] = &model.SelectedOption{Name: strings.TrimSpace(option.Name), Value: strings.TrimSpace(option.Value)}
+		names[i] = options[i].Name
+		switch {
+		case options[i].Name == "" || options[i].Value == "":
+			problems.Add("options", "names and values are required")
+		case len([]rune(options[i].Name)) > 50 || len([]rune(options[i].Value)) > 100:
+			problems.Add("options", "names must be at most 50 and values at most 100 characters")
+		case seen[strings.ToLower(options[i].Name)]:
+			problems.Add("options", fmt.Sprintf("%s is given more than once", options[i].Name))
+		}
+		seen[strings.ToLower(options[i].Name)] = true
+	}
+	if len(options) == 0 {
+		problems.Add("options", "must have at least one option")
+		return options
+	}
+
+	variants, err := Repos.Variants.ListForProduct(ctx, productID)
+	if err != nil || len(variants) == 0 {
+		return options
+	}
+	existing := make([]string, len(variants[0].Options))
+	for i, option := range variants[0].Options {
+		existing[i] = option.Name
+	}
+	if strings.Join(names, "\x00") != strings.Join(existing, "\x00") {
+		problems.Add("options", fmt.Sprintf("must be %s, like the product's other variants", strings.Join(existing, ", ")))
+	}
+	return options
+}
+
+// variantError reports the conflicts found on write, when another request
+// took the SKU or options after validation, as validation errors
+func variantError(err error) error {
+	switch err {
+	case repository.ErrSKUTaken:
+		return &ValidationError{Fields: map[string]string{"sku": err.Error()}}
+	case repository.ErrVariantExists:
+		return &ValidationError{Fields: map[string]string{"options": err.Error()}}
+	}
+	return err
+}
+
+// CreateProductVariant validates the input and adds a variant to a product
+func CreateProductVariant(ctx context.Context, input CreateProductVariantInput) (*ProductVariant, error) {
+	problems := NewValidationError()
+	if _, err := Repos.Products.GetByID(ctx, input.ProductID); err != nil {
+		problems.Add("productId", "product does not exist")
+	}
+	variant := repository.NewVariant{
+		ProductID:     input.ProductID,
+		SKU:           strings.TrimSpace(input.SKU),
+		Price:         input.Price,
+		StockQuantity: input.StockQuantity,
+		Options:       variantOptions(ctx, problems, input.ProductID, input.Options),
+		Images:        append([]string{}, input.Images...),
+	}
+	validateVariantSKU(ctx, problems, variant.SKU, 0)
+	if variant.Price < 0 {
+		problems.Add("price", "must not be negative")
+	}
+	if variant.StockQuantity < 0 {
+		problems.Add("stockQuantity", "must not be negative")
+	}
+	validateVariantImages(problems, variant.Images)
+	if err := problems.OrNil(); err != nil {
+		return nil, err
+	}
+
+	created, err := Repos.Variants.Create(ctx, variant)
+	if err != nil {
+		return nil, variantError(err)
+	}
+	return created, nil
+}
+
+// UpdateProductVariant validates the input and updates the provided fields of
+// a variant
+func UpdateProductVariant(ctx context.Context, id int, input UpdateProductVariantInput) (*ProductVariant, error) {
+	problems := NewValidationError()
+	update := repository.VariantUpdate{
+		Price:         input.Price,
+		StockQuantity: input.StockQuantity,
+		Images:        input.Images,
+		IsActive:      input.IsActive,
+	}
+	if input.SKU != nil {
+		sku := strings.TrimSpace(*input.SKU)
+		update.SKU = &sku
+		validateVariantSKU(ctx, problems, sku, id)
+	}
+	if update.Price != nil && *update.Price < 0 {
+		problems.Add("price", "must not be negative")
+	}
+	if update.StockQuantity != nil && *update.StockQuantity < 0 {
+		problems.Add("stockQuantity", "must not be negative")
+	}
+	validateVariantImages(problems, update.Images)
+	if err := problems.OrNil(); err != nil {
+		return nil, err
+	}
+
+	variant, err := Repos.Variants.Update(ctx, id, update)
+	if err != nil {
+		return nil, variantError(err)
+	}
+	return variant, nil
+}
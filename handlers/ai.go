@@ -1,84 +1,88 @@
-package handlers
-
-import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"os"
-)
-
-type OpenRouterRequest struct {
-	Model  string    `json:"model"`
-	Messages []Message `json:"messages"`
-}
-
-type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-type OpenRouterResponse struct {
-	Choices []Choice `json:"choices"`
-}
-
-type Choice struct {
-	Message Message `json:"message"`
-}
-
-// GenerateDescription generates a marketing description for a product in Arabic
-func GenerateDescription(productName, category string) (string, error) {
-	apiKey := os.Getenv("OPENROUTER_API_KEY")
-	if apiKey == "" {
-		return "", fmt.Errorf("OPENROUTER_API_KEY environment variable not set")
-	}
-
-	prompt := fmt.Sprintf("Write a short 3-sentence marketing description for product: %s in category %s in Arabic.", productName, category)
-	
-	requestBody := OpenRouterRequest{
-		Model: "qwen/qwen3-coder:free",
-		Messages: []Message{
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
-	}
-
-	jsonData, err := json.Marshal(requestBody)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %v", err)
-	}
-
-	req, err := http.NewRequest("POST", "https://openrouter.ai/api/v1/chat/completions", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %v", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("HTTP-Referer", "https://ai-catalog.com")
-	req.Header.Set("X-Title", "AI Catalog")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to send request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API request failed with status: %d", resp.StatusCode)
-	}
-
-	var response OpenRouterResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("failed to decode response: %v", err)
-	}
-
-	if len(response.Choices) == 0 {
-		return "", fmt.Errorf("no choices in response")
-	}
-
-	return response.Choices[0].Message.Content, nil
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// DescriptionLanguages maps the supported description language codes to the
+// language name used in the prompt
+var DescriptionLanguages = map[string]string{
+	"ar": "Arabic",
+	"en": "English",
+}
+
+// DescriptionTones lists the tones a description can be written in
+var DescriptionTones = []string{"professional", "friendly", "luxury", "playful"}
+
+// Default language and tone for generated descriptions
+const (
+	DefaultDescriptionLanguage = "ar"
+	DefaultDescriptionTone     = "professional"
+)
+
+// maxShortDescriptionLength matches products.short_description
+const maxShortDescriptionLength = 500
+
+// ProductCopy is generated marketing copy for a product
+type ProductCopy struct {
+	ShortDescription string `json:"shortDescription"`
+	Description      string `json:"description"`
+}
+
+// GenerateDescription generates marketing copy for a product in the given
+// language code and tone
+func GenerateDescription(ctx context.Context, productName, category, language, tone string) (*ProductCopy, error) {
+	languageName, ok := DescriptionLanguages[language]
+	if !ok {
+		return nil, fmt.Errorf("unsupported description language: %s", language)
+	}
+
+	prompt := fmt.Sprintf(
+		"Write marketing copy in %s with a %s tone for the product \"%s\" in the category \"%s\". "+
+			"Respond with JSON only, in the form {\"shortDescription\": \"one sentence\", \"description\": \"three sentences\"}.",
+		languageName, tone, productName, category,
+	)
+
+	content, err := complete(ctx, TaskDescription, prompt)
+	if err != nil {
+		return nil, err
+	}
+	return parseProductCopy(content), nil
+}
+
+// parseProductCopy extracts the JSON copy from a model reply. Models sometimes wrap
+// the JSON in prose or code fences, or ignore the format entirely, in which case the
+// whole reply becomes the description and its first sentence the short description.
+func parseProductCopy(content string) *ProductCopy {
+	content = strings.TrimSpace(content)
+
+	var result ProductCopy
+	start, end := strings.Index(content, "{"), strings.LastIndex(content, "}")
+	if start < 0 || end <= start || json.Unmarshal([]byte(content[start:end+1]), &result) != nil || result.Description == "" {
+		result = ProductCopy{Description: content}
+	}
+
+	result.Description = strings.TrimSpace(result.Description)
+	result.ShortDescription = strings.TrimSpace(result.ShortDescription)
+	if result.ShortDescription == "" {
+		result.ShortDescription = firstSentence(result.Description)
+	}
+	if runes := []rune(result.ShortDescription); len(runes) > maxShortDescriptionLength {
+		result.ShortDescription = string(runes[:maxShortDescriptionLength])
+	}
+	return &result
+}
+
+// firstSentence returns text up to and including its first sentence terminator
+func firstSentence(text string) string {
+	for i, r := range text {
+		if strings.ContainsRune(".!?؟\n", r) {
+			return strings.TrimSpace(text[:i+utf8.RuneLen(r)])
+		}
+	}
+	return text
 } 
\ No newline at end of file
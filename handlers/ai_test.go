@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// useFakeLLM makes the package use a fake client with the given replies for the test
+func useFakeLLM(t *testing.T, responses map[string]string) *FakeLLMClient {
+	t.Helper()
+	client := &FakeLLMClient{Responses: responses}
+	SetLLMClient(client)
+	t.Cleanup(func() { SetLLMClient(nil) })
+	return client
+}
+
+func TestGenerateDescriptionPromptsForTheLanguageAndTone(t *testing.T) {
+	client := useFakeLLM(t, map[string]string{
+		TaskDescription: `{"shortDescription": "هاتف ذكي.", "description": "هاتف ذكي بشاشة كبيرة."}`,
+	})
+
+	generated, err := GenerateDescription(context.Background(), "Phone X", "Phones", "ar", "luxury")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if generated.ShortDescription != "هاتف ذكي." || generated.Description != "هاتف ذكي بشاشة كبيرة." {
+		t.Errorf("generated copy = %+v", generated)
+	}
+
+	requests := client.Requests()
+	if len(requests) != 1 || requests[0].Task != TaskDescription {
+		t.Fatalf("requests = %+v, want one description request", requests)
+	}
+	prompt := requests[0].Messages[0].Content
+	for _, want := range []string{"Arabic", "luxury", `"Phone X"`, `"Phones"`} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt %q does not contain %s", prompt, want)
+		}
+	}
+}
+
+func TestGenerateDescriptionRejectsUnsupportedLanguages(t *testing.T) {
+	client := useFakeLLM(t, nil)
+	if _, err := GenerateDescription(context.Background(), "Phone", "", "fr", DefaultDescriptionTone); err == nil {
+		t.Error("GenerateDescription accepted fr")
+	}
+	if requests := client.Requests(); len(requests) != 0 {
+		t.Errorf("the model was called for an unsupported language: %+v", requests)
+	}
+}
+
+func TestGenerateDescriptionReturnsClientErrors(t *testing.T) {
+	client := useFakeLLM(t, nil)
+	client.Err = errors.New("rate limited")
+	if _, err := GenerateDescription(context.Background(), "Phone", "", "en", DefaultDescriptionTone); err != client.Err {
+		t.Errorf("GenerateDescription: got %v, want the client error", err)
+	}
+}
+
+func TestParseProductCopy(t *testing.T) {
+	long := strings.Repeat("ك", maxShortDescriptionLength+10)
+	tests := []struct {
+		name    string
+		content string
+		want    ProductCopy
+	}{
+		{"json", `{"shortDescription": "Short.", "description": "Long text."}`, ProductCopy{"Short.", "Long text."}},
+		{"code fence", "Here you go:\n```json\n{\"shortDescription\": \"Short.\", \"description\": \"Long text.\"}\n```", ProductCopy{"Short.", "Long text."}},
+		{"missing short description", `{"description": "First one. Second one."}`, ProductCopy{"First one.", "First one. Second one."}},
+		{"prose", "  A great phone! Buy it now.  ", ProductCopy{"A great phone!", "A great phone! Buy it now."}},
+		{"arabic question mark", "هل تبحث عن هاتف؟ هذا هو.", ProductCopy{"هل تبحث عن هاتف؟", "هل تبحث عن هاتف؟ هذا هو."}},
+		{"long short description", long, ProductCopy{long[:maxShortDescriptionLength*len("ك")], long}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseProductCopy(tt.content); *got != tt.want {
+				t.Errorf("parseProductCopy(%q) = %+v, want %+v", tt.content, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateTextUsesTheTranslationTask(t *testing.T) {
+	client := useFakeLLM(t, nil)
+	translated, err := TranslateText(context.Background(), "Phone", "English", "Arabic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "[translation] Translate the following text from English to Arabic: Phone"
+	if translated != want {
+		t.Errorf("TranslateText = %q, want %q", translated, want)
+	}
+	if requests := client.Requests(); len(requests) != 1 || requests[0].Task != TaskTranslation {
+		t.Errorf("requests = %+v, want one translation request", requests)
+	}
+}
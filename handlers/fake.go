@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// FakeLLMClient is a deterministic LLMClient for tests and offline development.
+// It answers with the response configured for the task, or else echoes the last
+// message tagged with the task, and records every request it receives.
+type FakeLLMClient struct {
+	// Responses maps tasks to canned replies
+	Responses map[string]string
+	// Err, when set, is returned instead of a reply
+	Err error
+
+	mu       sync.Mutex
+	requests []CompletionRequest
+}
+
+// Complete returns the canned reply for the request's task
+func (f *FakeLLMClient) Complete(ctx context.Context, req CompletionRequest) (string, error) {
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	f.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if f.Err != nil {
+		return "", f.Err
+	}
+	if response, ok := f.Responses[req.Task]; ok {
+		return response, nil
+	}
+
+	var last string
+	if len(req.Messages) > 0 {
+		last = req.Messages[len(req.Messages)-1].Content
+	}
+	return fmt.Sprintf("[%s] %s", req.Task, last), nil
+}
+
+// Model returns "fake" for every task
+func (f *FakeLLMClient) Model(task string) string {
+	return "fake"
+}
+
+// Requests returns the requests received so far
+func (f *FakeLLMClient) Requests() []CompletionRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]CompletionRequest(nil), f.requests...)
+}
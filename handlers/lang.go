@@ -1,66 +1,12 @@
-package handlers
-
-import (
-	"bytes"
-	"encoding/json"
-	"fmt"
-	"net/http"
-	"os"
-)
-
-// TranslateText translates text from source language to target language using OpenRouter
-func TranslateText(text, sourceLang, targetLang string) (string, error) {
-	apiKey := os.Getenv("OPENROUTER_API_KEY")
-	if apiKey == "" {
-		return "", fmt.Errorf("OPENROUTER_API_KEY environment variable not set")
-	}
-
-	prompt := fmt.Sprintf("Translate the following text from %s to %s: %s", sourceLang, targetLang, text)
-	
-	requestBody := OpenRouterRequest{
-		Model: "openai/gpt-3.5-turbo",
-		Messages: []Message{
-			{
-				Role:    "user",
-				Content: prompt,
-			},
-		},
-	}
-
-	jsonData, err := json.Marshal(requestBody)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %v", err)
-	}
-
-	req, err := http.NewRequest("POST", "https://openrouter.ai/api/v1/chat/completions", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %v", err)
-	}
-
-	req.Header.Set("Authorization", "Bearer "+apiKey)
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("HTTP-Referer", "https://ai-catalog.com")
-	req.Header.Set("X-Title", "AI Catalog")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to send request: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API request failed with status: %d", resp.StatusCode)
-	}
-
-	var response OpenRouterResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("failed to decode response: %v", err)
-	}
-
-	if len(response.Choices) == 0 {
-		return "", fmt.Errorf("no choices in response")
-	}
-
-	return response.Choices[0].Message.Content, nil
-} 
\ No newline at end of file
+package handlers
+
+import (
+	"context"
+	"fmt"
+)
+
+// TranslateText translates text from source language to target language using the configured LLM client
+func TranslateText(ctx context.Context, text, sourceLang, targetLang string) (string, error) {
+	prompt := fmt.Sprintf("Translate the following text from %s to %s: %s", sourceLang, targetLang, text)
+	return complete(ctx, TaskTranslation, prompt)
+}
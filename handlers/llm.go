@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Tasks the language model is used for. Each task can be routed to its own model.
+const (
+	TaskDescription = "description"
+	TaskTranslation = "translation"
+)
+
+// Message is a single chat message sent to or received from a language model
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// CompletionRequest asks a language model to continue a chat for a task
+type CompletionRequest struct {
+	Task     string
+	Messages []Message
+}
+
+// LLMClient generates chat completions
+type LLMClient interface {
+	Complete(ctx context.Context, req CompletionRequest) (string, error)
+	// Model returns the name of the model that handles a task
+	Model(task string) string
+}
+
+// Models maps tasks to model names. The model under the empty task is used for
+// tasks without their own entry.
+type Models map[string]string
+
+// For returns the model configured for a task
+func (m Models) For(task string) string {
+	if model, ok := m[task]; ok && model != "" {
+		return model
+	}
+	return m[""]
+}
+
+var (
+	llmMu     sync.RWMutex
+	llmClient LLMClient
+)
+
+// SetLLMClient sets the client used by GenerateDescription and TranslateText
+func SetLLMClient(client LLMClient) {
+	llmMu.Lock()
+	defer llmMu.Unlock()
+	llmClient = client
+}
+
+// currentLLMClient returns the configured client, creating one from the
+// environment on first use
+func currentLLMClient() (LLMClient, error) {
+	llmMu.RLock()
+	client := llmClient
+	llmMu.RUnlock()
+	if client != nil {
+		return client, nil
+	}
+
+	client, err := LLMClientFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	SetLLMClient(client)
+	return client, nil
+}
+
+// ModelFor returns the name of the model the configured client uses for a task
+func ModelFor(task string) (string, error) {
+	client, err := currentLLMClient()
+	if err != nil {
+		return "", err
+	}
+	return client.Model(task), nil
+}
+
+// complete sends a single user prompt for a task and returns the reply
+func complete(ctx context.Context, task, prompt string) (string, error) {
+	client, err := currentLLMClient()
+	if err != nil {
+		return "", err
+	}
+	return client.Complete(ctx, CompletionRequest{
+		Task:     task,
+		Messages: []Message{{Role: "user", Content: prompt}},
+	})
+}
+
+// LLMClientFromEnv returns the client configured by the LLM_* environment variables.
+// LLM_PROVIDER selects openrouter (the default), openai for any OpenAI-compatible
+// API at LLM_BASE_URL, or fake for a deterministic offline client.
+func LLMClientFromEnv() (LLMClient, error) {
+	models := Models{
+		"":              os.Getenv("LLM_MODEL"),
+		TaskDescription: os.Getenv("LLM_MODEL_DESCRIPTION"),
+		TaskTranslation: os.Getenv("LLM_MODEL_TRANSLATION"),
+	}
+
+	timeout := defaultLLMTimeout
+	if value := os.Getenv("LLM_TIMEOUT"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LLM_TIMEOUT: %v", err)
+		}
+		timeout = d
+	}
+
+	maxRetries := defaultLLMMaxRetries
+	if value := os.Getenv("LLM_MAX_RETRIES"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid LLM_MAX_RETRIES: %q", value)
+		}
+		maxRetries = n
+	}
+
+	switch provider := os.Getenv("LLM_PROVIDER"); provider {
+	case "", "openrouter":
+		apiKey := os.Getenv("LLM_API_KEY")
+		if apiKey == "" {
+			apiKey = os.Getenv("OPENROUTER_API_KEY")
+		}
+		client := NewOpenRouterClient(apiKey, models)
+		client.Timeout = timeout
+		client.MaxRetries = maxRetries
+		return client, nil
+	case "openai":
+		baseURL := os.Getenv("LLM_BASE_URL")
+		if baseURL == "" {
+			baseURL = "https://api.openai.com/v1"
+		}
+		if models.For(TaskDescription) == "" || models.For(TaskTranslation) == "" {
+			return nil, fmt.Errorf("LLM_MODEL must be set for the openai provider")
+		}
+		return &OpenAICompatibleClient{
+			BaseURL:    baseURL,
+			APIKey:     os.Getenv("LLM_API_KEY"),
+			Models:     models,
+			Timeout:    timeout,
+			MaxRetries: maxRetries,
+		}, nil
+	case "fake":
+		return &FakeLLMClient{}, nil
+	default:
+		return nil, fmt.Errorf("unknown LLM_PROVIDER: %s", provider)
+	}
+}
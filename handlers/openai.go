@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLLMTimeout    = 30 * time.Second
+	defaultLLMMaxRetries = 2
+
+	// llmRetryBaseDelay is doubled after every failed attempt, up to llmRetryMaxDelay
+	llmRetryBaseDelay = 500 * time.Millisecond
+	llmRetryMaxDelay  = 30 * time.Second
+)
+
+// OpenAICompatibleClient talks to any API implementing the OpenAI chat completions
+// endpoint, such as OpenAI itself, OpenRouter or a local stand-in server
+type OpenAICompatibleClient struct {
+	BaseURL string
+	APIKey  string
+	Models  Models
+	// Headers are added to every request
+	Headers map[string]string
+	// Timeout bounds each attempt; MaxRetries is the number of extra attempts
+	// made after rate limiting (429) or server (5xx) errors
+	Timeout    time.Duration
+	MaxRetries int
+	// RequireAPIKey fails requests up front when APIKey is empty
+	RequireAPIKey bool
+	// APIKeyName names the missing setting in the error returned for RequireAPIKey
+	APIKeyName string
+
+	httpClient *http.Client
+	once       sync.Once
+}
+
+// NewOpenRouterClient returns a client for the OpenRouter API. Tasks without a
+// configured model use the models the store has always used.
+func NewOpenRouterClient(apiKey string, models Models) *OpenAICompatibleClient {
+	resolved := Models{
+		TaskDescription: "qwen/qwen3-coder:free",
+		TaskTranslation: "openai/gpt-3.5-turbo",
+	}
+	for task := range resolved {
+		if model := models.For(task); model != "" {
+			resolved[task] = model
+		}
+	}
+
+	return &OpenAICompatibleClient{
+		BaseURL: "https://openrouter.ai/api/v1",
+		APIKey:  apiKey,
+		Models:  resolved,
+		Headers: map[string]string{
+			"HTTP-Referer": "https://ai-catalog.com",
+			"X-Title":      "AI Catalog",
+		},
+		Timeout:       defaultLLMTimeout,
+		MaxRetries:    defaultLLMMaxRetries,
+		RequireAPIKey: true,
+		APIKeyName:    "OPENROUTER_API_KEY",
+	}
+}
+
+type chatCompletionRequest struct {
+	Model    string    `json:"model"`
+	Messages []Message `json:"messages"`
+}
+
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message Message `json:"message"`
+	} `json:"choices"`
+}
+
+// statusError reports an unsuccessful HTTP response
+type statusError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration
+}
+
+func (e *statusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("API request failed with status: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("API request failed with status: %d: %s", e.StatusCode, e.Body)
+}
+
+// retryable reports whether the request may succeed if sent again
+func (e *statusError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Model returns the model configured for a task
+func (c *OpenAICompatibleClient) Model(task string) string {
+	return c.Models.For(task)
+}
+
+// Complete sends the chat to the model configured for the task, retrying with
+// exponential backoff when the API is rate limited or unavailable
+func (c *OpenAICompatibleClient) Complete(ctx context.Context, req CompletionRequest) (string, error) {
+	if c.RequireAPIKey && c.APIKey == "" {
+		return "", fmt.Errorf("%s environment variable not set", c.APIKeyName)
+	}
+
+	model := c.Model(req.Task)
+	if model == "" {
+		return "", fmt.Errorf("no model configured for task %q", req.Task)
+	}
+
+	body, err := json.Marshal(chatCompletionRequest{Model: model, Messages: req.Messages})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	delay := llmRetryBaseDelay
+	for attempt := 0; ; attempt++ {
+		content, err := c.send(ctx, body)
+		if err == nil {
+			return content, nil
+		}
+
+		// Network errors and per-attempt timeouts are retried along with 429 and 5xx responses
+		var statusErr *statusError
+		retryable := !errors.As(err, &statusErr) || statusErr.retryable()
+		if !retryable || attempt >= c.MaxRetries || ctx.Err() != nil {
+			return "", err
+		}
+
+		wait := delay
+		if statusErr != nil && statusErr.RetryAfter > 0 {
+			wait = statusErr.RetryAfter
+		}
+		if wait > llmRetryMaxDelay {
+			wait = llmRetryMaxDelay
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", ctx.Err()
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// send makes a single chat completion request
+func (c *OpenAICompatibleClient) send(ctx context.Context, body []byte) (string, error) {
+	c.once.Do(func() {
+		c.httpClient = &http.Client{Timeout: c.Timeout}
+	})
+
+	url := strings.TrimRight(c.BaseURL, "/") + "/chat/completions"
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	}
+	for name, value := range c.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		statusErr := &statusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(snippet))}
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			statusErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return "", statusErr
+	}
+
+	var response chatCompletionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", fmt.Errorf("failed to decode response: %v", err)
+	}
+
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("no choices in response")
+	}
+
+	return response.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// completionServer serves chat completions with reply after the given failing
+// statuses, counting the requests it receives
+func completionServer(t *testing.T, reply string, failures ...int) (*httptest.Server, *int32) {
+	t.Helper()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&requests, 1))
+		if n <= len(failures) {
+			if failures[n-1] == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "1")
+			}
+			http.Error(w, fmt.Sprintf("failure %d", n), failures[n-1])
+			return
+		}
+		fmt.Fprintf(w, `{"choices": [{"message": {"role": "assistant", "content": %q}}]}`, reply)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// testClient returns a client for server that makes up to maxRetries extra attempts
+func testClient(server *httptest.Server, maxRetries int) *OpenAICompatibleClient {
+	return &OpenAICompatibleClient{
+		BaseURL:    server.URL,
+		Models:     Models{"": "test-model"},
+		Timeout:    5 * time.Second,
+		MaxRetries: maxRetries,
+	}
+}
+
+var testCompletion = CompletionRequest{Task: TaskTranslation, Messages: []Message{{Role: "user", Content: "Hello"}}}
+
+func TestRateLimitedRequestsWaitForRetryAfter(t *testing.T) {
+	server, requests := completionServer(t, "Bonjour", http.StatusTooManyRequests)
+
+	start := time.Now()
+	content, err := testClient(server, 2).Complete(context.Background(), testCompletion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content != "Bonjour" {
+		t.Errorf("content = %q, want the reply of the retried request", content)
+	}
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Errorf("sent %d requests, want 2", n)
+	}
+	if waited := time.Since(start); waited < time.Second {
+		t.Errorf("retried after %s, want the one second asked for by Retry-After", waited)
+	}
+}
+
+func TestServerErrorsAreRetriedUpToMaxRetries(t *testing.T) {
+	server, requests := completionServer(t, "Bonjour", http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+
+	_, err := testClient(server, 1).Complete(context.Background(), testCompletion)
+	var statusErr *statusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("err = %v, want the status of the last attempt", err)
+	}
+	if n := atomic.LoadInt32(requests); n != 2 {
+		t.Errorf("sent %d requests, want the first attempt and one retry", n)
+	}
+}
+
+func TestClientErrorsAreNotRetried(t *testing.T) {
+	server, requests := completionServer(t, "Bonjour", http.StatusBadRequest)
+
+	_, err := testClient(server, 2).Complete(context.Background(), testCompletion)
+	var statusErr *statusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusBadRequest || statusErr.Body != "failure 1" {
+		t.Errorf("err = %v, want the 400 response with its body", err)
+	}
+	if n := atomic.LoadInt32(requests); n != 1 {
+		t.Errorf("sent %d requests, want a 400 not to be retried", n)
+	}
+}
+
+func TestOpenAIProviderUsesTheConfiguredBaseURL(t *testing.T) {
+	var got struct {
+		path, authorization string
+		request             chatCompletionRequest
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.path, got.authorization = r.URL.Path, r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got.request); err != nil {
+			t.Error(err)
+		}
+		fmt.Fprint(w, `{"choices": [{"message": {"role": "assistant", "content": "Hola"}}]}`)
+	}))
+	defer server.Close()
+
+	t.Setenv("LLM_PROVIDER", "openai")
+	t.Setenv("LLM_BASE_URL", server.URL+"/v1/")
+	t.Setenv("LLM_API_KEY", "local-key")
+	t.Setenv("LLM_MODEL", "local-model")
+	client, err := LLMClientFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := client.Complete(context.Background(), testCompletion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content != "Hola" {
+		t.Errorf("content = %q", content)
+	}
+	if got.path != "/v1/chat/completions" || got.authorization != "Bearer local-key" || got.request.Model != "local-model" {
+		t.Errorf("request to %s with %q for model %q, want the configured base URL, key and model",
+			got.path, got.authorization, got.request.Model)
+	}
+}
@@ -0,0 +1,66 @@
+package jobs
+
+import (
+	"ai-catalog/model"
+	"ai-catalog/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Repos holds the jobs table
+var Repos *repository.Repositories
+
+// SetRepositories sets the repositories the jobs package stores the queue in
+func SetRepositories(repos *repository.Repositories) {
+	Repos = repos
+}
+
+// Job statuses. Jobs that fail their last attempt, or fail permanently, are
+// dead-lettered with StatusDead and keep their last error for inspection.
+const (
+	StatusPending   = model.JobPending
+	StatusRunning   = model.JobRunning
+	StatusCompleted = model.JobCompleted
+	StatusDead      = model.JobDead
+)
+
+// DefaultMaxAttempts is the number of times a job is tried before it is dead-lettered
+const DefaultMaxAttempts = 5
+
+// ErrNotFound is returned when a job does not exist
+var ErrNotFound = repository.ErrJobNotFound
+
+// Job is a unit of background work stored in the jobs table
+type Job = model.Job
+
+// Enqueue stores a new pending job. createdBy is the ID of the user who asked for
+// the work, or 0 for jobs started by the system.
+func Enqueue(ctx context.Context, jobType string, payload interface{}, createdBy int) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job payload: %v", err)
+	}
+
+	var creator *int
+	if createdBy != 0 {
+		creator = &createdBy
+	}
+
+	return Repos.Jobs.Enqueue(ctx, repository.NewJob{
+		Type:        jobType,
+		Payload:     data,
+		MaxAttempts: DefaultMaxAttempts,
+		CreatedBy:   creator,
+	})
+}
+
+// Get returns a job by ID
+func Get(ctx context.Context, id int) (*Job, error) {
+	return Repos.Jobs.GetByID(ctx, id)
+}
+
+// Retry moves a dead-lettered job back to the queue with a fresh set of attempts
+func Retry(ctx context.Context, id int) (*Job, error) {
+	return Repos.Jobs.Requeue(ctx, id)
+}
@@ -0,0 +1,195 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Handler performs a job. The returned value is stored as the job's JSON result.
+type Handler func(ctx context.Context, payload json.RawMessage) (interface{}, error)
+
+// permanentError marks a failure that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps an error so the job is dead-lettered without further attempts
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// Retry backoff: the delay doubles after every failed attempt, up to retryMaxDelay
+const (
+	retryBaseDelay = 10 * time.Second
+	retryMaxDelay  = 10 * time.Minute
+)
+
+// Worker runs queued jobs in background goroutines
+type Worker struct {
+	// Concurrency is the number of jobs run at the same time
+	Concurrency int
+	// PollInterval is how long an idle worker waits before checking for new jobs
+	PollInterval time.Duration
+	// Timeout bounds a single attempt. Running jobs whose attempt started more than
+	// twice this long ago are assumed abandoned by a crashed worker and picked up
+	// again, or dead-lettered when that was their last attempt.
+	Timeout time.Duration
+
+	handlers map[string]Handler
+}
+
+// NewWorker returns a worker with the default settings
+func NewWorker() *Worker {
+	return &Worker{
+		Concurrency:  2,
+		PollInterval: time.Second,
+		Timeout:      2 * time.Minute,
+		handlers:     map[string]Handler{},
+	}
+}
+
+// WorkerFromEnv returns a worker configured by JOB_WORKERS and JOB_POLL_INTERVAL
+func WorkerFromEnv() (*Worker, error) {
+	w := NewWorker()
+	if value := os.Getenv("JOB_WORKERS"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid JOB_WORKERS: %q", value)
+		}
+		w.Concurrency = n
+	}
+	if value := os.Getenv("JOB_POLL_INTERVAL"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid JOB_POLL_INTERVAL: %q", value)
+		}
+		w.PollInterval = d
+	}
+	return w, nil
+}
+
+// Handle registers the handler for a job type
+func (w *Worker) Handle(jobType string, handler Handler) {
+	w.handlers[jobType] = handler
+}
+
+// Start launches the worker goroutines. They stop claiming jobs when ctx is
+// cancelled; jobs already running are finished, and the returned function
+// waits for them.
+func (w *Worker) Start(ctx context.Context) (wait func()) {
+	var wg sync.WaitGroup
+	for i := 0; i < w.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.loop(ctx)
+		}()
+	}
+	return wg.Wait
+}
+
+// loop claims and runs jobs until ctx is cancelled, sleeping while the queue is empty
+func (w *Worker) loop(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := w.claim(ctx)
+		if err != nil {
+			log.Printf("failed to claim job: %v", err)
+		}
+		if job != nil {
+			w.run(ctx, job)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.PollInterval):
+		}
+	}
+}
+
+// jobTypes lists the job types this worker can run
+func (w *Worker) jobTypes() []string {
+	types := make([]string, 0, len(w.handlers))
+	for jobType := range w.handlers {
+		types = append(types, jobType)
+	}
+	return types
+}
+
+// claim locks the next due job, or a running job abandoned by a crashed worker
+func (w *Worker) claim(ctx context.Context) (*Job, error) {
+	return Repos.Jobs.Claim(ctx, w.jobTypes(), 2*w.Timeout)
+}
+
+// run performs one attempt of a claimed job and records the outcome
+func (w *Worker) run(ctx context.Context, job *Job) {
+	// A started attempt runs to completion on shutdown, bounded by Timeout, and
+	// its outcome is recorded even when it timed out
+	ctx = context.WithoutCancel(ctx)
+	callCtx, cancel := context.WithTimeout(ctx, w.Timeout)
+	defer cancel()
+
+	result, err := w.call(callCtx, job)
+	if err == nil {
+		err = complete(ctx, job.ID, result)
+		if err == nil {
+			return
+		}
+	}
+
+	if recordErr := fail(ctx, job, err); recordErr != nil {
+		log.Printf("failed to record failure of job %d: %v", job.ID, recordErr)
+	}
+}
+
+// call invokes the job's handler, turning panics into errors
+func (w *Worker) call(ctx context.Context, job *Job) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return w.handlers[job.Type](ctx, job.Payload)
+}
+
+// complete stores a job's result
+func complete(ctx context.Context, id int, result interface{}) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return Permanent(fmt.Errorf("failed to encode job result: %v", err))
+	}
+	return Repos.Jobs.Complete(ctx, id, data)
+}
+
+// fail schedules another attempt with exponential backoff, or dead-letters the job
+// once it has used all of its attempts or failed permanently
+func fail(ctx context.Context, job *Job, jobErr error) error {
+	var permanent *permanentError
+	if errors.As(jobErr, &permanent) || job.Attempts >= job.MaxAttempts {
+		log.Printf("job %d (%s) dead-lettered after %d attempt(s): %v", job.ID, job.Type, job.Attempts, jobErr)
+		return Repos.Jobs.DeadLetter(ctx, job.ID, jobErr.Error())
+	}
+
+	return Repos.Jobs.Reschedule(ctx, job.ID, jobErr.Error(), retryDelay(job.Attempts))
+}
+
+// retryDelay returns how long to wait before retrying a job that failed its
+// attempts-th attempt
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, retryMaxDelay)
+}
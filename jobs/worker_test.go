@@ -0,0 +1,263 @@
+package jobs
+
+import (
+	"ai-catalog/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// useMemory backs the queue with an empty in-memory store for the test
+func useMemory(t *testing.T) {
+	t.Helper()
+	previous := Repos
+	SetRepositories(repository.NewMemory().Repositories())
+	t.Cleanup(func() { Repos = previous })
+}
+
+// enqueue queues a job of jobType that may be tried maxAttempts times
+func enqueue(t *testing.T, jobType string, maxAttempts int) *Job {
+	t.Helper()
+	job, err := Repos.Jobs.Enqueue(context.Background(), repository.NewJob{
+		Type: jobType, Payload: json.RawMessage(`{}`), MaxAttempts: maxAttempts,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+// get returns the stored state of a job
+func get(t *testing.T, id int) *Job {
+	t.Helper()
+	job, err := Get(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+// claimAndRun claims the next job and runs one attempt of it, failing the test
+// when no job is due
+func claimAndRun(t *testing.T, w *Worker) *Job {
+	t.Helper()
+	ctx := context.Background()
+	job, err := w.claim(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job == nil {
+		t.Fatal("no job was claimed")
+	}
+	w.run(ctx, job)
+	return get(t, job.ID)
+}
+
+func TestConcurrentClaimsNeverShareAJob(t *testing.T) {
+	useMemory(t)
+	w := NewWorker()
+	w.Handle("work", func(ctx context.Context, payload json.RawMessage) (interface{}, error) { return nil, nil })
+	enqueue(t, "other", DefaultMaxAttempts)
+	for i := 0; i < 50; i++ {
+		enqueue(t, "work", DefaultMaxAttempts)
+	}
+
+	var mu sync.Mutex
+	claimed := map[int]int{}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				job, err := w.claim(context.Background())
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if job == nil {
+					return
+				}
+				mu.Lock()
+				claimed[job.ID]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(claimed) != 50 {
+		t.Errorf("claimed %d different jobs, want the 50 jobs the worker handles", len(claimed))
+	}
+	for id, count := range claimed {
+		if count != 1 {
+			t.Errorf("job %d was claimed %d times", id, count)
+		}
+	}
+}
+
+func TestCompletedJobsKeepTheirResult(t *testing.T) {
+	useMemory(t)
+	w := NewWorker()
+	w.Handle("work", func(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+		return map[string]string{"text": "done"}, nil
+	})
+	enqueue(t, "work", DefaultMaxAttempts)
+
+	job := claimAndRun(t, w)
+	if job.Status != StatusCompleted || string(job.Result) != `{"text":"done"}` || job.CompletedAt == nil {
+		t.Errorf("job = %+v, want it completed with its result", job)
+	}
+}
+
+func TestFailedAttemptsAreRetriedWithBackoff(t *testing.T) {
+	useMemory(t)
+	w := NewWorker()
+	w.Handle("work", func(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+		return nil, errors.New("provider unavailable")
+	})
+	enqueue(t, "work", DefaultMaxAttempts)
+
+	before := time.Now()
+	job := claimAndRun(t, w)
+	if job.Status != StatusPending || job.Attempts != 1 || job.Error == nil || *job.Error != "provider unavailable" {
+		t.Fatalf("job = %+v, want it pending again with the error", job)
+	}
+	if wait := job.RunAt.Sub(before); wait < retryBaseDelay || wait > retryBaseDelay+time.Second {
+		t.Errorf("retry scheduled in %s, want %s", wait, retryBaseDelay)
+	}
+	if next, err := w.claim(context.Background()); err != nil || next != nil {
+		t.Errorf("claimed %+v (%v) before the retry was due", next, err)
+	}
+
+	for attempts, want := range map[int]time.Duration{
+		1:  10 * time.Second,
+		2:  20 * time.Second,
+		3:  40 * time.Second,
+		6:  320 * time.Second,
+		7:  retryMaxDelay,
+		50: retryMaxDelay,
+	} {
+		if got := retryDelay(attempts); got != want {
+			t.Errorf("retryDelay(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
+
+func TestJobsAreDeadLetteredAfterTheirLastAttempt(t *testing.T) {
+	useMemory(t)
+	w := NewWorker()
+	w.Handle("work", func(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+		return nil, errors.New("provider unavailable")
+	})
+	enqueue(t, "work", 1)
+
+	job := claimAndRun(t, w)
+	if job.Status != StatusDead || job.Error == nil || *job.Error != "provider unavailable" {
+		t.Fatalf("job = %+v, want it dead-lettered with the error", job)
+	}
+
+	retried, err := Retry(context.Background(), job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retried.Status != StatusPending || retried.Attempts != 0 {
+		t.Errorf("retried job = %+v, want it pending with fresh attempts", retried)
+	}
+	if _, err := Retry(context.Background(), job.ID); err != repository.ErrJobNotDead {
+		t.Errorf("retrying a pending job: err = %v, want ErrJobNotDead", err)
+	}
+}
+
+func TestPermanentErrorsAreDeadLetteredAtOnce(t *testing.T) {
+	useMemory(t)
+	w := NewWorker()
+	w.Handle("work", func(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+		return nil, Permanent(errors.New("product not found"))
+	})
+	enqueue(t, "work", DefaultMaxAttempts)
+
+	job := claimAndRun(t, w)
+	if job.Status != StatusDead || job.Attempts != 1 || job.Error == nil || *job.Error != "product not found" {
+		t.Errorf("job = %+v, want it dead-lettered after its first attempt", job)
+	}
+}
+
+func TestPanicsFailTheAttempt(t *testing.T) {
+	useMemory(t)
+	w := NewWorker()
+	w.Handle("work", func(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+		panic("nil map")
+	})
+	enqueue(t, "work", DefaultMaxAttempts)
+
+	job := claimAndRun(t, w)
+	if job.Status != StatusPending || job.Error == nil || !strings.Contains(*job.Error, "job panicked: nil map") {
+		t.Errorf("job = %+v, want the panic recorded as a failed attempt", job)
+	}
+}
+
+func TestAbandonedJobsAreClaimedAgainUntilTheirLastAttempt(t *testing.T) {
+	useMemory(t)
+	w := NewWorker()
+	w.Timeout = 5 * time.Millisecond
+	w.Handle("work", func(ctx context.Context, payload json.RawMessage) (interface{}, error) { return nil, nil })
+	queued := enqueue(t, "work", 2)
+	ctx := context.Background()
+
+	// The worker that claims the job never finishes it
+	first, err := w.claim(ctx)
+	if err != nil || first == nil {
+		t.Fatalf("claim = %+v, %v", first, err)
+	}
+	if next, err := w.claim(ctx); err != nil || next != nil {
+		t.Fatalf("claimed %+v (%v) while its attempt was still running", next, err)
+	}
+
+	time.Sleep(3 * w.Timeout)
+	second, err := w.claim(ctx)
+	if err != nil || second == nil || second.ID != queued.ID || second.Attempts != 2 {
+		t.Fatalf("claim after the attempt went stale = %+v, %v, want the job on its second attempt", second, err)
+	}
+
+	// A job that keeps hanging is dead-lettered rather than retried forever
+	time.Sleep(3 * w.Timeout)
+	if next, err := w.claim(ctx); err != nil || next != nil {
+		t.Fatalf("claimed %+v (%v) after its last attempt", next, err)
+	}
+	job := get(t, queued.ID)
+	if job.Status != StatusDead || job.Error == nil || *job.Error != repository.ErrJobAbandoned.Error() {
+		t.Errorf("job = %+v, want it dead-lettered as abandoned", job)
+	}
+}
+
+func TestStartFinishesRunningJobsOnShutdown(t *testing.T) {
+	useMemory(t)
+	w := NewWorker()
+	w.Concurrency = 1
+	w.PollInterval = time.Millisecond
+	started := make(chan struct{})
+	w.Handle("work", func(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+		close(started)
+		time.Sleep(20 * time.Millisecond)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return "done", nil
+	})
+	queued := enqueue(t, "work", DefaultMaxAttempts)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wait := w.Start(ctx)
+	<-started
+	cancel()
+	wait()
+
+	if job := get(t, queued.ID); job.Status != StatusCompleted {
+		t.Errorf("job = %+v, want the running job finished on shutdown", job)
+	}
+}
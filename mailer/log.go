@@ -0,0 +1,38 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// LogMailer writes messages to files in Dir instead of sending them.
+// When Dir is empty messages are written to the standard logger.
+type LogMailer struct {
+	Dir  string
+	From string
+
+	sequence uint64
+}
+
+// Send records the message as a .eml file or a log entry
+func (m *LogMailer) Send(msg Message) error {
+	content := buildMessage(m.From, msg)
+	if m.Dir == "" {
+		log.Printf("📧 Email to %s:\n%s", msg.To, content)
+		return nil
+	}
+
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create mail directory: %v", err)
+	}
+
+	name := fmt.Sprintf("%d-%d.eml", time.Now().UnixNano(), atomic.AddUint64(&m.sequence, 1))
+	if err := os.WriteFile(filepath.Join(m.Dir, name), content, 0o644); err != nil {
+		return fmt.Errorf("failed to write email: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+)
+
+// Message represents an email to be delivered
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers email messages
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// FromEnv returns the mailer configured by the MAILER environment variable.
+// MAILER=smtp uses the SMTP_* settings and MAILER=log uses LogMailer, which
+// writes messages to MAIL_DIR (or the log). Since logged messages carry
+// password reset and verification tokens, MAILER may only be left unset with
+// APP_ENV=development, which also uses LogMailer.
+func FromEnv() (Mailer, error) {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "Fintks Store <no-reply@fintks.com>"
+	}
+
+	switch os.Getenv("MAILER") {
+	case "smtp":
+		host := os.Getenv("SMTP_HOST")
+		if host == "" {
+			return nil, errors.New("SMTP_HOST is required with MAILER=smtp")
+		}
+		port, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
+		if err != nil {
+			port = 587
+		}
+		return &SMTPMailer{
+			Host:     host,
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		}, nil
+	case "log":
+	case "":
+		if os.Getenv("APP_ENV") != "development" {
+			return nil, errors.New("MAILER is required (smtp, or log to write emails to MAIL_DIR; set APP_ENV=development to log emails by default)")
+		}
+		log.Println("⚠️ MAILER is not set, writing emails to the log")
+	default:
+		return nil, fmt.Errorf("unknown MAILER %q, use smtp or log", os.Getenv("MAILER"))
+	}
+
+	return &LogMailer{Dir: os.Getenv("MAIL_DIR"), From: from}, nil
+}
@@ -0,0 +1,373 @@
+// Package migrations applies the numbered database migrations in sql/ and loads
+// the optional demo data in seed.sql. Applied versions are recorded in the
+// schema_migrations table, and a Postgres advisory lock serializes migrations
+// so replicas starting together do not race.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed sql/*.sql
+var migrationFiles embed.FS
+
+//go:embed seed.sql
+var seedSQL string
+
+// advisoryLockKey identifies the advisory lock held while migrating or seeding
+const advisoryLockKey = 7263010019
+
+// ErrAlreadySeeded is returned by Seed when the catalog already has data
+var ErrAlreadySeeded = errors.New("database already has catalog data, not seeding")
+
+// Migration is a numbered schema change. Its files are sql/<version>_<name>.up.sql
+// and sql/<version>_<name>.down.sql.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// MigrationStatus reports whether a migration has been applied. Migrations that
+// are recorded in the database but have no files are listed with an empty Name.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// All returns the embedded migrations ordered by version
+func All() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		base, direction, ok := cutDirection(entry.Name())
+		if !ok {
+			return nil, fmt.Errorf("migration %s must end in .up.sql or .down.sql", entry.Name())
+		}
+		prefix, name, _ := strings.Cut(base, "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s must start with a positive version number", entry.Name())
+		}
+
+		contents, err := migrationFiles.ReadFile(path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration := byVersion[version]
+		if migration == nil {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, migration.Name, name)
+		}
+		if direction == "up" {
+			migration.Up = string(contents)
+		} else {
+			migration.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %s needs both an up and a down file", migration)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// cutDirection splits "0001_name.up.sql" into "0001_name" and "up"
+func cutDirection(file string) (string, string, bool) {
+	for _, direction := range []string{"up", "down"} {
+		if base, ok := strings.CutSuffix(file, "."+direction+".sql"); ok {
+			return base, direction, true
+		}
+	}
+	return "", "", false
+}
+
+// Latest returns the version of the newest migration, or 0 if there are none
+func Latest() (int, error) {
+	migrations, err := All()
+	if err != nil || len(migrations) == 0 {
+		return 0, err
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// Up applies every pending migration and returns the ones it applied
+func Up(ctx context.Context, db *sql.DB) ([]Migration, error) {
+	latest, err := Latest()
+	if err != nil {
+		return nil, err
+	}
+	return To(ctx, db, latest)
+}
+
+// Down rolls back the most recently applied steps migrations and returns them
+// in the order they were rolled back
+func Down(ctx context.Context, db *sql.DB, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("steps must be at least 1")
+	}
+
+	var run []Migration
+	err := withLock(ctx, db, func(conn *sql.Conn) error {
+		migrations, applied, err := load(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		versions := appliedVersions(applied)
+		target := 0
+		if steps < len(versions) {
+			target = versions[len(versions)-1-steps]
+		}
+		run, err = migrate(ctx, conn, migrations, applied, target)
+		return err
+	})
+	return run, err
+}
+
+// To applies or rolls back migrations until version is the newest one applied,
+// and returns the migrations it ran. Version 0 rolls back every migration.
+func To(ctx context.Context, db *sql.DB, version int) ([]Migration, error) {
+	var run []Migration
+	err := withLock(ctx, db, func(conn *sql.Conn) error {
+		migrations, applied, err := load(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if version != 0 && !hasVersion(migrations, version) {
+			return fmt.Errorf("there is no migration with version %d", version)
+		}
+		run, err = migrate(ctx, conn, migrations, applied, version)
+		return err
+	})
+	return run, err
+}
+
+// Status returns every known migration and when it was applied
+func Status(ctx context.Context, db *sql.DB) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := withLock(ctx, db, func(conn *sql.Conn) error {
+		migrations, applied, err := load(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range migrations {
+			status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+			if appliedAt, ok := applied[migration.Version]; ok {
+				status.AppliedAt = &appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+		for version, appliedAt := range applied {
+			if !hasVersion(migrations, version) {
+				appliedAt := appliedAt
+				statuses = append(statuses, MigrationStatus{Version: version, AppliedAt: &appliedAt})
+			}
+		}
+		sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+		return nil
+	})
+	return statuses, err
+}
+
+// Seed loads the demo data into a database with an empty catalog. The schema
+// must be fully migrated first.
+func Seed(ctx context.Context, db *sql.DB) error {
+	return withLock(ctx, db, func(conn *sql.Conn) error {
+		migrations, applied, err := load(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range migrations {
+			if _, ok := applied[migration.Version]; !ok {
+				return fmt.Errorf("migration %s is pending, run migrate up before seeding", migration)
+			}
+		}
+
+		var hasData bool
+		err = conn.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM categories) OR EXISTS(SELECT 1 FROM products)").Scan(&hasData)
+		if err != nil {
+			return err
+		}
+		if hasData {
+			return ErrAlreadySeeded
+		}
+
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+		if _, err := tx.ExecContext(ctx, seedSQL); err != nil {
+			return fmt.Errorf("failed to load seed data: %v", err)
+		}
+		return tx.Commit()
+	})
+}
+
+// withLock runs fn on a single connection holding the migration advisory lock.
+// Session-level advisory locks belong to a connection, so the lock and the
+// work must share one.
+func withLock(ctx context.Context, db *sql.DB, fn func(conn *sql.Conn) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", advisoryLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %v", err)
+	}
+	// Unlock even if ctx was cancelled so the pooled connection does not keep the lock
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", advisoryLockKey)
+
+	_, err = conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(255) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %v", err)
+	}
+
+	return fn(conn)
+}
+
+// load returns the embedded migrations and the versions applied to the database
+func load(ctx context.Context, conn *sql.Conn) ([]Migration, map[int]time.Time, error) {
+	migrations, err := All()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return migrations, applied, rows.Err()
+}
+
+// migrate applies the pending migrations up to target and rolls back the
+// applied ones above it. Each migration runs in its own transaction together
+// with its schema_migrations change.
+func migrate(ctx context.Context, conn *sql.Conn, migrations []Migration, applied map[int]time.Time, target int) ([]Migration, error) {
+	for version := range applied {
+		if version > target && !hasVersion(migrations, version) {
+			return nil, fmt.Errorf("migration %d is applied but its files are missing, it cannot be rolled back", version)
+		}
+	}
+
+	var run []Migration
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migration := migrations[i]
+		if _, ok := applied[migration.Version]; !ok || migration.Version <= target {
+			continue
+		}
+		if err := runMigration(ctx, conn, migration, false); err != nil {
+			return run, err
+		}
+		run = append(run, migration)
+	}
+
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok || migration.Version > target {
+			continue
+		}
+		if err := runMigration(ctx, conn, migration, true); err != nil {
+			return run, err
+		}
+		run = append(run, migration)
+	}
+	return run, nil
+}
+
+// runMigration applies (up) or rolls back a single migration
+func runMigration(ctx context.Context, conn *sql.Conn, migration Migration, up bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	script := migration.Down
+	record := "DELETE FROM schema_migrations WHERE version = $1"
+	args := []interface{}{migration.Version}
+	if up {
+		script = migration.Up
+		record = "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)"
+		args = append(args, migration.Name)
+	}
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		direction := "roll back"
+		if up {
+			direction = "apply"
+		}
+		return fmt.Errorf("failed to %s migration %s: %v", direction, migration, err)
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// appliedVersions returns the applied versions in ascending order
+func appliedVersions(applied map[int]time.Time) []int {
+	versions := make([]int, 0, len(applied))
+	for version := range applied {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+func hasVersion(migrations []Migration, version int) bool {
+	for _, migration := range migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
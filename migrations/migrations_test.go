@@ -0,0 +1,65 @@
+package migrations
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedMigrationsAreNumberedInOrder(t *testing.T) {
+	migrations, err := All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no migrations are embedded")
+	}
+
+	for i, migration := range migrations {
+		// Versions start at 1 with no gaps, so a missing or repeated number shows up here
+		if migration.Version != i+1 {
+			t.Errorf("migration %s has version %d, want %d", migration, migration.Version, i+1)
+		}
+		if migration.Name == "" {
+			t.Errorf("migration %d has no name", migration.Version)
+		}
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("migration %s has an empty up or down file", migration)
+		}
+	}
+
+	latest, err := Latest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != len(migrations) {
+		t.Errorf("Latest() = %d, want %d", latest, len(migrations))
+	}
+}
+
+func TestEmbeddedMigrationFilesAreNamedByVersion(t *testing.T) {
+	migrations, err := All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := map[string]bool{}
+	for _, migration := range migrations {
+		names[migration.String()+".up.sql"] = true
+		names[migration.String()+".down.sql"] = true
+	}
+
+	// Every file must belong to a migration under its zero-padded name, so files
+	// sort in the order they are applied
+	entries, err := fs.ReadDir(migrationFiles, "sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(names) {
+		t.Errorf("%d migration files, want %d", len(entries), len(names))
+	}
+	for _, entry := range entries {
+		if !names[entry.Name()] {
+			t.Errorf("migration file %s does not match its version's name", entry.Name())
+		}
+	}
+}
@@ -0,0 +1,23 @@
+-- Drop the baseline schema, dependent tables first
+DROP TABLE IF EXISTS product_likes;
+DROP TABLE IF EXISTS review_likes;
+DROP TABLE IF EXISTS reviews;
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS orders;
+DROP TABLE IF EXISTS wishlist;
+DROP TABLE IF EXISTS cart;
+DROP TABLE IF EXISTS category_translations;
+DROP TABLE IF EXISTS product_translations;
+DROP TABLE IF EXISTS product_images;
+DROP TABLE IF EXISTS products;
+DROP FUNCTION IF EXISTS set_updated_at();
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS translation_memory;
+DROP TABLE IF EXISTS jobs;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS user_identities;
+DROP TABLE IF EXISTS two_factor_recovery_codes;
+DROP TABLE IF EXISTS login_attempts;
+DROP TABLE IF EXISTS user_tokens;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_order_items_product_id;
+DROP INDEX IF EXISTS idx_wishlist_user_created_at_id;
+DROP INDEX IF EXISTS idx_reviews_user_created_at_id;
+DROP INDEX IF EXISTS idx_reviews_product_created_at_id;
+DROP INDEX IF EXISTS idx_orders_user_created_at_id;
+DROP INDEX IF EXISTS idx_products_price_id;
+DROP INDEX IF EXISTS idx_products_created_at_id;
+
+ALTER TABLE wishlist ALTER COLUMN created_at DROP NOT NULL;
+ALTER TABLE reviews ALTER COLUMN created_at DROP NOT NULL;
+ALTER TABLE orders ALTER COLUMN created_at DROP NOT NULL;
+ALTER TABLE products ALTER COLUMN created_at DROP NOT NULL;
@@ -0,0 +1,21 @@
+-- Cursor pagination orders listings by created_at and id, so the sort columns
+-- must not be NULL and need indexes matching those orders
+
+UPDATE products SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+ALTER TABLE products ALTER COLUMN created_at SET NOT NULL;
+UPDATE orders SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+ALTER TABLE orders ALTER COLUMN created_at SET NOT NULL;
+UPDATE reviews SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+ALTER TABLE reviews ALTER COLUMN created_at SET NOT NULL;
+UPDATE wishlist SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+ALTER TABLE wishlist ALTER COLUMN created_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_products_created_at_id ON products(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_products_price_id ON products(price, id);
+CREATE INDEX IF NOT EXISTS idx_orders_user_created_at_id ON orders(user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_reviews_product_created_at_id ON reviews(product_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_reviews_user_created_at_id ON reviews(user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_wishlist_user_created_at_id ON wishlist(user_id, created_at, id);
+
+-- Rating and popularity sorts aggregate reviews and order items per product
+CREATE INDEX IF NOT EXISTS idx_order_items_product_id ON order_items(product_id);
@@ -0,0 +1,4 @@
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_parent_not_self;
+
+DROP INDEX IF EXISTS idx_products_category_id;
+DROP INDEX IF EXISTS idx_categories_parent_id;
@@ -0,0 +1,8 @@
+-- The category tree is walked through parent_id, and descendant product
+-- filters look products up by category
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON products(category_id);
+
+-- A category cannot be its own parent; longer cycles are rejected by the
+-- application when categories are moved
+ALTER TABLE categories ADD CONSTRAINT categories_parent_not_self CHECK (parent_id <> id);
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_products_search_vector;
+
+DROP TRIGGER IF EXISTS product_translations_refresh_search_vector ON product_translations;
+DROP FUNCTION IF EXISTS product_translations_refresh_search_vector();
+DROP TRIGGER IF EXISTS products_set_search_vector ON products;
+DROP FUNCTION IF EXISTS products_set_search_vector();
+DROP FUNCTION IF EXISTS product_search_vector(products);
+
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS product_search_query(TEXT);
+DROP FUNCTION IF EXISTS search_document("char", TEXT);
+DROP FUNCTION IF EXISTS normalize_arabic(TEXT);
@@ -0,0 +1,75 @@
+-- Full-text product search. Text is normalized before it is indexed or queried
+-- so Arabic spelling variants match: diacritics and tatweel are dropped, alef
+-- with hamza or madda becomes a bare alef, hamza on waw or yaa becomes the bare
+-- letter, taa marbuta becomes haa and alef maksura becomes yaa.
+CREATE OR REPLACE FUNCTION normalize_arabic(input TEXT) RETURNS TEXT AS $$
+    SELECT translate(
+        regexp_replace(coalesce(input, ''), '[\u064B-\u0652\u0670\u0640]', '', 'g'),
+        'أإآٱؤئةى',
+        'ااااويهي'
+    );
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+-- Catalog text may be in either language, so it is stemmed as both English and
+-- Arabic; each stemmer leaves the other language's words as they are
+CREATE OR REPLACE FUNCTION search_document(weight "char", body TEXT) RETURNS tsvector AS $$
+    SELECT setweight(
+        to_tsvector('english', normalize_arabic(body)) || to_tsvector('arabic', normalize_arabic(body)),
+        weight
+    );
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+-- product_search_query parses what a customer typed. Quoted phrases, OR and
+-- -word work as in web search engines.
+CREATE OR REPLACE FUNCTION product_search_query(input TEXT) RETURNS tsquery AS $$
+    SELECT websearch_to_tsquery('english', normalize_arabic(input))
+        || websearch_to_tsquery('arabic', normalize_arabic(input));
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+-- The name and SKU weigh most, then the short description, then the
+-- description, in the default locale and every translation
+CREATE OR REPLACE FUNCTION product_search_vector(product products) RETURNS tsvector AS $$
+    SELECT search_document('A', product.name)
+        || setweight(to_tsvector('simple', coalesce(product.sku, '')), 'A')
+        || search_document('B', product.short_description)
+        || search_document('C', product.description)
+        || (
+            SELECT search_document('A', string_agg(t.name, ' '))
+                || search_document('B', string_agg(t.short_description, ' '))
+                || search_document('C', string_agg(t.description, ' '))
+            FROM product_translations t
+            WHERE t.product_id = product.id
+        );
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION products_set_search_vector() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector = product_search_vector(NEW);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS products_set_search_vector ON products;
+CREATE TRIGGER products_set_search_vector
+    BEFORE INSERT OR UPDATE OF name, description, short_description, sku ON products
+    FOR EACH ROW EXECUTE FUNCTION products_set_search_vector();
+
+CREATE OR REPLACE FUNCTION product_translations_refresh_search_vector() RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE products p SET search_vector = product_search_vector(p)
+    WHERE p.id = CASE WHEN TG_OP = 'DELETE' THEN OLD.product_id ELSE NEW.product_id END;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS product_translations_refresh_search_vector ON product_translations;
+CREATE TRIGGER product_translations_refresh_search_vector
+    AFTER INSERT OR UPDATE OR DELETE ON product_translations
+    FOR EACH ROW EXECUTE FUNCTION product_translations_refresh_search_vector();
+
+UPDATE products p SET search_vector = product_search_vector(p);
+ALTER TABLE products ALTER COLUMN search_vector SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN (search_vector);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS search_queries;
+
+DROP INDEX IF EXISTS idx_category_translations_name_trgm;
+DROP INDEX IF EXISTS idx_categories_name_trgm;
+DROP INDEX IF EXISTS idx_product_translations_name_trgm;
+DROP INDEX IF EXISTS idx_products_name_trgm;
+
+DROP EXTENSION IF EXISTS pg_trgm;
@@ -0,0 +1,29 @@
+-- Trigram indexes let suggestions match names by prefix or by similarity, so
+-- a mistyped word still finds what the customer meant
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_products_name_trgm
+    ON products USING GIN (normalize_arabic(name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_product_translations_name_trgm
+    ON product_translations USING GIN (normalize_arabic(name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_categories_name_trgm
+    ON categories USING GIN (normalize_arabic(name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_category_translations_name_trgm
+    ON category_translations USING GIN (normalize_arabic(name) gin_trgm_ops);
+
+-- Log of the searches customers make. Popular searches are suggested to other
+-- customers, and popular and zero-result searches are reported to merchandisers.
+-- normalized is the lower-case query with Arabic spelling variants unified, so
+-- spellings of the same search are counted together.
+CREATE TABLE IF NOT EXISTS search_queries (
+    id BIGSERIAL PRIMARY KEY,
+    query VARCHAR(200) NOT NULL,
+    normalized VARCHAR(200) NOT NULL,
+    result_count INTEGER NOT NULL,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    locale VARCHAR(10),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_queries_created_at ON search_queries(created_at);
+CREATE INDEX IF NOT EXISTS idx_search_queries_normalized_trgm ON search_queries USING GIN (normalized gin_trgm_ops);
@@ -0,0 +1,11 @@
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_name;
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_id;
+
+-- Variant items cannot be told apart once variant_id is gone
+DELETE FROM cart WHERE variant_id IS NOT NULL;
+DROP INDEX IF EXISTS idx_cart_user_product_variant;
+ALTER TABLE cart DROP COLUMN IF EXISTS variant_id;
+ALTER TABLE cart ADD CONSTRAINT cart_user_id_product_id_key UNIQUE (user_id, product_id);
+
+DROP TABLE IF EXISTS product_variants;
+DROP FUNCTION IF EXISTS product_variants_sync_stock();
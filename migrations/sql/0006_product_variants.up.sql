@@ -0,0 +1,59 @@
+-- Variants are the purchasable versions of a product, such as each size and
+-- color of a shoe, with their own SKU, price, stock and images. options lists
+-- the variant's value of each option type in order, as
+-- [{"name": "Size", "value": "42"}, {"name": "Color", "value": "Red"}].
+CREATE TABLE IF NOT EXISTS product_variants (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    sku VARCHAR(100) NOT NULL UNIQUE,
+    price DECIMAL(10,2) NOT NULL,
+    stock_quantity INTEGER NOT NULL DEFAULT 0,
+    options JSONB NOT NULL,
+    images TEXT[] NOT NULL DEFAULT '{}',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (product_id, options)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_variants_product_id ON product_variants(product_id);
+
+DROP TRIGGER IF EXISTS product_variants_set_updated_at ON product_variants;
+CREATE TRIGGER product_variants_set_updated_at
+    BEFORE UPDATE ON product_variants
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- The stock of a product with variants is the total stock of its active
+-- variants, so stock filters and facets keep working
+CREATE OR REPLACE FUNCTION product_variants_sync_stock() RETURNS TRIGGER AS $$
+DECLARE
+    changed_product_id INTEGER;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        changed_product_id = OLD.product_id;
+    ELSE
+        changed_product_id = NEW.product_id;
+    END IF;
+
+    UPDATE products SET stock_quantity = (
+        SELECT COALESCE(SUM(v.stock_quantity), 0) FROM product_variants v
+        WHERE v.product_id = changed_product_id AND v.is_active
+    )
+    WHERE id = changed_product_id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS product_variants_sync_stock ON product_variants;
+CREATE TRIGGER product_variants_sync_stock
+    AFTER INSERT OR UPDATE OF stock_quantity, is_active OR DELETE ON product_variants
+    FOR EACH ROW EXECUTE FUNCTION product_variants_sync_stock();
+
+-- A cart holds each variant of a product as its own item
+ALTER TABLE cart ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants(id) ON DELETE CASCADE;
+ALTER TABLE cart DROP CONSTRAINT IF EXISTS cart_user_id_product_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cart_user_product_variant ON cart(user_id, product_id, (COALESCE(variant_id, 0)));
+
+-- Order items keep the variant's name as it was ordered, like the product name
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_id INTEGER REFERENCES product_variants(id) ON DELETE SET NULL;
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_name VARCHAR(255);
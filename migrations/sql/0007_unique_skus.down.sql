@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS product_variants_check_sku ON product_variants;
+DROP FUNCTION IF EXISTS product_variants_check_sku();
+DROP TRIGGER IF EXISTS products_check_sku ON products;
+DROP FUNCTION IF EXISTS products_check_sku();
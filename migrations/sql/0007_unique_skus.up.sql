@@ -0,0 +1,38 @@
+-- A SKU identifies one thing on sale, so a variant cannot reuse the SKU of a
+-- product or the other way round. The unique constraints only cover their own
+-- table; these triggers check the other one. The advisory lock on the SKU
+-- makes a concurrent insert of the same SKU into the other table wait until
+-- this one commits, so it sees this row.
+CREATE OR REPLACE FUNCTION products_check_sku() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.sku IS NOT NULL THEN
+        PERFORM pg_advisory_xact_lock(hashtext(NEW.sku));
+        IF EXISTS (SELECT 1 FROM product_variants WHERE sku = NEW.sku) THEN
+            RAISE EXCEPTION 'SKU % is used by a product variant', NEW.sku
+                USING ERRCODE = 'unique_violation', CONSTRAINT = 'products_sku_key';
+        END IF;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS products_check_sku ON products;
+CREATE TRIGGER products_check_sku
+    BEFORE INSERT OR UPDATE OF sku ON products
+    FOR EACH ROW EXECUTE FUNCTION products_check_sku();
+
+CREATE OR REPLACE FUNCTION product_variants_check_sku() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock(hashtext(NEW.sku));
+    IF EXISTS (SELECT 1 FROM products WHERE sku = NEW.sku) THEN
+        RAISE EXCEPTION 'SKU % is used by a product', NEW.sku
+            USING ERRCODE = 'unique_violation', CONSTRAINT = 'product_variants_sku_key';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS product_variants_check_sku ON product_variants;
+CREATE TRIGGER product_variants_check_sku
+    BEFORE INSERT OR UPDATE OF sku ON product_variants
+    FOR EACH ROW EXECUTE FUNCTION product_variants_check_sku();
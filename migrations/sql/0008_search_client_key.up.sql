@@ -0,0 +1,3 @@
+-- Suggestions need a query to be searched by several customers, so anonymous
+-- searches record a hash of the client's address to tell them apart
+ALTER TABLE search_queries ADD COLUMN IF NOT EXISTS client_key VARCHAR(64);
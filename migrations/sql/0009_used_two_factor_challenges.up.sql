@@ -0,0 +1,8 @@
+-- IDs of two-factor challenge tokens that completed a login, so a challenge
+-- cannot be used again before it expires. Rows are pruned once expired.
+CREATE TABLE IF NOT EXISTS used_two_factor_challenges (
+    id VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_used_two_factor_challenges_expires_at ON used_two_factor_challenges(expires_at);
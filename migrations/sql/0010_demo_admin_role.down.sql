@@ -0,0 +1,2 @@
+-- The role is left in place: demoting the account could leave the store
+-- without an admin.
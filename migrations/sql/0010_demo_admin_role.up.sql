@@ -0,0 +1,4 @@
+-- Give the demo admin account the admin role, as the old init.sql did on every
+-- start. Databases that adopted 0001 from init.sql are not seeded again, so
+-- without this they would be left with no admin.
+UPDATE users SET role = 'admin' WHERE email = 'admin@fintks.com';
@@ -0,0 +1,36 @@
+package model
+
+// ProductFacets counts the products matching a filter by category, price range,
+// average rating and stock, for the refinements a storefront offers next to
+// search results
+type ProductFacets struct {
+	Total       int                `json:"total"`
+	Categories  []*CategoryFacet   `json:"categories"`
+	PriceRanges []*PriceRangeFacet `json:"priceRanges"`
+	Ratings     []*RatingFacet     `json:"ratings"`
+	InStock     int                `json:"inStock"`
+	OutOfStock  int                `json:"outOfStock"`
+}
+
+// CategoryFacet is the number of matching products in a category. Category is
+// filled in by the caller.
+type CategoryFacet struct {
+	CategoryID int       `json:"categoryId"`
+	Category   *Category `json:"category"`
+	Count      int       `json:"count"`
+}
+
+// PriceRangeFacet is the number of matching products priced from Min up to but
+// not including Max. The highest range has no Max.
+type PriceRangeFacet struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max"`
+	Count int      `json:"count"`
+}
+
+// RatingFacet is the number of matching products with an average review rating
+// of at least MinRating
+type RatingFacet struct {
+	MinRating int `json:"minRating"`
+	Count     int `json:"count"`
+}
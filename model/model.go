@@ -0,0 +1,163 @@
+// Package model defines the domain entities shared by the repository and
+// graph packages
+package model
+
+import (
+	"time"
+)
+
+// User represents a user in the system
+type User struct {
+	ID                int       `json:"id"`
+	Email             string    `json:"email"`
+	FirstName         string    `json:"firstName"`
+	LastName          string    `json:"lastName"`
+	Phone             string    `json:"phone"`
+	Address           string    `json:"address"`
+	City              string    `json:"city"`
+	Country           string    `json:"country"`
+	Role              string    `json:"role"`
+	EmailVerified     bool      `json:"emailVerified"`
+	Status            string    `json:"status"`
+	TwoFactorEnabled  bool      `json:"twoFactorEnabled"`
+	TwoFactorRequired bool      `json:"twoFactorRequired"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// Category represents a product category
+type Category struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ImageURL    string    `json:"imageUrl"`
+	ParentID    *int      `json:"parentId"`
+	CreatedAt   time.Time `json:"createdAt"`
+	// Locale is the language of Name and Description
+	Locale string `json:"locale"`
+}
+
+// Product represents a product in the catalog
+type Product struct {
+	ID               int       `json:"id"`
+	Name             string    `json:"name"`
+	Price            float64   `json:"price"`
+	OriginalPrice    *float64  `json:"originalPrice"`
+	CategoryID       int       `json:"categoryId"`
+	Category         *Category `json:"category"`
+	Description      string    `json:"description"`
+	ShortDescription string    `json:"shortDescription"`
+	ImageURL         string    `json:"imageUrl"`
+	StockQuantity    int       `json:"stockQuantity"`
+	SKU              string    `json:"sku"`
+	Weight           *float64  `json:"weight"`
+	Dimensions       string    `json:"dimensions"`
+	IsActive         bool      `json:"isActive"`
+	IsFeatured       bool      `json:"isFeatured"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+	AverageRating    float64   `json:"averageRating"`
+	ReviewCount      int       `json:"reviewCount"`
+	IsInWishlist     bool      `json:"isInWishlist"`
+	IsLiked          bool      `json:"isLiked"`
+	// Locale is the language of Name, Description and ShortDescription
+	Locale string `json:"locale"`
+}
+
+// SelectedOption is a variant's value of one of its product's option types
+type SelectedOption struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ProductVariant is a purchasable version of a product, such as one size and
+// color of a shoe, with its own SKU, price and stock
+type ProductVariant struct {
+	ID            int               `json:"id"`
+	ProductID     int               `json:"productId"`
+	SKU           string            `json:"sku"`
+	Price         float64           `json:"price"`
+	StockQuantity int               `json:"stockQuantity"`
+	Options       []*SelectedOption `json:"options"`
+	Images        []string          `json:"images"`
+	IsActive      bool              `json:"isActive"`
+	CreatedAt     time.Time         `json:"createdAt"`
+	UpdatedAt     time.Time         `json:"updatedAt"`
+}
+
+// CartItem represents an item in the shopping cart. VariantID is set for
+// products sold in variants. Available is false once the product or variant
+// has been deactivated; such items cannot be ordered.
+type CartItem struct {
+	ID        int             `json:"id"`
+	UserID    int             `json:"userId"`
+	ProductID int             `json:"productId"`
+	Product   *Product        `json:"product"`
+	VariantID *int            `json:"variantId"`
+	Variant   *ProductVariant `json:"variant"`
+	Quantity  int             `json:"quantity"`
+	Available bool            `json:"available"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// WishlistItem represents an item in the wishlist
+type WishlistItem struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"userId"`
+	ProductID int       `json:"productId"`
+	Product   *Product  `json:"product"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Order represents a customer order
+type Order struct {
+	ID              int          `json:"id"`
+	UserID          int          `json:"userId"`
+	User            *User        `json:"user"`
+	OrderNumber     string       `json:"orderNumber"`
+	Status          string       `json:"status"`
+	TotalAmount     float64      `json:"totalAmount"`
+	ShippingAddress string       `json:"shippingAddress"`
+	ShippingCity    string       `json:"shippingCity"`
+	ShippingCountry string       `json:"shippingCountry"`
+	ShippingPhone   string       `json:"shippingPhone"`
+	PaymentMethod   string       `json:"paymentMethod"`
+	PaymentStatus   string       `json:"paymentStatus"`
+	Notes           string       `json:"notes"`
+	CreatedAt       time.Time    `json:"createdAt"`
+	UpdatedAt       time.Time    `json:"updatedAt"`
+	Items           []*OrderItem `json:"items"`
+}
+
+// OrderItem represents an item in an order. ProductPrice is the unit price
+// paid, the variant's price for variants, and VariantName the variant's name
+// when it was ordered.
+type OrderItem struct {
+	ID           int       `json:"id"`
+	OrderID      int       `json:"orderId"`
+	ProductID    int       `json:"productId"`
+	ProductName  string    `json:"productName"`
+	ProductPrice float64   `json:"productPrice"`
+	VariantID    *int      `json:"variantId"`
+	VariantName  string    `json:"variantName"`
+	Quantity     int       `json:"quantity"`
+	TotalPrice   float64   `json:"totalPrice"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// Review represents a product review
+type Review struct {
+	ID                 int       `json:"id"`
+	UserID             int       `json:"userId"`
+	User               *User     `json:"user"`
+	ProductID          int       `json:"productId"`
+	Rating             int       `json:"rating"`
+	Title              string    `json:"title"`
+	Comment            string    `json:"comment"`
+	IsVerifiedPurchase bool      `json:"isVerifiedPurchase"`
+	CreatedAt          time.Time `json:"createdAt"`
+	UpdatedAt          time.Time `json:"updatedAt"`
+	LikeCount          int       `json:"likeCount"`
+	IsLiked            bool      `json:"isLiked"`
+}
@@ -0,0 +1,62 @@
+package model
+
+// PageInfo describes the window a connection returned, as in the Relay cursor
+// connections spec
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
+}
+
+// ProductEdge is a product in a ProductConnection with its cursor
+type ProductEdge struct {
+	Cursor string   `json:"cursor"`
+	Node   *Product `json:"node"`
+}
+
+// ProductConnection is a page of products
+type ProductConnection struct {
+	Edges      []*ProductEdge `json:"edges"`
+	PageInfo   *PageInfo      `json:"pageInfo"`
+	TotalCount int            `json:"totalCount"`
+}
+
+// OrderEdge is an order in an OrderConnection with its cursor
+type OrderEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *Order `json:"node"`
+}
+
+// OrderConnection is a page of orders
+type OrderConnection struct {
+	Edges      []*OrderEdge `json:"edges"`
+	PageInfo   *PageInfo    `json:"pageInfo"`
+	TotalCount int          `json:"totalCount"`
+}
+
+// ReviewEdge is a review in a ReviewConnection with its cursor
+type ReviewEdge struct {
+	Cursor string  `json:"cursor"`
+	Node   *Review `json:"node"`
+}
+
+// ReviewConnection is a page of reviews
+type ReviewConnection struct {
+	Edges      []*ReviewEdge `json:"edges"`
+	PageInfo   *PageInfo     `json:"pageInfo"`
+	TotalCount int           `json:"totalCount"`
+}
+
+// WishlistItemEdge is a wishlist item in a WishlistConnection with its cursor
+type WishlistItemEdge struct {
+	Cursor string        `json:"cursor"`
+	Node   *WishlistItem `json:"node"`
+}
+
+// WishlistConnection is a page of wishlist items
+type WishlistConnection struct {
+	Edges      []*WishlistItemEdge `json:"edges"`
+	PageInfo   *PageInfo           `json:"pageInfo"`
+	TotalCount int                 `json:"totalCount"`
+}
@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+// SearchStat is how often customers made a search, for the merchandising reports
+type SearchStat struct {
+	Query          string    `json:"query"`
+	Searches       int       `json:"searches"`
+	LastSearchedAt time.Time `json:"lastSearchedAt"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// TranslationMemoryEntry is a cached machine translation or a linguist's override.
+// Overrides apply whichever model is configured and are never replaced by
+// machine output.
+type TranslationMemoryEntry struct {
+	ID             int        `json:"id"`
+	SourceText     string     `json:"sourceText"`
+	SourceLang     string     `json:"sourceLang"`
+	TargetLang     string     `json:"targetLang"`
+	Model          string     `json:"model"`
+	TranslatedText string     `json:"translatedText"`
+	IsOverride     bool       `json:"isOverride"`
+	HitCount       int        `json:"hitCount"`
+	LastUsedAt     *time.Time `json:"lastUsedAt"`
+	UpdatedBy      *int       `json:"updatedBy"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
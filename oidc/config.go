@@ -0,0 +1,62 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// ConfigsFromEnv reads the providers listed in OIDC_PROVIDERS (comma separated
+// names). Each provider NAME is configured with:
+//
+//	OIDC_NAME_ISSUER         issuer URL used for discovery
+//	OIDC_NAME_CLIENT_ID      client ID registered with the provider
+//	OIDC_NAME_CLIENT_SECRET  client secret (optional for public clients)
+//	OIDC_NAME_REDIRECT_URL   callback URL (default APP_BASE_URL/auth/oidc/name/callback)
+//	OIDC_NAME_SCOPES         space separated scopes (default "openid email profile")
+func ConfigsFromEnv() ([]Config, error) {
+	var configs []Config
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+
+		config := Config{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}
+		if config.Issuer == "" || config.ClientID == "" {
+			return nil, fmt.Errorf("oidc %s: %sISSUER and %sCLIENT_ID are required", name, prefix, prefix)
+		}
+		if config.RedirectURL == "" {
+			baseURL := os.Getenv("APP_BASE_URL")
+			if baseURL == "" {
+				baseURL = "http://localhost:8080"
+			}
+			config.RedirectURL = strings.TrimRight(baseURL, "/") + "/auth/oidc/" + name + "/callback"
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+// StateSecretFromEnv returns OIDC_STATE_SECRET, or a random per-process secret when
+// it is unset. Set it when running several instances behind a load balancer.
+func StateSecretFromEnv() []byte {
+	if secret := os.Getenv("OIDC_STATE_SECRET"); secret != "" {
+		return []byte(secret)
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		log.Fatal("failed to generate OIDC state secret:", err)
+	}
+	return secret
+}
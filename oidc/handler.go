@@ -0,0 +1,256 @@
+package oidc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// stateTTL is how long a user has to complete a login at the provider
+const stateTTL = 10 * time.Minute
+
+// LoginFunc signs in the user behind a verified identity and returns the URL the
+// browser is redirected to afterwards. Only the messages of *LoginError errors
+// are shown to the user.
+type LoginFunc func(ctx context.Context, identity *Identity) (string, error)
+
+// LoginError is a login refusal the user can act on, such as a suspended
+// account. Its message is shown to the user; other errors from a LoginFunc
+// are logged and reported as a generic failure.
+type LoginError struct {
+	Message string
+}
+
+func (e *LoginError) Error() string {
+	return e.Message
+}
+
+// Handler serves the login and callback endpoints for the configured providers
+type Handler struct {
+	configs map[string]Config
+	secret  []byte
+	login   LoginFunc
+
+	mu        sync.Mutex
+	providers map[string]*Provider
+}
+
+// NewHandler creates a handler for the given providers. stateSecret signs the
+// short-lived cookie that carries the state, nonce and PKCE verifier.
+func NewHandler(configs []Config, stateSecret []byte, login LoginFunc) *Handler {
+	h := &Handler{
+		configs:   map[string]Config{},
+		secret:    stateSecret,
+		login:     login,
+		providers: map[string]*Provider{},
+	}
+	for _, config := range configs {
+		h.configs[config.Name] = config
+	}
+	return h
+}
+
+// Register mounts the OIDC endpoints on the router:
+//
+//	GET /auth/oidc/providers          names of the configured providers
+//	GET /auth/oidc/{provider}/login    redirects to the provider
+//	GET /auth/oidc/{provider}/callback completes the login
+func (h *Handler) Register(router *mux.Router) {
+	router.HandleFunc("/auth/oidc/providers", h.ServeProviders).Methods("GET")
+	router.HandleFunc("/auth/oidc/{provider}/login", h.ServeLogin).Methods("GET")
+	router.HandleFunc("/auth/oidc/{provider}/callback", h.ServeCallback).Methods("GET")
+}
+
+// ProviderNames returns the configured provider names in order
+func (h *Handler) ProviderNames() []string {
+	names := make([]string, 0, len(h.configs))
+	for name := range h.configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ServeProviders lists the configured providers so the frontend can show login buttons
+func (h *Handler) ServeProviders(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"providers": h.ProviderNames()})
+}
+
+// provider returns a provider by name, running discovery on first use
+func (h *Handler) provider(ctx context.Context, name string) (*Provider, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if provider, ok := h.providers[name]; ok {
+		return provider, nil
+	}
+	config, ok := h.configs[name]
+	if !ok {
+		return nil, errUnknownProvider
+	}
+	provider, err := NewProvider(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	h.providers[name] = provider
+	return provider, nil
+}
+
+var errUnknownProvider = errors.New("unknown login provider")
+
+// loginState is stored in a signed cookie between the login redirect and the callback
+type loginState struct {
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"verifier"`
+	ExpiresAt    int64  `json:"exp"`
+}
+
+// ServeLogin starts a login by redirecting to the provider's authorization endpoint
+func (h *Handler) ServeLogin(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["provider"]
+	provider, err := h.provider(r.Context(), name)
+	if err == errUnknownProvider {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("OIDC login failed: %v", err)
+		http.Error(w, "login provider is unavailable", http.StatusBadGateway)
+		return
+	}
+
+	state := loginState{ExpiresAt: time.Now().Add(stateTTL).Unix()}
+	for _, value := range []*string{&state.State, &state.Nonce, &state.CodeVerifier} {
+		if *value, err = randomString(32); err != nil {
+			http.Error(w, "failed to start login", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookieName(name),
+		Value:    h.signState(state),
+		Path:     "/auth/oidc/" + name + "/",
+		MaxAge:   int(stateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, provider.AuthCodeURL(state.State, state.Nonce, state.CodeVerifier), http.StatusFound)
+}
+
+// ServeCallback verifies the provider's response, exchanges the code and signs the user in
+func (h *Handler) ServeCallback(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["provider"]
+	provider, err := h.provider(r.Context(), name)
+	if err == errUnknownProvider {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("OIDC callback failed: %v", err)
+		http.Error(w, "login provider is unavailable", http.StatusBadGateway)
+		return
+	}
+
+	// The state cookie is single-use
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookieName(name),
+		Path:     "/auth/oidc/" + name + "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isHTTPS(r),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		http.Error(w, "login was cancelled or denied: "+providerErr, http.StatusUnauthorized)
+		return
+	}
+
+	cookie, err := r.Cookie(stateCookieName(name))
+	if err != nil {
+		http.Error(w, "login session expired, please try again", http.StatusBadRequest)
+		return
+	}
+	state, ok := h.verifyState(cookie.Value)
+	if !ok || subtle.ConstantTimeCompare([]byte(state.State), []byte(query.Get("state"))) != 1 {
+		http.Error(w, "invalid login state, please try again", http.StatusBadRequest)
+		return
+	}
+
+	identity, err := provider.Exchange(r.Context(), query.Get("code"), state.CodeVerifier, state.Nonce)
+	if err != nil {
+		log.Printf("OIDC %s code exchange failed: %v", name, err)
+		http.Error(w, "failed to complete login", http.StatusUnauthorized)
+		return
+	}
+
+	redirectURL, err := h.login(r.Context(), identity)
+	var loginErr *LoginError
+	if errors.As(err, &loginErr) {
+		http.Error(w, loginErr.Message, http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		log.Printf("OIDC %s login failed: %v", name, err)
+		http.Error(w, "failed to complete login", http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, redirectURL, http.StatusFound)
+}
+
+// signState encodes the login state as base64(json).base64(hmac)
+func (h *Handler) signState(state loginState) string {
+	payload, _ := json.Marshal(state)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(h.mac(encoded))
+}
+
+// verifyState checks the signature and expiry of a state cookie
+func (h *Handler) verifyState(value string) (loginState, bool) {
+	var state loginState
+	encoded, signature, found := strings.Cut(value, ".")
+	if !found {
+		return state, false
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, h.mac(encoded)) {
+		return state, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || json.Unmarshal(payload, &state) != nil {
+		return state, false
+	}
+	return state, time.Now().Unix() < state.ExpiresAt
+}
+
+func (h *Handler) mac(data string) []byte {
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func stateCookieName(provider string) string {
+	return "oidc_state_" + provider
+}
+
+// isHTTPS reports whether the browser reached us over HTTPS, directly or via a proxy
+func isHTTPS(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
@@ -0,0 +1,216 @@
+package oidc
+
+import (
+	"ai-catalog/oidc/oidctest"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// loginTest is a handler for the provider "mock" backed by an oidctest provider
+type loginTest struct {
+	handler  *Handler
+	router   *mux.Router
+	provider *oidctest.Provider
+	// identities are the identities the handler signed in
+	identities []*Identity
+	loginErr   error
+}
+
+func newLoginTest(t *testing.T) *loginTest {
+	t.Helper()
+	provider, server, err := oidctest.NewServer("store", "store-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+	provider.AddUser(oidctest.User{Subject: "alice-1", Email: "alice@example.com", EmailVerified: true, GivenName: "Alice"})
+
+	lt := &loginTest{router: mux.NewRouter(), provider: provider}
+	lt.handler = NewHandler([]Config{{
+		Name:         "mock",
+		Issuer:       server.URL,
+		ClientID:     "store",
+		ClientSecret: "store-secret",
+		RedirectURL:  "http://store.test/auth/oidc/mock/callback",
+		Scopes:       []string{"openid", "email", "profile"},
+	}}, []byte("state-secret"), func(ctx context.Context, identity *Identity) (string, error) {
+		lt.identities = append(lt.identities, identity)
+		return "http://store.test/app#token=signed-in", lt.loginErr
+	})
+	lt.handler.Register(lt.router)
+	return lt
+}
+
+// start begins a login and returns the state cookie and the provider's URL
+func (lt *loginTest) start(t *testing.T) (*http.Cookie, *url.URL) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	lt.router.ServeHTTP(rec, httptest.NewRequest("GET", "http://store.test/auth/oidc/mock/login", nil))
+	if rec.Code != http.StatusFound {
+		t.Fatalf("login: status %d: %s", rec.Code, rec.Body)
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != stateCookieName("mock") {
+		t.Fatalf("login set cookies %v, want the state cookie", cookies)
+	}
+	authorizeURL, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cookies[0], authorizeURL
+}
+
+// authorize approves the login at the provider and returns the callback URL it
+// redirects back to
+func (lt *loginTest) authorize(t *testing.T, authorizeURL *url.URL) *url.URL {
+	t.Helper()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(authorizeURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("authorize: status %d", resp.StatusCode)
+	}
+	callbackURL, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return callbackURL
+}
+
+// callback completes the login with the given state cookie
+func (lt *loginTest) callback(callbackURL *url.URL, cookie *http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", callbackURL.String(), nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	lt.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCallbackSignsInTheVerifiedIdentity(t *testing.T) {
+	lt := newLoginTest(t)
+	cookie, authorizeURL := lt.start(t)
+	rec := lt.callback(lt.authorize(t, authorizeURL), cookie)
+
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "http://store.test/app#token=signed-in" {
+		t.Fatalf("callback: status %d, Location %q: %s", rec.Code, rec.Header().Get("Location"), rec.Body)
+	}
+	if len(lt.identities) != 1 {
+		t.Fatalf("signed in %d identities, want 1", len(lt.identities))
+	}
+	want := Identity{Provider: "mock", Subject: "alice-1", Email: "alice@example.com", EmailVerified: true, GivenName: "Alice", Name: "Alice"}
+	if *lt.identities[0] != want {
+		t.Errorf("identity = %+v, want %+v", *lt.identities[0], want)
+	}
+	if cleared := rec.Result().Cookies(); len(cleared) != 1 || cleared[0].MaxAge >= 0 {
+		t.Errorf("callback cookies = %v, want the state cookie cleared", cleared)
+	}
+}
+
+func TestCallbackRejectsAMismatchedState(t *testing.T) {
+	lt := newLoginTest(t)
+	tests := []struct {
+		name string
+		// prepare returns the callback URL and cookie to complete a fresh login with
+		prepare    func(t *testing.T) (*url.URL, *http.Cookie)
+		wantStatus int
+	}{
+		{"no state cookie", func(t *testing.T) (*url.URL, *http.Cookie) {
+			_, authorizeURL := lt.start(t)
+			return lt.authorize(t, authorizeURL), nil
+		}, http.StatusBadRequest},
+		{"state of another login", func(t *testing.T) (*url.URL, *http.Cookie) {
+			cookie, _ := lt.start(t)
+			_, authorizeURL := lt.start(t)
+			return lt.authorize(t, authorizeURL), cookie
+		}, http.StatusBadRequest},
+		{"tampered state cookie", func(t *testing.T) (*url.URL, *http.Cookie) {
+			cookie, authorizeURL := lt.start(t)
+			cookie.Value = strings.Replace(cookie.Value, ".", "x.", 1)
+			return lt.authorize(t, authorizeURL), cookie
+		}, http.StatusBadRequest},
+		{"expired state", func(t *testing.T) (*url.URL, *http.Cookie) {
+			cookie, authorizeURL := lt.start(t)
+			state := stateOf(t, lt, cookie)
+			state.ExpiresAt = time.Now().Add(-time.Second).Unix()
+			cookie.Value = lt.handler.signState(state)
+			return lt.authorize(t, authorizeURL), cookie
+		}, http.StatusBadRequest},
+		{"wrong PKCE verifier", func(t *testing.T) (*url.URL, *http.Cookie) {
+			cookie, authorizeURL := lt.start(t)
+			state := stateOf(t, lt, cookie)
+			state.CodeVerifier = "not-the-verifier"
+			cookie.Value = lt.handler.signState(state)
+			return lt.authorize(t, authorizeURL), cookie
+		}, http.StatusUnauthorized},
+		{"wrong nonce", func(t *testing.T) (*url.URL, *http.Cookie) {
+			cookie, authorizeURL := lt.start(t)
+			state := stateOf(t, lt, cookie)
+			state.Nonce = "not-the-nonce"
+			cookie.Value = lt.handler.signState(state)
+			return lt.authorize(t, authorizeURL), cookie
+		}, http.StatusUnauthorized},
+		{"provider error", func(t *testing.T) (*url.URL, *http.Cookie) {
+			cookie, _ := lt.start(t)
+			callbackURL, _ := url.Parse("http://store.test/auth/oidc/mock/callback?error=access_denied&state=" + stateOf(t, lt, cookie).State)
+			return callbackURL, cookie
+		}, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callbackURL, cookie := tt.prepare(t)
+			rec := lt.callback(callbackURL, cookie)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("callback: status %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if len(lt.identities) != 0 {
+				t.Errorf("signed in %+v", lt.identities)
+			}
+		})
+	}
+}
+
+// stateOf returns the login state carried by a state cookie
+func stateOf(t *testing.T, lt *loginTest, cookie *http.Cookie) loginState {
+	t.Helper()
+	state, ok := lt.handler.verifyState(cookie.Value)
+	if !ok {
+		t.Fatal("the handler rejected its own state cookie")
+	}
+	return state
+}
+
+func TestCallbackOnlyShowsLoginErrorsMeantForTheUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{"refusal", &LoginError{Message: "your account is suspended"}, http.StatusForbidden, "your account is suspended"},
+		{"internal error", errors.New("pq: could not connect to server at 10.0.0.5"), http.StatusInternalServerError, "failed to complete login"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lt := newLoginTest(t)
+			lt.loginErr = tt.err
+			cookie, authorizeURL := lt.start(t)
+			rec := lt.callback(lt.authorize(t, authorizeURL), cookie)
+			if rec.Code != tt.wantStatus || strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("callback: status %d, body %q, want %d, %q", rec.Code, rec.Body, tt.wantStatus, tt.wantBody)
+			}
+		})
+	}
+}
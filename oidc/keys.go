@@ -0,0 +1,155 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// keyRefreshInterval limits how often an unknown kid triggers a JWKS refetch
+const keyRefreshInterval = time.Minute
+
+// jsonWebKey is a public key from a provider's JWKS document
+type jsonWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	Y         string `json:"y"`
+}
+
+// remoteKeySet caches a provider's signing keys and refetches them when a token
+// names a kid it has not seen, so provider key rotations are picked up
+type remoteKeySet struct {
+	client *http.Client
+	url    string
+
+	mu        sync.Mutex
+	keys      map[string]publicKey
+	fetchedAt time.Time
+}
+
+// publicKey is a parsed verification key and the JWT algorithm it belongs to
+type publicKey struct {
+	algorithm string
+	key       interface{}
+}
+
+func newRemoteKeySet(client *http.Client, url string) *remoteKeySet {
+	return &remoteKeySet{client: client, url: url}
+}
+
+// key returns the verification key for a kid and algorithm
+func (s *remoteKeySet) key(ctx context.Context, kid, algorithm string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.lookup(kid)
+	if !ok && time.Since(s.fetchedAt) > keyRefreshInterval {
+		if err := s.refresh(ctx); err != nil {
+			return nil, err
+		}
+		key, ok = s.lookup(kid)
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if key.algorithm != algorithm {
+		return nil, fmt.Errorf("signing key %q is not a %s key", kid, algorithm)
+	}
+	return key.key, nil
+}
+
+// lookup finds a cached key; tokens without a kid match a provider's only key
+func (s *remoteKeySet) lookup(kid string) (publicKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// refresh fetches the JWKS document, skipping keys of unsupported types
+func (s *remoteKeySet) refresh(ctx context.Context) error {
+	var doc struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := getJSON(ctx, s.client, s.url, &doc); err != nil {
+		return fmt.Errorf("failed to fetch provider keys: %v", err)
+	}
+
+	keys := map[string]publicKey{}
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if key, err := parseJWK(jwk); err == nil {
+			keys[jwk.KeyID] = key
+		}
+	}
+
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	return nil
+}
+
+// parseJWK converts an RSA, P-256 or Ed25519 JWK into a verification key
+func parseJWK(jwk jsonWebKey) (publicKey, error) {
+	switch jwk.KeyType {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return publicKey{}, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return publicKey{}, err
+		}
+		return publicKey{algorithm: "RS256", key: &rsa.PublicKey{N: n, E: int(e.Int64())}}, nil
+	case "EC":
+		if jwk.Curve != "P-256" {
+			return publicKey{}, fmt.Errorf("unsupported curve %s", jwk.Curve)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return publicKey{}, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return publicKey{}, err
+		}
+		return publicKey{algorithm: "ES256", key: &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}}, nil
+	case "OKP":
+		if jwk.Curve != "Ed25519" {
+			return publicKey{}, fmt.Errorf("unsupported curve %s", jwk.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return publicKey{}, fmt.Errorf("invalid Ed25519 key")
+		}
+		return publicKey{algorithm: "EdDSA", key: ed25519.PublicKey(x)}, nil
+	}
+	return publicKey{}, fmt.Errorf("unsupported key type %s", jwk.KeyType)
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
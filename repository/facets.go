@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"ai-catalog/model"
+	"sort"
+)
+
+// PriceRangeBounds are the boundaries of the price range facets: below 50, 50
+// up to 100, and so on up to 1000 and above
+var PriceRangeBounds = []float64{50, 100, 250, 500, 1000}
+
+// RatingThresholds are the minimum average ratings the rating facets count,
+// best first
+var RatingThresholds = []int{4, 3, 2, 1}
+
+// priceBucket returns the price range holding price, numbered like the
+// Postgres width_bucket function: 0 below the first bound, then 1 and up
+func priceBucket(price float64) int {
+	return sort.Search(len(PriceRangeBounds), func(i int) bool { return PriceRangeBounds[i] > price })
+}
+
+// facetFilters are the filters the facets are counted with. Counts are
+// disjunctive: each facet applies every filter but its own, so once a category
+// or price range is chosen the facet still shows what the other values hold.
+type facetFilters struct {
+	category, price, rating, stock ProductFilter
+}
+
+// newFacetFilters returns the filters for counting the facets of filter
+func newFacetFilters(filter ProductFilter) facetFilters {
+	f := facetFilters{category: filter, price: filter, rating: filter, stock: filter}
+	f.category.CategoryID, f.category.IncludeDescendants = nil, false
+	f.price.MinPrice, f.price.MaxPrice = nil, nil
+	f.rating.MinRating = nil
+	f.stock.InStock = nil
+	return f
+}
+
+// newProductFacets builds facets from the number of products in each price
+// bucket and at each rating threshold. Empty price ranges are left out.
+func newProductFacets(priceBuckets map[int]int, ratings map[int]int) *model.ProductFacets {
+	facets := &model.ProductFacets{
+		Categories:  []*model.CategoryFacet{},
+		PriceRanges: []*model.PriceRangeFacet{},
+		Ratings:     []*model.RatingFacet{},
+	}
+	for bucket := 0; bucket <= len(PriceRangeBounds); bucket++ {
+		if priceBuckets[bucket] == 0 {
+			continue
+		}
+		priceRange := &model.PriceRangeFacet{Count: priceBuckets[bucket]}
+		if bucket > 0 {
+			priceRange.Min = PriceRangeBounds[bucket-1]
+		}
+		if bucket < len(PriceRangeBounds) {
+			max := PriceRangeBounds[bucket]
+			priceRange.Max = &max
+		}
+		facets.PriceRanges = append(facets.PriceRanges, priceRange)
+	}
+	for _, threshold := range RatingThresholds {
+		facets.Ratings = append(facets.Ratings, &model.RatingFacet{MinRating: threshold, Count: ratings[threshold]})
+	}
+	return facets
+}
+
+// sortCategoryFacets orders category facets by count, largest first
+func sortCategoryFacets(facets []*model.CategoryFacet) {
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].CategoryID < facets[j].CategoryID
+	})
+}
@@ -0,0 +1,1785 @@
+package repository
+
+import (
+	"ai-catalog/model"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Memory holds the store's data in memory. It implements the same contracts as
+// the Postgres repositories, so resolvers can be tested without a database.
+// Entities are copied in and out so callers cannot change stored data.
+type Memory struct {
+	mu     sync.Mutex
+	nextID int
+
+	users          map[int]*model.User
+	passwordHashes map[int]string
+	categories     map[int]*model.Category
+	products       map[int]*model.Product
+	variants       map[int]*model.ProductVariant
+	productLikes   map[[2]int]bool
+	cart           map[int]*model.CartItem
+	wishlist       map[int]*model.WishlistItem
+	orders         map[int]*model.Order
+	orderItems     map[int][]*model.OrderItem
+	reviews        map[int]*model.Review
+	reviewLikes    map[[2]int]bool
+	searches       []memorySearch
+
+	identities        map[int]*memoryIdentity
+	loginAttempts     []*model.LoginAttempt
+	twoFactor         map[int]*memoryTwoFactor
+	usedChallenges    map[string]time.Time
+	productTexts      map[string]map[int]*Translation
+	categoryTexts     map[string]map[int]*Translation
+	translationMemory map[int]*memoryTranslation
+
+	sessions   map[string]*memorySession
+	apiKeys    map[int]*memoryAPIKey
+	userTokens []*memoryUserToken
+	jobs       map[int]*memoryJob
+}
+
+// memorySearch is an entry of the in-memory search log
+type memorySearch struct {
+	NewSearch
+	normalized string
+	createdAt  time.Time
+}
+
+// NewMemory returns an empty in-memory store
+func NewMemory() *Memory {
+	return &Memory{
+		users:          map[int]*model.User{},
+		passwordHashes: map[int]string{},
+		categories:     map[int]*model.Category{},
+		products:       map[int]*model.Product{},
+		variants:       map[int]*model.ProductVariant{},
+		productLikes:   map[[2]int]bool{},
+		cart:           map[int]*model.CartItem{},
+		wishlist:       map[int]*model.WishlistItem{},
+		orders:         map[int]*model.Order{},
+		orderItems:     map[int][]*model.OrderItem{},
+		reviews:        map[int]*model.Review{},
+		reviewLikes:    map[[2]int]bool{},
+
+		identities:        map[int]*memoryIdentity{},
+		twoFactor:         map[int]*memoryTwoFactor{},
+		usedChallenges:    map[string]time.Time{},
+		productTexts:      map[string]map[int]*Translation{},
+		categoryTexts:     map[string]map[int]*Translation{},
+		translationMemory: map[int]*memoryTranslation{},
+
+		sessions: map[string]*memorySession{},
+		apiKeys:  map[int]*memoryAPIKey{},
+		jobs:     map[int]*memoryJob{},
+	}
+}
+
+// Repositories returns repositories backed by the store
+func (m *Memory) Repositories() *Repositories {
+	return &Repositories{
+		Users:      memoryUsers{m},
+		Categories: memoryCategories{m},
+		Products:   memoryProducts{m},
+		Variants:   memoryVariants{m},
+		Carts:      memoryCarts{m},
+		Wishlists:  memoryWishlists{m},
+		Orders:     memoryOrders{m},
+		Reviews:    memoryReviews{m},
+		Searches:   memorySearches{m},
+
+		Identities:        memoryIdentities{m},
+		LoginAttempts:     memoryLoginAttempts{m},
+		TwoFactor:         memoryTwoFactorStore{m},
+		Translations:      memoryTranslations{m},
+		TranslationMemory: memoryTranslationMemory{m},
+
+		Sessions:   memorySessions{m},
+		APIKeys:    memoryAPIKeys{m},
+		UserTokens: memoryUserTokens{m},
+		Jobs:       memoryJobs{m},
+	}
+}
+
+// AddCategory stores a category, assigning its ID, and returns the stored copy
+func (m *Memory) AddCategory(category model.Category) *model.Category {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	category.ID = m.newID()
+	if category.CreatedAt.IsZero() {
+		category.CreatedAt = time.Now()
+	}
+	m.categories[category.ID] = &category
+	return copyCategory(&category)
+}
+
+// AddProduct stores a product, assigning its ID, and returns the stored copy
+func (m *Memory) AddProduct(product model.Product) *model.Product {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	product.ID = m.newID()
+	product.Category = nil
+	now := time.Now()
+	if product.CreatedAt.IsZero() {
+		product.CreatedAt = now
+	}
+	if product.UpdatedAt.IsZero() {
+		product.UpdatedAt = now
+	}
+	m.products[product.ID] = &product
+	return copyProduct(&product)
+}
+
+// newID returns the next unused ID. The caller must hold m.mu.
+func (m *Memory) newID() int {
+	m.nextID++
+	return m.nextID
+}
+
+func copyUser(user *model.User) *model.User {
+	c := *user
+	return &c
+}
+
+func copyCategory(category *model.Category) *model.Category {
+	c := *category
+	if category.ParentID != nil {
+		parentID := *category.ParentID
+		c.ParentID = &parentID
+	}
+	return &c
+}
+
+func copyProduct(product *model.Product) *model.Product {
+	c := *product
+	return &c
+}
+
+func copyVariant(variant *model.ProductVariant) *model.ProductVariant {
+	c := *variant
+	c.Options = make([]*model.SelectedOption, len(variant.Options))
+	for i, option := range variant.Options {
+		o := *option
+		c.Options[i] = &o
+	}
+	c.Images = append([]string{}, variant.Images...)
+	return &c
+}
+
+func copyOrder(order *model.Order) *model.Order {
+	c := *order
+	c.Items = nil
+	return &c
+}
+
+// containsFold reports whether substr is within s, ignoring case
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+type memoryUsers struct {
+	*Memory
+}
+
+func (r memoryUsers) GetByID(ctx context.Context, id int) (*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return copyUser(user), nil
+}
+
+func (r memoryUsers) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if user.Email == email {
+			return copyUser(user), nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+func (r memoryUsers) Create(ctx context.Context, newUser NewUser) (*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.createUser(newUser)
+}
+
+// createUser stores a new account. The caller must hold m.mu.
+func (m *Memory) createUser(newUser NewUser) (*model.User, error) {
+	for _, user := range m.users {
+		if user.Email == newUser.Email {
+			return nil, ErrEmailTaken
+		}
+	}
+
+	now := time.Now()
+	user := &model.User{
+		ID:            m.newID(),
+		Email:         newUser.Email,
+		FirstName:     newUser.FirstName,
+		LastName:      newUser.LastName,
+		Phone:         newUser.Phone,
+		Address:       newUser.Address,
+		City:          newUser.City,
+		Country:       "Saudi Arabia",
+		Role:          "customer",
+		EmailVerified: newUser.EmailVerified,
+		Status:        "active",
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	m.users[user.ID] = user
+	m.passwordHashes[user.ID] = newUser.PasswordHash
+	return copyUser(user), nil
+}
+
+func (r memoryUsers) PasswordHash(ctx context.Context, id int) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[id]; !ok {
+		return "", ErrUserNotFound
+	}
+	return r.passwordHashes[id], nil
+}
+
+// update applies change to a stored user
+func (r memoryUsers) update(id int, change func(user *model.User)) (*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	change(user)
+	user.UpdatedAt = time.Now()
+	return copyUser(user), nil
+}
+
+func (r memoryUsers) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	_, err := r.update(id, func(user *model.User) { r.passwordHashes[id] = passwordHash })
+	return err
+}
+
+func (r memoryUsers) MarkEmailVerified(ctx context.Context, id int) error {
+	_, err := r.update(id, func(user *model.User) { user.EmailVerified = true })
+	return err
+}
+
+func (r memoryUsers) UpdateProfile(ctx context.Context, id int, update ProfileUpdate) (*model.User, error) {
+	return r.update(id, func(user *model.User) {
+		user.FirstName = update.FirstName
+		user.LastName = update.LastName
+		if update.Phone != nil {
+			user.Phone = *update.Phone
+		}
+		if update.Address != nil {
+			user.Address = *update.Address
+		}
+		if update.City != nil {
+			user.City = *update.City
+		}
+	})
+}
+
+func (r memoryUsers) SetRole(ctx context.Context, id int, role string) error {
+	_, err := r.update(id, func(user *model.User) { user.Role = role })
+	return err
+}
+
+func (r memoryUsers) SetStatus(ctx context.Context, id int, status string) error {
+	_, err := r.update(id, func(user *model.User) { user.Status = status })
+	return err
+}
+
+func (r memoryUsers) SetTwoFactorRequired(ctx context.Context, id int, required bool) error {
+	_, err := r.update(id, func(user *model.User) { user.TwoFactorRequired = required })
+	return err
+}
+
+type memoryCategories struct {
+	*Memory
+}
+
+func (r memoryCategories) GetByID(ctx context.Context, id int) (*model.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	category, ok := r.categories[id]
+	if !ok {
+		return nil, ErrCategoryNotFound
+	}
+	return copyCategory(category), nil
+}
+
+func (r memoryCategories) List(ctx context.Context) ([]*model.Category, error) {
+	return r.Search(ctx, "")
+}
+
+// Search matches category names; the in-memory store has no translations
+func (r memoryCategories) Search(ctx context.Context, text string) ([]*model.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	categories := []*model.Category{}
+	for _, category := range r.categories {
+		if containsFold(normalizeArabic(category.Name), normalizeArabic(text)) {
+			categories = append(categories, copyCategory(category))
+		}
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Name < categories[j].Name })
+	return categories, nil
+}
+
+// Suggest matches category names; the in-memory store has no translations
+func (r memoryCategories) Suggest(ctx context.Context, prefix string, limit int) ([]*model.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prefix = normalizeQuery(prefix)
+	categories := []*model.Category{}
+	ranks := map[int]float64{}
+	for _, category := range r.categories {
+		if rank, ok := suggestionRank(category.Name, prefix); ok {
+			categories = append(categories, copyCategory(category))
+			ranks[category.ID] = rank
+		}
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		a, b := categories[i], categories[j]
+		if ranks[a.ID] != ranks[b.ID] {
+			return ranks[a.ID] > ranks[b.ID]
+		}
+		return a.Name < b.Name
+	})
+	return categories[:min(limit, len(categories))], nil
+}
+
+func (r memoryCategories) Children(ctx context.Context, parentID *int) ([]*model.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	categories := []*model.Category{}
+	for _, category := range r.categories {
+		if (parentID == nil && category.ParentID == nil) ||
+			(parentID != nil && category.ParentID != nil && *category.ParentID == *parentID) {
+			categories = append(categories, copyCategory(category))
+		}
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Name < categories[j].Name })
+	return categories, nil
+}
+
+func (r memoryCategories) Ancestors(ctx context.Context, id int) ([]*model.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ancestors := []*model.Category{}
+	category, ok := r.categories[id]
+	if !ok {
+		return ancestors, nil
+	}
+	seen := map[int]bool{id: true}
+	for category.ParentID != nil && !seen[*category.ParentID] {
+		parent, ok := r.categories[*category.ParentID]
+		if !ok {
+			break
+		}
+		seen[parent.ID] = true
+		ancestors = append([]*model.Category{copyCategory(parent)}, ancestors...)
+		category = parent
+	}
+	return ancestors, nil
+}
+
+func (r memoryCategories) Create(ctx context.Context, newCategory NewCategory) (*model.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if newCategory.ParentID != nil {
+		if _, ok := r.categories[*newCategory.ParentID]; !ok {
+			return nil, ErrCategoryNotFound
+		}
+	}
+
+	category := &model.Category{
+		ID:          r.newID(),
+		Name:        newCategory.Name,
+		Description: newCategory.Description,
+		ImageURL:    newCategory.ImageURL,
+		CreatedAt:   time.Now(),
+	}
+	if newCategory.ParentID != nil {
+		parentID := *newCategory.ParentID
+		category.ParentID = &parentID
+	}
+	r.categories[category.ID] = category
+	return copyCategory(category), nil
+}
+
+// inSubtree reports whether id is root or one of its descendants. The caller
+// must hold m.mu.
+func (m *Memory) inSubtree(root, id int) bool {
+	seen := map[int]bool{}
+	for !seen[id] {
+		if id == root {
+			return true
+		}
+		seen[id] = true
+		category, ok := m.categories[id]
+		if !ok || category.ParentID == nil {
+			return false
+		}
+		id = *category.ParentID
+	}
+	return false
+}
+
+func (r memoryCategories) Move(ctx context.Context, id int, parentID *int) (*model.Category, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	category, ok := r.categories[id]
+	if !ok {
+		return nil, ErrCategoryNotFound
+	}
+
+	if parentID == nil {
+		category.ParentID = nil
+		return copyCategory(category), nil
+	}
+	if _, ok := r.categories[*parentID]; !ok {
+		return nil, ErrCategoryNotFound
+	}
+	if r.inSubtree(id, *parentID) {
+		return nil, ErrCategoryCycle
+	}
+	newParentID := *parentID
+	category.ParentID = &newParentID
+	return copyCategory(category), nil
+}
+
+func (r memoryCategories) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.categories[id]; !ok {
+		return ErrCategoryNotFound
+	}
+	for _, category := range r.categories {
+		if category.ParentID != nil && *category.ParentID == id {
+			return ErrCategoryNotEmpty
+		}
+	}
+	for _, product := range r.products {
+		if product.CategoryID == id {
+			return ErrCategoryNotEmpty
+		}
+	}
+
+	delete(r.categories, id)
+	return nil
+}
+
+type memoryProducts struct {
+	*Memory
+}
+
+func (r memoryProducts) GetByID(ctx context.Context, id int) (*model.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	product, ok := r.products[id]
+	if !ok {
+		return nil, ErrProductNotFound
+	}
+	return copyProduct(product), nil
+}
+
+// matches reports whether product passes filter. Search matches the name,
+// SKU and descriptions; the in-memory store has no translations.
+func (filter ProductFilter) matches(product *model.Product) bool {
+	switch {
+	case filter.ActiveOnly && !product.IsActive:
+		return false
+	case filter.CategoryID != nil && !filter.IncludeDescendants && product.CategoryID != *filter.CategoryID:
+		return false
+	case filter.Search != "" && searchRank(product, filter.Search) == 0:
+		return false
+	case filter.IsFeatured != nil && product.IsFeatured != *filter.IsFeatured:
+		return false
+	case filter.InStock != nil && (product.StockQuantity > 0) != *filter.InStock:
+		return false
+	}
+	return true
+}
+
+// productMatches reports whether product is selected by filter, including the
+// products of subcategories when filter.IncludeDescendants is set. The caller
+// must hold m.mu.
+func (m *Memory) productMatches(filter ProductFilter, product *model.Product) bool {
+	if filter.CategoryID != nil && filter.IncludeDescendants && !m.inSubtree(*filter.CategoryID, product.CategoryID) {
+		return false
+	}
+	if filter.MinRating != nil && m.averageRatings()[product.ID] < float64(*filter.MinRating) {
+		return false
+	}
+	if filter.MinPrice != nil || filter.MaxPrice != nil {
+		inRange := false
+		for _, price := range m.productPrices(product) {
+			if (filter.MinPrice == nil || price >= *filter.MinPrice) && (filter.MaxPrice == nil || price <= *filter.MaxPrice) {
+				inRange = true
+				break
+			}
+		}
+		if !inRange {
+			return false
+		}
+	}
+	return filter.matches(product)
+}
+
+// productPrices returns the prices a product is sold at: those of its active
+// variants, or its own price when it has no variants. The caller must hold m.mu.
+func (m *Memory) productPrices(product *model.Product) []float64 {
+	variants := m.productVariants(product.ID)
+	if len(variants) == 0 {
+		return []float64{product.Price}
+	}
+	var prices []float64
+	for _, variant := range variants {
+		if variant.IsActive {
+			prices = append(prices, variant.Price)
+		}
+	}
+	return prices
+}
+
+// productPrice returns the price a product is sorted by: the lowest of its
+// productPrices, or its own price when no variant is active. The caller must
+// hold m.mu.
+func (m *Memory) productPrice(product *model.Product) float64 {
+	prices := m.productPrices(product)
+	if len(prices) == 0 {
+		return product.Price
+	}
+	return slices.Min(prices)
+}
+
+func (r memoryProducts) List(ctx context.Context, filter ProductFilter) ([]*model.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	products := []*model.Product{}
+	for _, product := range r.products {
+		if r.productMatches(filter, product) {
+			products = append(products, copyProduct(product))
+		}
+	}
+
+	sort.Slice(products, func(i, j int) bool {
+		a, b := products[i], products[j]
+		if filter.Search != "" {
+			if rankA, rankB := searchRank(a, filter.Search), searchRank(b, filter.Search); rankA != rankB {
+				return rankA > rankB
+			}
+		}
+		if filter.FeaturedFirst && a.IsFeatured != b.IsFeatured {
+			return a.IsFeatured
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	})
+	if filter.Offset > 0 {
+		products = products[min(filter.Offset, len(products)):]
+	}
+	if filter.Limit > 0 && len(products) > filter.Limit {
+		products = products[:filter.Limit]
+	}
+	return products, nil
+}
+
+func (r memoryProducts) ListPage(ctx context.Context, filter ProductFilter, sortBy ProductSort, page Page) (*model.ProductConnection, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Keys match the Postgres keysets; creation times are compared as Unix nanoseconds
+	var key func(product *model.Product) float64
+	desc := true
+	switch sortBy {
+	case SortNewest:
+		key = func(product *model.Product) float64 { return float64(product.CreatedAt.UnixNano()) }
+	case SortPriceAsc, SortPriceDesc:
+		key = func(product *model.Product) float64 { return r.productPrice(product) }
+		desc = sortBy == SortPriceDesc
+	case SortRating:
+		ratings := r.averageRatings()
+		key = func(product *model.Product) float64 { return ratings[product.ID] }
+	case SortPopularity:
+		ordered := r.unitsOrdered()
+		key = func(product *model.Product) float64 { return ordered[product.ID] }
+	default:
+		return nil, fmt.Errorf("unknown product sort %q", sortBy)
+	}
+
+	products := []*model.Product{}
+	for _, product := range r.products {
+		if r.productMatches(filter, product) {
+			products = append(products, copyProduct(product))
+		}
+	}
+	total := len(products)
+
+	products, cursors, info, err := memoryPage(products, string(sortBy), desc, key,
+		func(product *model.Product) int { return product.ID }, page)
+	if err != nil {
+		return nil, err
+	}
+	connection := &model.ProductConnection{Edges: []*model.ProductEdge{}, PageInfo: info, TotalCount: total}
+	for i, product := range products {
+		connection.Edges = append(connection.Edges, &model.ProductEdge{Cursor: cursors[i], Node: product})
+	}
+	return connection, nil
+}
+
+// averageRatings returns the average review rating of each reviewed product.
+// The caller must hold m.mu.
+func (m *Memory) averageRatings() map[int]float64 {
+	sums := map[int]float64{}
+	counts := map[int]float64{}
+	for _, review := range m.reviews {
+		sums[review.ProductID] += float64(review.Rating)
+		counts[review.ProductID]++
+	}
+	for id := range sums {
+		sums[id] /= counts[id]
+	}
+	return sums
+}
+
+// unitsOrdered returns the number of units ordered of each product. The caller
+// must hold m.mu.
+func (m *Memory) unitsOrdered() map[int]float64 {
+	units := map[int]float64{}
+	for _, items := range m.orderItems {
+		for _, item := range items {
+			units[item.ProductID] += float64(item.Quantity)
+		}
+	}
+	return units
+}
+
+func (r memoryProducts) Count(ctx context.Context, filter ProductFilter) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	total := 0
+	for _, product := range r.products {
+		if r.productMatches(filter, product) {
+			total++
+		}
+	}
+	return total, nil
+}
+
+func (r memoryProducts) Facets(ctx context.Context, filter ProductFilter) (*model.ProductFacets, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	averageRatings := r.averageRatings()
+	facetFilters := newFacetFilters(filter)
+	byCategory := map[int]int{}
+	priceBuckets := map[int]int{}
+	ratings := map[int]int{}
+	var total, inStock, outOfStock int
+	for _, product := range r.products {
+		if r.productMatches(filter, product) {
+			total++
+		}
+		if product.CategoryID != 0 && r.productMatches(facetFilters.category, product) {
+			byCategory[product.CategoryID]++
+		}
+		if r.productMatches(facetFilters.price, product) {
+			buckets := map[int]bool{}
+			for _, price := range r.productPrices(product) {
+				buckets[priceBucket(price)] = true
+			}
+			for bucket := range buckets {
+				priceBuckets[bucket]++
+			}
+		}
+		if r.productMatches(facetFilters.rating, product) {
+			for _, threshold := range RatingThresholds {
+				if averageRatings[product.ID] >= float64(threshold) {
+					ratings[threshold]++
+				}
+			}
+		}
+		if r.productMatches(facetFilters.stock, product) {
+			if product.StockQuantity > 0 {
+				inStock++
+			} else {
+				outOfStock++
+			}
+		}
+	}
+
+	facets := newProductFacets(priceBuckets, ratings)
+	facets.Total, facets.InStock, facets.OutOfStock = total, inStock, outOfStock
+	for categoryID, count := range byCategory {
+		facets.Categories = append(facets.Categories, &model.CategoryFacet{CategoryID: categoryID, Count: count})
+	}
+	sortCategoryFacets(facets.Categories)
+	return facets, nil
+}
+
+// Suggest matches product names; the in-memory store has no translations
+func (r memoryProducts) Suggest(ctx context.Context, prefix string, limit int) ([]*model.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prefix = normalizeQuery(prefix)
+	products := []*model.Product{}
+	ranks := map[int]float64{}
+	for _, product := range r.products {
+		if !product.IsActive {
+			continue
+		}
+		if rank, ok := suggestionRank(product.Name, prefix); ok {
+			products = append(products, copyProduct(product))
+			ranks[product.ID] = rank
+		}
+	}
+
+	sort.Slice(products, func(i, j int) bool {
+		a, b := products[i], products[j]
+		if ranks[a.ID] != ranks[b.ID] {
+			return ranks[a.ID] > ranks[b.ID]
+		}
+		return a.Name < b.Name
+	})
+	return products[:min(limit, len(products))], nil
+}
+
+func (r memoryProducts) SKUTaken(ctx context.Context, sku string, excludeID int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.skuTaken(sku, excludeID, 0), nil
+}
+
+// skuTaken reports whether a product other than excludeProductID or a variant
+// other than excludeVariantID uses sku. The caller must hold m.mu.
+func (m *Memory) skuTaken(sku string, excludeProductID, excludeVariantID int) bool {
+	if sku == "" {
+		return false
+	}
+	for _, product := range m.products {
+		if product.SKU == sku && product.ID != excludeProductID {
+			return true
+		}
+	}
+	for _, variant := range m.variants {
+		if variant.SKU == sku && variant.ID != excludeVariantID {
+			return true
+		}
+	}
+	return false
+}
+
+// applyProductFields sets the fields of product from column values. NULL
+// columns are stored as zero values, matching what the Postgres scan returns.
+func applyProductFields(product *model.Product, fields ProductFields) {
+	for column, value := range fields {
+		switch column {
+		case "name":
+			product.Name, _ = value.(string)
+		case "price":
+			product.Price, _ = value.(float64)
+		case "original_price":
+			product.OriginalPrice = floatPointer(value)
+		case "category_id":
+			product.CategoryID, _ = value.(int)
+		case "description":
+			product.Description, _ = value.(string)
+		case "short_description":
+			product.ShortDescription, _ = value.(string)
+		case "image_url":
+			product.ImageURL, _ = value.(string)
+		case "stock_quantity":
+			product.StockQuantity, _ = value.(int)
+		case "sku":
+			product.SKU, _ = value.(string)
+		case "weight":
+			product.Weight = floatPointer(value)
+		case "dimensions":
+			product.Dimensions, _ = value.(string)
+		case "is_active":
+			product.IsActive, _ = value.(bool)
+		case "is_featured":
+			product.IsFeatured, _ = value.(bool)
+		}
+	}
+}
+
+// floatPointer returns a pointer to a float64 column value, or nil for NULL
+func floatPointer(value interface{}) *float64 {
+	f, ok := value.(float64)
+	if !ok {
+		return nil
+	}
+	return &f
+}
+
+func (r memoryProducts) Create(ctx context.Context, fields ProductFields) (*model.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Column defaults of the products table
+	product := &model.Product{IsActive: true}
+	applyProductFields(product, fields)
+	if r.skuTaken(product.SKU, 0, 0) {
+		return nil, ErrSKUTaken
+	}
+
+	product.ID = r.newID()
+	product.CreatedAt = time.Now()
+	product.UpdatedAt = product.CreatedAt
+	r.products[product.ID] = product
+	return copyProduct(product), nil
+}
+
+func (r memoryProducts) Update(ctx context.Context, id int, fields ProductFields) (*model.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	product, ok := r.products[id]
+	if !ok {
+		return nil, ErrProductNotFound
+	}
+
+	updated := copyProduct(product)
+	applyProductFields(updated, fields)
+	if r.skuTaken(updated.SKU, id, 0) {
+		return nil, ErrSKUTaken
+	}
+	if len(fields) > 0 {
+		updated.UpdatedAt = time.Now()
+	}
+	r.products[id] = updated
+	return copyProduct(updated), nil
+}
+
+func (r memoryProducts) SetActive(ctx context.Context, id int, active bool) (*model.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	product, ok := r.products[id]
+	if !ok {
+		return nil, ErrProductNotFound
+	}
+	product.IsActive = active
+	product.UpdatedAt = time.Now()
+	return copyProduct(product), nil
+}
+
+func (r memoryProducts) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, items := range r.orderItems {
+		for _, item := range items {
+			if item.ProductID == id {
+				return ErrProductOrdered
+			}
+		}
+	}
+	if _, ok := r.products[id]; !ok {
+		return ErrProductNotFound
+	}
+
+	delete(r.products, id)
+	for key, variant := range r.variants {
+		if variant.ProductID == id {
+			delete(r.variants, key)
+		}
+	}
+	for key, item := range r.cart {
+		if item.ProductID == id {
+			delete(r.cart, key)
+		}
+	}
+	for key, item := range r.wishlist {
+		if item.ProductID == id {
+			delete(r.wishlist, key)
+		}
+	}
+	return nil
+}
+
+func (r memoryProducts) SetLiked(ctx context.Context, userID, productID int, liked bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := [2]int{userID, productID}
+	if !liked {
+		delete(r.productLikes, key)
+		return nil
+	}
+	if product, ok := r.products[productID]; !ok || !product.IsActive {
+		return ErrProductNotFound
+	}
+	r.productLikes[key] = true
+	return nil
+}
+
+// activeProduct returns a product that can be added to a cart or wishlist. The
+// caller must hold m.mu.
+func (m *Memory) activeProduct(id int) (*model.Product, error) {
+	product, ok := m.products[id]
+	if !ok || !product.IsActive {
+		return nil, ErrProductNotFound
+	}
+	return product, nil
+}
+
+type memoryVariants struct {
+	*Memory
+}
+
+// productVariants returns the stored variants of a product in the order they
+// were created. The caller must hold m.mu.
+func (m *Memory) productVariants(productID int) []*model.ProductVariant {
+	var variants []*model.ProductVariant
+	for _, variant := range m.variants {
+		if variant.ProductID == productID {
+			variants = append(variants, variant)
+		}
+	}
+	sort.Slice(variants, func(i, j int) bool { return variants[i].ID < variants[j].ID })
+	return variants
+}
+
+// syncStock sets the stock of a product to the total stock of its active
+// variants, like the product_variants_sync_stock trigger. The caller must hold
+// m.mu.
+func (m *Memory) syncStock(productID int) {
+	product, ok := m.products[productID]
+	if !ok {
+		return
+	}
+	product.StockQuantity = 0
+	for _, variant := range m.productVariants(productID) {
+		if variant.IsActive {
+			product.StockQuantity += variant.StockQuantity
+		}
+	}
+}
+
+func (r memoryVariants) GetByID(ctx context.Context, id int) (*model.ProductVariant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	variant, ok := r.variants[id]
+	if !ok {
+		return nil, ErrVariantNotFound
+	}
+	return copyVariant(variant), nil
+}
+
+func (r memoryVariants) ListForProduct(ctx context.Context, productID int) ([]*model.ProductVariant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	variants := []*model.ProductVariant{}
+	for _, variant := range r.productVariants(productID) {
+		variants = append(variants, copyVariant(variant))
+	}
+	return variants, nil
+}
+
+func (r memoryVariants) SKUTaken(ctx context.Context, sku string, excludeID int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.skuTaken(sku, 0, excludeID), nil
+}
+
+func (r memoryVariants) Create(ctx context.Context, newVariant NewVariant) (*model.ProductVariant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.products[newVariant.ProductID]; !ok {
+		return nil, ErrProductNotFound
+	}
+	if r.skuTaken(newVariant.SKU, 0, 0) {
+		return nil, ErrSKUTaken
+	}
+	for _, variant := range r.productVariants(newVariant.ProductID) {
+		if sameOptions(variant.Options, newVariant.Options) {
+			return nil, ErrVariantExists
+		}
+	}
+
+	now := time.Now()
+	variant := copyVariant(&model.ProductVariant{
+		ID:            r.newID(),
+		ProductID:     newVariant.ProductID,
+		SKU:           newVariant.SKU,
+		Price:         newVariant.Price,
+		StockQuantity: newVariant.StockQuantity,
+		Options:       newVariant.Options,
+		Images:        newVariant.Images,
+		IsActive:      true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	})
+	r.variants[variant.ID] = variant
+	r.syncStock(variant.ProductID)
+	return copyVariant(variant), nil
+}
+
+func (r memoryVariants) Update(ctx context.Context, id int, update VariantUpdate) (*model.ProductVariant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	variant, ok := r.variants[id]
+	if !ok {
+		return nil, ErrVariantNotFound
+	}
+	if update.SKU != nil && r.skuTaken(*update.SKU, 0, id) {
+		return nil, ErrSKUTaken
+	}
+
+	if update.SKU != nil {
+		variant.SKU = *update.SKU
+	}
+	if update.Price != nil {
+		variant.Price = *update.Price
+	}
+	if update.StockQuantity != nil {
+		variant.StockQuantity = *update.StockQuantity
+	}
+	if update.Images != nil {
+		variant.Images = append([]string{}, update.Images...)
+	}
+	if update.IsActive != nil {
+		variant.IsActive = *update.IsActive
+	}
+	variant.UpdatedAt = time.Now()
+	r.syncStock(variant.ProductID)
+	return copyVariant(variant), nil
+}
+
+func (r memoryVariants) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	variant, ok := r.variants[id]
+	if !ok {
+		return ErrVariantNotFound
+	}
+
+	delete(r.variants, id)
+	for key, item := range r.cart {
+		if item.VariantID != nil && *item.VariantID == id {
+			delete(r.cart, key)
+		}
+	}
+	for _, items := range r.orderItems {
+		for _, item := range items {
+			if item.VariantID != nil && *item.VariantID == id {
+				item.VariantID = nil
+			}
+		}
+	}
+	r.syncStock(variant.ProductID)
+	return nil
+}
+
+type memoryCarts struct {
+	*Memory
+}
+
+// withProduct returns a copy of item with its current product and variant
+// attached. The caller must hold r.mu.
+func (r memoryCarts) withProduct(item *model.CartItem) *model.CartItem {
+	c := *item
+	c.Product = copyProduct(r.products[item.ProductID])
+	if item.VariantID != nil {
+		variantID := *item.VariantID
+		c.VariantID = &variantID
+		c.Variant = copyVariant(r.variants[variantID])
+	}
+	markAvailable(&c)
+	return &c
+}
+
+func (r memoryCarts) List(ctx context.Context, userID int) ([]*model.CartItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := []*model.CartItem{}
+	for _, item := range r.cart {
+		if item.UserID == userID {
+			items = append(items, r.withProduct(item))
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items, nil
+}
+
+// cartVariant returns the variant of a product being added to a cart, or nil
+// for products not sold in variants. The caller must hold r.mu.
+func (r memoryCarts) cartVariant(productID int, variantID *int) (*model.ProductVariant, error) {
+	if variantID == nil {
+		if len(r.productVariants(productID)) > 0 {
+			return nil, ErrVariantRequired
+		}
+		return nil, nil
+	}
+	variant, ok := r.variants[*variantID]
+	if !ok || variant.ProductID != productID || !variant.IsActive {
+		return nil, ErrVariantNotFound
+	}
+	return variant, nil
+}
+
+// sameVariant reports whether two optional variant IDs are equal
+func sameVariant(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func (r memoryCarts) Add(ctx context.Context, userID, productID int, variantID *int, quantity int) (*model.CartItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	product, err := r.activeProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+	variant, err := r.cartVariant(productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+	stock := product.StockQuantity
+	if variant != nil {
+		stock = variant.StockQuantity
+	}
+	if stock < quantity {
+		return nil, ErrInsufficientStock
+	}
+
+	for _, item := range r.cart {
+		if item.UserID == userID && item.ProductID == productID && sameVariant(item.VariantID, variantID) {
+			item.Quantity += quantity
+			item.UpdatedAt = time.Now()
+			return r.withProduct(item), nil
+		}
+	}
+
+	now := time.Now()
+	item := &model.CartItem{ID: r.newID(), UserID: userID, ProductID: productID, Quantity: quantity, CreatedAt: now, UpdatedAt: now}
+	if variant != nil {
+		variantID := variant.ID
+		item.VariantID = &variantID
+	}
+	r.cart[item.ID] = item
+	return r.withProduct(item), nil
+}
+
+func (r memoryCarts) SetQuantity(ctx context.Context, userID, id, quantity int) (*model.CartItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.cart[id]
+	if !ok || item.UserID != userID {
+		return nil, ErrCartItemNotFound
+	}
+	product, err := r.activeProduct(item.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	stock := product.StockQuantity
+	if item.VariantID != nil {
+		variant, err := r.cartVariant(item.ProductID, item.VariantID)
+		if err != nil {
+			return nil, err
+		}
+		stock = variant.StockQuantity
+	}
+	if stock < quantity {
+		return nil, ErrInsufficientStock
+	}
+	item.Quantity = quantity
+	item.UpdatedAt = time.Now()
+	return r.withProduct(item), nil
+}
+
+func (r memoryCarts) Remove(ctx context.Context, userID, id int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.cart[id]
+	if !ok || item.UserID != userID {
+		return false, nil
+	}
+	delete(r.cart, id)
+	return true, nil
+}
+
+func (r memoryCarts) Clear(ctx context.Context, userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, item := range r.cart {
+		if item.UserID == userID {
+			delete(r.cart, id)
+		}
+	}
+	return nil
+}
+
+type memoryWishlists struct {
+	*Memory
+}
+
+// userItems returns copies of a user's wishlist items with their products.
+// The caller must hold r.mu.
+func (r memoryWishlists) userItems(userID int) []*model.WishlistItem {
+	items := []*model.WishlistItem{}
+	for _, item := range r.wishlist {
+		if item.UserID == userID {
+			c := *item
+			c.Product = copyProduct(r.products[item.ProductID])
+			items = append(items, &c)
+		}
+	}
+	return items
+}
+
+func (r memoryWishlists) List(ctx context.Context, userID int) ([]*model.WishlistItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := r.userItems(userID)
+	sort.Slice(items, func(i, j int) bool { return items[i].ID > items[j].ID })
+	return items, nil
+}
+
+func (r memoryWishlists) ListPage(ctx context.Context, userID int, page Page) (*model.WishlistConnection, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := r.userItems(userID)
+	total := len(items)
+
+	items, cursors, info, err := memoryPage(items, "newest", true,
+		func(item *model.WishlistItem) float64 { return float64(item.CreatedAt.UnixNano()) },
+		func(item *model.WishlistItem) int { return item.ID }, page)
+	if err != nil {
+		return nil, err
+	}
+	connection := &model.WishlistConnection{Edges: []*model.WishlistItemEdge{}, PageInfo: info, TotalCount: total}
+	for i, item := range items {
+		connection.Edges = append(connection.Edges, &model.WishlistItemEdge{Cursor: cursors[i], Node: item})
+	}
+	return connection, nil
+}
+
+func (r memoryWishlists) Add(ctx context.Context, userID, productID int) (*model.WishlistItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	product, err := r.activeProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var item *model.WishlistItem
+	for _, existing := range r.wishlist {
+		if existing.UserID == userID && existing.ProductID == productID {
+			item = existing
+		}
+	}
+	if item == nil {
+		item = &model.WishlistItem{ID: r.newID(), UserID: userID, ProductID: productID, CreatedAt: time.Now()}
+		r.wishlist[item.ID] = item
+	}
+
+	c := *item
+	c.Product = copyProduct(product)
+	return &c, nil
+}
+
+func (r memoryWishlists) Remove(ctx context.Context, userID, productID int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, item := range r.wishlist {
+		if item.UserID == userID && item.ProductID == productID {
+			delete(r.wishlist, id)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type memoryOrders struct {
+	*Memory
+}
+
+func (r memoryOrders) GetByID(ctx context.Context, id int) (*model.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, ErrOrderNotFound
+	}
+	return copyOrder(order), nil
+}
+
+func (r memoryOrders) ListForUser(ctx context.Context, userID int) ([]*model.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	orders := []*model.Order{}
+	for _, order := range r.orders {
+		if order.UserID == userID {
+			orders = append(orders, copyOrder(order))
+		}
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].ID > orders[j].ID })
+	return orders, nil
+}
+
+func (r memoryOrders) ListPageForUser(ctx context.Context, userID int, page Page) (*model.OrderConnection, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	orders := []*model.Order{}
+	for _, order := range r.orders {
+		if order.UserID == userID {
+			orders = append(orders, copyOrder(order))
+		}
+	}
+	total := len(orders)
+
+	orders, cursors, info, err := memoryPage(orders, "newest", true,
+		func(order *model.Order) float64 { return float64(order.CreatedAt.UnixNano()) },
+		func(order *model.Order) int { return order.ID }, page)
+	if err != nil {
+		return nil, err
+	}
+	connection := &model.OrderConnection{Edges: []*model.OrderEdge{}, PageInfo: info, TotalCount: total}
+	for i, order := range orders {
+		connection.Edges = append(connection.Edges, &model.OrderEdge{Cursor: cursors[i], Node: order})
+	}
+	return connection, nil
+}
+
+func (r memoryOrders) Items(ctx context.Context, orderID int) ([]*model.OrderItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	items := []*model.OrderItem{}
+	for _, item := range r.orderItems[orderID] {
+		c := *item
+		if item.VariantID != nil {
+			variantID := *item.VariantID
+			c.VariantID = &variantID
+		}
+		items = append(items, &c)
+	}
+	return items, nil
+}
+
+func (r memoryOrders) CreateFromCart(ctx context.Context, newOrder NewOrder) (*model.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var cart []*model.CartItem
+	for _, item := range r.cart {
+		if item.UserID == newOrder.UserID {
+			cart = append(cart, item)
+		}
+	}
+	if len(cart) == 0 {
+		return nil, ErrCartEmpty
+	}
+	sort.Slice(cart, func(i, j int) bool { return cart[i].ID < cart[j].ID })
+
+	// Check every item before changing any stock, as the Postgres transaction
+	// rolls back
+	for _, cartItem := range cart {
+		if !(memoryCarts{r.Memory}).withProduct(cartItem).Available {
+			return nil, ErrCartUnavailable
+		}
+	}
+	for _, cartItem := range cart {
+		stock := r.products[cartItem.ProductID].StockQuantity
+		if cartItem.VariantID != nil {
+			stock = r.variants[*cartItem.VariantID].StockQuantity
+		}
+		if stock < cartItem.Quantity {
+			return nil, ErrInsufficientStock
+		}
+	}
+
+	now := time.Now()
+	order := &model.Order{
+		ID:              r.newID(),
+		UserID:          newOrder.UserID,
+		OrderNumber:     newOrder.OrderNumber,
+		Status:          "pending",
+		ShippingAddress: newOrder.ShippingAddress,
+		ShippingCity:    newOrder.ShippingCity,
+		ShippingCountry: "Saudi Arabia",
+		ShippingPhone:   newOrder.ShippingPhone,
+		PaymentMethod:   newOrder.PaymentMethod,
+		PaymentStatus:   "pending",
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if newOrder.Notes != nil {
+		order.Notes = *newOrder.Notes
+	}
+
+	var items []*model.OrderItem
+	for _, cartItem := range cart {
+		product := r.products[cartItem.ProductID]
+		item := &model.OrderItem{
+			ID:           r.newID(),
+			OrderID:      order.ID,
+			ProductID:    product.ID,
+			ProductName:  product.Name,
+			ProductPrice: product.Price,
+			Quantity:     cartItem.Quantity,
+			CreatedAt:    now,
+		}
+		if cartItem.VariantID != nil {
+			variant := r.variants[*cartItem.VariantID]
+			variantID := variant.ID
+			item.VariantID = &variantID
+			item.VariantName = variantName(variant.Options)
+			item.ProductPrice = variant.Price
+			variant.StockQuantity -= item.Quantity
+			r.syncStock(product.ID)
+		} else {
+			product.StockQuantity -= item.Quantity
+		}
+		item.TotalPrice = item.ProductPrice * float64(item.Quantity)
+		order.TotalAmount += item.TotalPrice
+		items = append(items, item)
+		delete(r.cart, cartItem.ID)
+	}
+
+	r.orders[order.ID] = order
+	r.orderItems[order.ID] = items
+	return copyOrder(order), nil
+}
+
+func (r memoryOrders) UpdateStatus(ctx context.Context, id int, status string) (*model.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, ErrOrderNotFound
+	}
+	order.Status = status
+	order.UpdatedAt = time.Now()
+	return copyOrder(order), nil
+}
+
+type memoryReviews struct {
+	*Memory
+}
+
+// view returns a copy of review with its author and likes as seen by viewerID.
+// The caller must hold r.mu.
+func (r memoryReviews) view(viewerID int, review *model.Review) *model.Review {
+	c := *review
+	if author, ok := r.users[review.UserID]; ok {
+		c.User = copyUser(author)
+	}
+	c.LikeCount = 0
+	for key := range r.reviewLikes {
+		if key[1] == review.ID {
+			c.LikeCount++
+		}
+	}
+	c.IsLiked = r.reviewLikes[[2]int{viewerID, review.ID}]
+	return &c
+}
+
+func (r memoryReviews) GetByID(ctx context.Context, viewerID, id int) (*model.Review, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	review, ok := r.reviews[id]
+	if !ok {
+		return nil, ErrReviewNotFound
+	}
+	return r.view(viewerID, review), nil
+}
+
+// matching returns the reviews that match filter as seen by viewerID. The
+// caller must hold r.mu.
+func (r memoryReviews) matching(viewerID int, filter ReviewFilter) []*model.Review {
+	reviews := []*model.Review{}
+	for _, review := range r.reviews {
+		if filter.ProductID != 0 && review.ProductID != filter.ProductID {
+			continue
+		}
+		if filter.UserID != 0 && review.UserID != filter.UserID {
+			continue
+		}
+		reviews = append(reviews, r.view(viewerID, review))
+	}
+	return reviews
+}
+
+func (r memoryReviews) List(ctx context.Context, viewerID int, filter ReviewFilter) ([]*model.Review, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reviews := r.matching(viewerID, filter)
+	sort.Slice(reviews, func(i, j int) bool { return reviews[i].ID > reviews[j].ID })
+	return reviews, nil
+}
+
+func (r memoryReviews) ListPage(ctx context.Context, viewerID int, filter ReviewFilter, page Page) (*model.ReviewConnection, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reviews := r.matching(viewerID, filter)
+	total := len(reviews)
+
+	reviews, cursors, info, err := memoryPage(reviews, "newest", true,
+		func(review *model.Review) float64 { return float64(review.CreatedAt.UnixNano()) },
+		func(review *model.Review) int { return review.ID }, page)
+	if err != nil {
+		return nil, err
+	}
+	connection := &model.ReviewConnection{Edges: []*model.ReviewEdge{}, PageInfo: info, TotalCount: total}
+	for i, review := range reviews {
+		connection.Edges = append(connection.Edges, &model.ReviewEdge{Cursor: cursors[i], Node: review})
+	}
+	return connection, nil
+}
+
+func (r memoryReviews) Create(ctx context.Context, newReview NewReview) (*model.Review, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.products[newReview.ProductID]; !ok {
+		return nil, ErrProductNotFound
+	}
+
+	verified := false
+	for orderID, order := range r.orders {
+		if order.UserID != newReview.UserID || order.Status != "delivered" {
+			continue
+		}
+		for _, item := range r.orderItems[orderID] {
+			if item.ProductID == newReview.ProductID {
+				verified = true
+			}
+		}
+	}
+
+	now := time.Now()
+	review := &model.Review{
+		ID:                 r.newID(),
+		UserID:             newReview.UserID,
+		ProductID:          newReview.ProductID,
+		Rating:             newReview.Rating,
+		Title:              newReview.Title,
+		Comment:            newReview.Comment,
+		IsVerifiedPurchase: verified,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	r.reviews[review.ID] = review
+	return r.view(newReview.UserID, review), nil
+}
+
+func (r memoryReviews) Update(ctx context.Context, id, rating int, title, comment string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	review, ok := r.reviews[id]
+	if !ok {
+		return ErrReviewNotFound
+	}
+	review.Rating = rating
+	review.Title = title
+	review.Comment = comment
+	review.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r memoryReviews) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.reviews[id]; !ok {
+		return ErrReviewNotFound
+	}
+	delete(r.reviews, id)
+	for key := range r.reviewLikes {
+		if key[1] == id {
+			delete(r.reviewLikes, key)
+		}
+	}
+	return nil
+}
+
+func (r memoryReviews) SetLiked(ctx context.Context, userID, reviewID int, liked bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := [2]int{userID, reviewID}
+	if !liked {
+		delete(r.reviewLikes, key)
+		return nil
+	}
+	if _, ok := r.reviews[reviewID]; !ok {
+		return ErrReviewNotFound
+	}
+	r.reviewLikes[key] = true
+	return nil
+}
+
+// memoryPage orders nodes by key and then ID the way the Postgres keysets do,
+// descending when desc is set, and returns the requested page. Keys are
+// floats so cursors can carry them as text.
+func memoryPage[T any](nodes []T, sortName string, desc bool, key func(T) float64, id func(T) int, page Page) ([]T, []string, *model.PageInfo, error) {
+	// compare orders a before b in fetch order, which is reversed for backward pages
+	fetchDesc := desc != page.backward()
+	compare := func(aKey float64, aID int, bKey float64, bID int) int {
+		result := cmp.Compare(aKey, bKey)
+		if result == 0 {
+			result = cmp.Compare(aID, bID)
+		}
+		if fetchDesc {
+			return -result
+		}
+		return result
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return compare(key(nodes[i]), id(nodes[i]), key(nodes[j]), id(nodes[j])) < 0
+	})
+
+	if encoded := page.cursor(); encoded != "" {
+		c, err := decodeCursor(encoded, sortName, "numeric")
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		cursorKey, err := strconv.ParseFloat(c.Key, 64)
+		if err != nil {
+			return nil, nil, nil, ErrInvalidCursor
+		}
+		start := sort.Search(len(nodes), func(i int) bool {
+			return compare(key(nodes[i]), id(nodes[i]), cursorKey, c.ID) > 0
+		})
+		nodes = nodes[start:]
+	}
+
+	if len(nodes) > page.size()+1 {
+		nodes = nodes[:page.size()+1]
+	}
+	cursors := make([]string, len(nodes))
+	for i, node := range nodes {
+		cursors[i] = encodeCursor(sortName, strconv.FormatFloat(key(node), 'g', -1, 64), id(node))
+	}
+	nodes, cursors, info := finishPage(page, nodes, cursors)
+	return nodes, cursors, info, nil
+}
+
+type memorySearches struct {
+	*Memory
+}
+
+func (r memorySearches) Record(ctx context.Context, search NewSearch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if search.UserID != nil {
+		userID := *search.UserID
+		search.UserID = &userID
+	}
+	r.searches = append(r.searches, memorySearch{
+		NewSearch:  search,
+		normalized: normalizeQuery(search.Query),
+		createdAt:  time.Now(),
+	})
+	return nil
+}
+
+// searchGroup counts the logged searches with the same normalized query
+type searchGroup struct {
+	stat      *model.SearchStat
+	spellings map[string]int
+	// searchers holds the user IDs and client keys that made the searches
+	searchers map[string]bool
+	rank      float64
+}
+
+// groupSearches groups the logged searches made since the given time that
+// keep returns true for. The caller must hold m.mu.
+func (m *Memory) groupSearches(since time.Time, keep func(search memorySearch) bool) []*searchGroup {
+	groups := map[string]*searchGroup{}
+	var ordered []*searchGroup
+	for _, search := range m.searches {
+		if search.createdAt.Before(since) || !keep(search) {
+			continue
+		}
+		group, ok := groups[search.normalized]
+		if !ok {
+			group = &searchGroup{stat: &model.SearchStat{}, spellings: map[string]int{}, searchers: map[string]bool{}}
+			groups[search.normalized] = group
+			ordered = append(ordered, group)
+		}
+		group.stat.Searches++
+		group.spellings[search.Query]++
+		if search.UserID != nil {
+			group.searchers[strconv.Itoa(*search.UserID)] = true
+		} else if search.ClientKey != "" {
+			group.searchers[search.ClientKey] = true
+		}
+		if search.createdAt.After(group.stat.LastSearchedAt) {
+			group.stat.LastSearchedAt = search.createdAt
+		}
+	}
+
+	// Like the Postgres mode aggregate, show the spelling used most
+	for _, group := range ordered {
+		for spelling, count := range group.spellings {
+			if count > group.spellings[group.stat.Query] ||
+				(count == group.spellings[group.stat.Query] && (group.stat.Query == "" || spelling < group.stat.Query)) {
+				group.stat.Query = spelling
+			}
+		}
+	}
+	return ordered
+}
+
+func (r memorySearches) Suggest(ctx context.Context, prefix string, since time.Time, minSearchers, limit int) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prefix = normalizeQuery(prefix)
+	groups := r.groupSearches(since, func(search memorySearch) bool { return search.Results > 0 })
+
+	var matches []*searchGroup
+	for _, group := range groups {
+		rank, ok := suggestionRank(group.stat.Query, prefix)
+		if ok && len(group.searchers) >= minSearchers {
+			group.rank = rank
+			matches = append(matches, group)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].rank != matches[j].rank {
+			return matches[i].rank > matches[j].rank
+		}
+		return matches[i].stat.Searches > matches[j].stat.Searches
+	})
+
+	queries := []string{}
+	for _, group := range matches[:min(limit, len(matches))] {
+		queries = append(queries, group.stat.Query)
+	}
+	return queries, nil
+}
+
+func (r memorySearches) Popular(ctx context.Context, since time.Time, zeroResults bool, limit int) ([]*model.SearchStat, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	groups := r.groupSearches(since, func(search memorySearch) bool { return !zeroResults || search.Results == 0 })
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i].stat, groups[j].stat
+		if a.Searches != b.Searches {
+			return a.Searches > b.Searches
+		}
+		return a.LastSearchedAt.After(b.LastSearchedAt)
+	})
+
+	stats := []*model.SearchStat{}
+	for _, group := range groups[:min(limit, len(groups))] {
+		stats = append(stats, group.stat)
+	}
+	return stats, nil
+}
@@ -0,0 +1,514 @@
+package repository
+
+import (
+	"ai-catalog/model"
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// memoryIdentity is an identity linked to a user
+type memoryIdentity struct {
+	model.UserIdentity
+	userID int
+}
+
+// memoryTwoFactor is a user's two-factor authentication state. Recovery codes
+// map to whether they have been used.
+type memoryTwoFactor struct {
+	secret        string
+	lastStep      *int64
+	recoveryCodes map[string]bool
+}
+
+type memoryIdentities struct {
+	*Memory
+}
+
+func (r memoryIdentities) Login(ctx context.Context, provider, subject, email string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			identity.Email = email
+			identity.LastLoginAt = time.Now()
+			return identity.userID, nil
+		}
+	}
+	return 0, ErrIdentityNotFound
+}
+
+func (r memoryIdentities) Link(ctx context.Context, newIdentity NewIdentity, account NewUser) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var user *model.User
+	for _, existing := range r.users {
+		if strings.EqualFold(existing.Email, newIdentity.Email) {
+			user = existing
+			break
+		}
+	}
+	if user == nil {
+		created, err := r.createUser(account)
+		if err != nil {
+			return 0, err
+		}
+		user = created
+	} else if !user.EmailVerified {
+		return 0, ErrEmailNotVerified
+	}
+
+	now := time.Now()
+	identity := &memoryIdentity{
+		UserIdentity: model.UserIdentity{
+			ID:          r.newID(),
+			Provider:    newIdentity.Provider,
+			Subject:     newIdentity.Subject,
+			Email:       newIdentity.Email,
+			LastLoginAt: now,
+			CreatedAt:   now,
+		},
+		userID: user.ID,
+	}
+	r.identities[identity.ID] = identity
+	return user.ID, nil
+}
+
+func (r memoryIdentities) ListForUser(ctx context.Context, userID int) ([]*model.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	identities := []*model.UserIdentity{}
+	for _, identity := range r.identities {
+		if identity.userID == userID {
+			c := identity.UserIdentity
+			identities = append(identities, &c)
+		}
+	}
+	sort.Slice(identities, func(i, j int) bool { return identities[i].ID < identities[j].ID })
+	return identities, nil
+}
+
+func (r memoryIdentities) Unlink(ctx context.Context, userID int, provider string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	unlinked := false
+	for id, identity := range r.identities {
+		if identity.userID == userID && identity.Provider == provider {
+			delete(r.identities, id)
+			unlinked = true
+		}
+	}
+	return unlinked, nil
+}
+
+type memoryLoginAttempts struct {
+	*Memory
+}
+
+func (r memoryLoginAttempts) Record(ctx context.Context, attempt NewLoginAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.loginAttempts = append(r.loginAttempts, &model.LoginAttempt{
+		ID:        r.newID(),
+		Email:     attempt.Email,
+		IPAddress: attempt.IPAddress,
+		UserAgent: attempt.UserAgent,
+		UserID:    attempt.UserID,
+		Success:   attempt.Success,
+		Reason:    attempt.Reason,
+		CreatedAt: time.Now(),
+	})
+	return nil
+}
+
+func (r memoryLoginAttempts) AccountFailures(ctx context.Context, email string, reasons []string, window time.Duration) ([]time.Duration, error) {
+	return r.failureAges(func(attempt *model.LoginAttempt) bool { return attempt.Email == email }, true, reasons, window), nil
+}
+
+func (r memoryLoginAttempts) IPFailures(ctx context.Context, ipAddress string, reasons []string, window time.Duration) ([]time.Duration, error) {
+	return r.failureAges(func(attempt *model.LoginAttempt) bool { return attempt.IPAddress == ipAddress }, false, reasons, window), nil
+}
+
+// failureAges returns the ages of the recent failures among the attempts that
+// match, newest first. With sinceSuccess, failures before the last successful
+// matching attempt are left out.
+func (r memoryLoginAttempts) failureAges(matches func(*model.LoginAttempt) bool, sinceSuccess bool, reasons []string, window time.Duration) []time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	var ages []time.Duration
+	// Attempts are stored oldest first
+	for i := len(r.loginAttempts) - 1; i >= 0; i-- {
+		attempt := r.loginAttempts[i]
+		age := now.Sub(attempt.CreatedAt)
+		if age >= window {
+			break
+		}
+		if !matches(attempt) {
+			continue
+		}
+		if sinceSuccess && attempt.Success {
+			break
+		}
+		for _, reason := range reasons {
+			if attempt.Reason == reason {
+				ages = append(ages, age)
+				break
+			}
+		}
+	}
+	return ages
+}
+
+func (r memoryLoginAttempts) List(ctx context.Context, filter LoginAttemptFilter) ([]*model.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempts := []*model.LoginAttempt{}
+	for i := len(r.loginAttempts) - 1; i >= 0 && len(attempts) < filter.Limit; i-- {
+		attempt := r.loginAttempts[i]
+		if (filter.Email != "" && attempt.Email != filter.Email) ||
+			(filter.IPAddress != "" && attempt.IPAddress != filter.IPAddress) ||
+			(filter.Success != nil && attempt.Success != *filter.Success) {
+			continue
+		}
+		c := *attempt
+		attempts = append(attempts, &c)
+	}
+	return attempts, nil
+}
+
+type memoryTwoFactorStore struct {
+	*Memory
+}
+
+// twoFactorState returns the two-factor state of a stored user, creating it
+// when the user has none. The caller must hold m.mu.
+func (m *Memory) twoFactorState(userID int) (*memoryTwoFactor, error) {
+	if _, ok := m.users[userID]; !ok {
+		return nil, ErrUserNotFound
+	}
+	state, ok := m.twoFactor[userID]
+	if !ok {
+		state = &memoryTwoFactor{recoveryCodes: map[string]bool{}}
+		m.twoFactor[userID] = state
+	}
+	return state, nil
+}
+
+func (r memoryTwoFactorStore) Secret(ctx context.Context, userID int) (string, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state, err := r.twoFactorState(userID)
+	if err != nil {
+		return "", false, err
+	}
+	return state.secret, r.users[userID].TwoFactorEnabled, nil
+}
+
+func (r memoryTwoFactorStore) SetPendingSecret(ctx context.Context, userID int, secret string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state, err := r.twoFactorState(userID)
+	if err != nil || r.users[userID].TwoFactorEnabled {
+		return err
+	}
+	state.secret = secret
+	state.lastStep = nil
+	return nil
+}
+
+func (r memoryTwoFactorStore) Enable(ctx context.Context, userID int, step int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state, err := r.twoFactorState(userID)
+	if err != nil {
+		return err
+	}
+	state.lastStep = &step
+	r.users[userID].TwoFactorEnabled = true
+	r.users[userID].UpdatedAt = time.Now()
+	return nil
+}
+
+func (r memoryTwoFactorStore) Disable(ctx context.Context, userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.twoFactorState(userID); err != nil {
+		return err
+	}
+	delete(r.twoFactor, userID)
+	r.users[userID].TwoFactorEnabled = false
+	r.users[userID].UpdatedAt = time.Now()
+	return nil
+}
+
+func (r memoryTwoFactorStore) UseStep(ctx context.Context, userID int, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state, err := r.twoFactorState(userID)
+	if err != nil {
+		return false, err
+	}
+	if state.lastStep != nil && *state.lastStep >= step {
+		return false, nil
+	}
+	state.lastStep = &step
+	return true, nil
+}
+
+func (r memoryTwoFactorStore) ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state, err := r.twoFactorState(userID)
+	if err != nil {
+		return err
+	}
+	state.recoveryCodes = make(map[string]bool, len(codeHashes))
+	for _, codeHash := range codeHashes {
+		state.recoveryCodes[codeHash] = false
+	}
+	return nil
+}
+
+func (r memoryTwoFactorStore) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state, err := r.twoFactorState(userID)
+	if err != nil {
+		return false, err
+	}
+	used, ok := state.recoveryCodes[codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	state.recoveryCodes[codeHash] = true
+	return true, nil
+}
+
+func (r memoryTwoFactorStore) UseChallenge(ctx context.Context, id string, expiresAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for usedID, usedExpiresAt := range r.usedChallenges {
+		if usedExpiresAt.Before(now) {
+			delete(r.usedChallenges, usedID)
+		}
+	}
+	if _, used := r.usedChallenges[id]; used {
+		return false, nil
+	}
+	r.usedChallenges[id] = expiresAt
+	return true, nil
+}
+
+// memorySession is a login session with its refresh token hashes
+type memorySession struct {
+	model.Session
+	refreshTokenHash         string
+	previousRefreshTokenHash string
+	revoked                  bool
+}
+
+// active reports whether the session is neither revoked nor expired
+func (s *memorySession) active() bool {
+	return !s.revoked && s.ExpiresAt.After(time.Now())
+}
+
+type memorySessions struct {
+	*Memory
+}
+
+func (r memorySessions) Create(ctx context.Context, session NewSession) (*model.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	stored := &memorySession{
+		Session: model.Session{
+			ID:         session.ID,
+			UserID:     session.UserID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			ExpiresAt:  now.Add(session.TTL),
+			LastUsedAt: now,
+			CreatedAt:  now,
+		},
+		refreshTokenHash: session.RefreshTokenHash,
+	}
+	r.sessions[session.ID] = stored
+	c := stored.Session
+	return &c, nil
+}
+
+func (r memorySessions) Rotate(ctx context.Context, tokenHash, newTokenHash string, ttl time.Duration) (*model.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, session := range r.sessions {
+		if session.refreshTokenHash == tokenHash && session.active() {
+			now := time.Now()
+			session.previousRefreshTokenHash = session.refreshTokenHash
+			session.refreshTokenHash = newTokenHash
+			session.ExpiresAt = now.Add(ttl)
+			session.LastUsedAt = now
+			c := session.Session
+			return &c, nil
+		}
+	}
+	for _, session := range r.sessions {
+		if session.previousRefreshTokenHash == tokenHash {
+			session.revoked = true
+		}
+	}
+	return nil, ErrSessionNotFound
+}
+
+func (r memorySessions) Revoke(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if session, ok := r.sessions[id]; ok {
+		session.revoked = true
+	}
+	return nil
+}
+
+func (r memorySessions) RevokeAll(ctx context.Context, userID int) error {
+	return r.RevokeOthers(ctx, userID, "")
+}
+
+func (r memorySessions) RevokeOthers(ctx context.Context, userID int, keepID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, session := range r.sessions {
+		if session.UserID == userID && id != keepID {
+			session.revoked = true
+		}
+	}
+	return nil
+}
+
+func (r memorySessions) IsActive(ctx context.Context, id string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	session, ok := r.sessions[id]
+	return ok && session.active(), nil
+}
+
+// memoryAPIKey is an API key with the hash of its secret
+type memoryAPIKey struct {
+	model.APIKey
+	keyHash string
+}
+
+func copyAPIKey(key *model.APIKey) *model.APIKey {
+	c := *key
+	c.Scopes = append([]string(nil), key.Scopes...)
+	return &c
+}
+
+type memoryAPIKeys struct {
+	*Memory
+}
+
+func (r memoryAPIKeys) Create(ctx context.Context, key NewAPIKey) (*model.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := &memoryAPIKey{
+		APIKey: model.APIKey{
+			ID:        r.newID(),
+			UserID:    key.UserID,
+			Name:      key.Name,
+			Prefix:    key.Prefix,
+			Scopes:    append([]string(nil), key.Scopes...),
+			ExpiresAt: key.ExpiresAt,
+			CreatedAt: time.Now(),
+		},
+		keyHash: key.KeyHash,
+	}
+	r.apiKeys[stored.ID] = stored
+	return copyAPIKey(&stored.APIKey), nil
+}
+
+func (r memoryAPIKeys) Authenticate(ctx context.Context, keyHash string, touchInterval time.Duration) (*model.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	for _, key := range r.apiKeys {
+		if key.keyHash != keyHash || key.RevokedAt != nil || (key.ExpiresAt != nil && !key.ExpiresAt.After(now)) {
+			continue
+		}
+		found := copyAPIKey(&key.APIKey)
+		if key.LastUsedAt == nil || key.LastUsedAt.Before(now.Add(-touchInterval)) {
+			key.LastUsedAt = &now
+		}
+		return found, nil
+	}
+	return nil, ErrAPIKeyNotFound
+}
+
+func (r memoryAPIKeys) ListForUser(ctx context.Context, userID int) ([]*model.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys := []*model.APIKey{}
+	for _, key := range r.apiKeys {
+		if key.UserID == userID {
+			keys = append(keys, copyAPIKey(&key.APIKey))
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID > keys[j].ID })
+	return keys, nil
+}
+
+func (r memoryAPIKeys) Revoke(ctx context.Context, id, userID int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key, ok := r.apiKeys[id]
+	if !ok || key.RevokedAt != nil || (userID != 0 && key.UserID != userID) {
+		return false, nil
+	}
+	now := time.Now()
+	key.RevokedAt = &now
+	return true, nil
+}
+
+// memoryUserToken is a single-use token issued to a user
+type memoryUserToken struct {
+	userID    int
+	purpose   string
+	tokenHash string
+	expiresAt time.Time
+	used      bool
+}
+
+type memoryUserTokens struct {
+	*Memory
+}
+
+func (r memoryUserTokens) Create(ctx context.Context, userID int, purpose, tokenHash string, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.userTokens {
+		if token.userID == userID && token.purpose == purpose {
+			token.used = true
+		}
+	}
+	r.userTokens = append(r.userTokens, &memoryUserToken{
+		userID:    userID,
+		purpose:   purpose,
+		tokenHash: tokenHash,
+		expiresAt: time.Now().Add(ttl),
+	})
+	return nil
+}
+
+func (r memoryUserTokens) Consume(ctx context.Context, tokenHash, purpose string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, token := range r.userTokens {
+		if token.tokenHash == tokenHash && token.purpose == purpose && !token.used && token.expiresAt.After(time.Now()) {
+			token.used = true
+			return token.userID, nil
+		}
+	}
+	return 0, ErrUserTokenNotFound
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"ai-catalog/model"
+	"context"
+	"encoding/json"
+	"slices"
+	"sort"
+	"time"
+)
+
+// memoryJob is a queued job with the time its current attempt was claimed
+type memoryJob struct {
+	model.Job
+	lockedAt *time.Time
+}
+
+func copyJob(job *model.Job) *model.Job {
+	c := *job
+	return &c
+}
+
+type memoryJobs struct {
+	*Memory
+}
+
+func (r memoryJobs) Enqueue(ctx context.Context, job NewJob) (*model.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	stored := &memoryJob{Job: model.Job{
+		ID:          r.newID(),
+		Type:        job.Type,
+		Payload:     append(json.RawMessage(nil), job.Payload...),
+		Status:      model.JobPending,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       now,
+		CreatedBy:   job.CreatedBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}}
+	r.jobs[stored.ID] = stored
+	return copyJob(&stored.Job), nil
+}
+
+func (r memoryJobs) GetByID(ctx context.Context, id int) (*model.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return copyJob(&job.Job), nil
+}
+
+func (r memoryJobs) Claim(ctx context.Context, types []string, staleAfter time.Duration) (*model.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+
+	var due []*memoryJob
+	for _, job := range r.jobs {
+		if !slices.Contains(types, job.Type) {
+			continue
+		}
+		pending := job.Status == model.JobPending && !job.RunAt.After(now)
+		stale := job.Status == model.JobRunning && job.lockedAt.Before(now.Add(-staleAfter))
+		switch {
+		case stale && job.Attempts >= job.MaxAttempts:
+			jobErr := ErrJobAbandoned.Error()
+			job.Status = model.JobDead
+			job.Error = &jobErr
+			job.lockedAt = nil
+			job.CompletedAt = &now
+			job.UpdatedAt = now
+		case pending || stale:
+			due = append(due, job)
+		}
+	}
+	if len(due) == 0 {
+		return nil, nil
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].RunAt.Equal(due[j].RunAt) {
+			return due[i].RunAt.Before(due[j].RunAt)
+		}
+		return due[i].ID < due[j].ID
+	})
+
+	job := due[0]
+	job.Status = model.JobRunning
+	job.Attempts++
+	job.lockedAt = &now
+	job.UpdatedAt = now
+	return copyJob(&job.Job), nil
+}
+
+func (r memoryJobs) Complete(ctx context.Context, id int, result json.RawMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if job, ok := r.jobs[id]; ok {
+		now := time.Now()
+		job.Status = model.JobCompleted
+		job.Result = append(json.RawMessage(nil), result...)
+		job.Error = nil
+		job.lockedAt = nil
+		job.CompletedAt = &now
+		job.UpdatedAt = now
+	}
+	return nil
+}
+
+func (r memoryJobs) Reschedule(ctx context.Context, id int, jobErr string, delay time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if job, ok := r.jobs[id]; ok {
+		now := time.Now()
+		job.Status = model.JobPending
+		job.Error = &jobErr
+		job.lockedAt = nil
+		job.RunAt = now.Add(delay)
+		job.UpdatedAt = now
+	}
+	return nil
+}
+
+func (r memoryJobs) DeadLetter(ctx context.Context, id int, jobErr string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if job, ok := r.jobs[id]; ok {
+		now := time.Now()
+		job.Status = model.JobDead
+		job.Error = &jobErr
+		job.lockedAt = nil
+		job.CompletedAt = &now
+		job.UpdatedAt = now
+	}
+	return nil
+}
+
+func (r memoryJobs) Requeue(ctx context.Context, id int) (*model.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok || job.Status != model.JobDead {
+		return nil, ErrJobNotDead
+	}
+	now := time.Now()
+	job.Status = model.JobPending
+	job.Attempts = 0
+	job.RunAt = now
+	job.CompletedAt = nil
+	job.UpdatedAt = now
+	return copyJob(&job.Job), nil
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"ai-catalog/model"
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestMemoryCategoryMovesCannotFormCycles(t *testing.T) {
+	store := NewMemory()
+	categories := store.Repositories().Categories
+	ctx := context.Background()
+	electronics := store.AddCategory(model.Category{Name: "Electronics"})
+	phones := store.AddCategory(model.Category{Name: "Phones", ParentID: &electronics.ID})
+	cases := store.AddCategory(model.Category{Name: "Cases", ParentID: &phones.ID})
+
+	for _, tt := range []struct {
+		name     string
+		id       int
+		parentID int
+	}{
+		{"under itself", phones.ID, phones.ID},
+		{"under its child", phones.ID, cases.ID},
+		{"under a deeper descendant", electronics.ID, cases.ID},
+	} {
+		if _, err := categories.Move(ctx, tt.id, &tt.parentID); err != ErrCategoryCycle {
+			t.Errorf("moving %s: err = %v, want ErrCategoryCycle", tt.name, err)
+		}
+	}
+	if stored, err := categories.GetByID(ctx, electronics.ID); err != nil || stored.ParentID != nil {
+		t.Errorf("electronics = %+v, %v after rejected moves, want it still a root", stored, err)
+	}
+
+	// Moving a subtree elsewhere, or to the root, is fine
+	moved, err := categories.Move(ctx, cases.ID, &electronics.ID)
+	if err != nil || moved.ParentID == nil || *moved.ParentID != electronics.ID {
+		t.Fatalf("moving cases under electronics = %+v, %v", moved, err)
+	}
+	if moved, err := categories.Move(ctx, phones.ID, nil); err != nil || moved.ParentID != nil {
+		t.Errorf("moving phones to the root = %+v, %v", moved, err)
+	}
+	missing := cases.ID + 100
+	if _, err := categories.Move(ctx, phones.ID, &missing); err != ErrCategoryNotFound {
+		t.Errorf("moving under a missing category: err = %v, want ErrCategoryNotFound", err)
+	}
+}
+
+func TestMemoryProductsSortByTheirLowestPrice(t *testing.T) {
+	store := NewMemory()
+	repos := store.Repositories()
+	ctx := context.Background()
+	lamp := store.AddProduct(model.Product{Name: "Lamp", Price: 50, IsActive: true})
+	shirt := store.AddProduct(model.Product{Name: "Shirt", Price: 100, IsActive: true})
+	mug := store.AddProduct(model.Product{Name: "Mug", Price: 30, IsActive: true})
+	for size, price := range map[string]float64{"L": 120, "S": 20} {
+		_, err := repos.Variants.Create(ctx, NewVariant{
+			ProductID: shirt.ID, SKU: "SHIRT-" + size, Price: price,
+			Options: []*model.SelectedOption{{Name: "Size", Value: size}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Pages of one product each must follow the same order as the sort
+	pageThrough := func(sortBy ProductSort) []int {
+		var ids []int
+		page := Page{First: 1}
+		for {
+			connection, err := repos.Products.ListPage(ctx, ProductFilter{}, sortBy, page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, edge := range connection.Edges {
+				ids = append(ids, edge.Node.ID)
+			}
+			if !connection.PageInfo.HasNextPage {
+				return ids
+			}
+			page.After = *connection.PageInfo.EndCursor
+		}
+	}
+
+	for sortBy, want := range map[ProductSort][]int{
+		SortPriceAsc:  {shirt.ID, mug.ID, lamp.ID},
+		SortPriceDesc: {lamp.ID, mug.ID, shirt.ID},
+	} {
+		if got := pageThrough(sortBy); !slices.Equal(got, want) {
+			t.Errorf("%s: got products %v, want %v", sortBy, got, want)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"ai-catalog/model"
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// memoryTranslation is a translation memory entry with the hash of its source
+type memoryTranslation struct {
+	model.TranslationMemoryEntry
+	sourceHash string
+}
+
+type memoryTranslations struct {
+	*Memory
+}
+
+// texts returns the stored translations of one kind into locale, keyed by ID.
+// The caller must hold m.mu.
+func texts(byLocale map[string]map[int]*Translation, locale string) map[int]*Translation {
+	translations, ok := byLocale[locale]
+	if !ok {
+		translations = map[int]*Translation{}
+		byLocale[locale] = translations
+	}
+	return translations
+}
+
+// findTranslations copies the translations of the given IDs. The caller must
+// hold m.mu.
+func findTranslations(translations map[int]*Translation, ids []int) map[int]*Translation {
+	found := map[int]*Translation{}
+	for _, id := range ids {
+		if t, ok := translations[id]; ok {
+			c := *t
+			found[id] = &c
+		}
+	}
+	return found
+}
+
+func (r memoryTranslations) Products(ctx context.Context, locale string, ids []int) (map[int]*Translation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return findTranslations(texts(r.productTexts, locale), ids), nil
+}
+
+func (r memoryTranslations) Categories(ctx context.Context, locale string, ids []int) (map[int]*Translation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return findTranslations(texts(r.categoryTexts, locale), ids), nil
+}
+
+func (r memoryTranslations) SetProduct(ctx context.Context, locale string, translation Translation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.products[translation.ID]; !ok {
+		return ErrProductNotFound
+	}
+	texts(r.productTexts, locale)[translation.ID] = trimTranslation(translation)
+	return nil
+}
+
+func (r memoryTranslations) SetCategory(ctx context.Context, locale string, translation Translation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.categories[translation.ID]; !ok {
+		return ErrCategoryNotFound
+	}
+	translation.ShortDescription = ""
+	texts(r.categoryTexts, locale)[translation.ID] = trimTranslation(translation)
+	return nil
+}
+
+// trimTranslation trims the descriptions of a translation being stored, which
+// are empty when they fall back to the default locale
+func trimTranslation(translation Translation) *Translation {
+	translation.Description = strings.TrimSpace(translation.Description)
+	translation.ShortDescription = strings.TrimSpace(translation.ShortDescription)
+	return &translation
+}
+
+// untranslated returns the default locale text of an entry that its
+// translation, which may be nil, lacks. ok is false when nothing is missing.
+func untranslated(source Translation, translation *Translation) (Translation, bool) {
+	if translation == nil {
+		return source, true
+	}
+	missing := Translation{ID: source.ID}
+	if translation.Description == "" {
+		missing.Description = source.Description
+	}
+	if translation.ShortDescription == "" {
+		missing.ShortDescription = source.ShortDescription
+	}
+	return missing, missing.Description != "" || missing.ShortDescription != ""
+}
+
+func (r memoryTranslations) UntranslatedProducts(ctx context.Context, locale string) ([]*Translation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	translations := texts(r.productTexts, locale)
+	var entries []*Translation
+	for id, product := range r.products {
+		source := Translation{ID: id, Name: product.Name, Description: product.Description, ShortDescription: product.ShortDescription}
+		if missing, ok := untranslated(source, translations[id]); ok {
+			entries = append(entries, &missing)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries, nil
+}
+
+func (r memoryTranslations) UntranslatedCategories(ctx context.Context, locale string) ([]*Translation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	translations := texts(r.categoryTexts, locale)
+	var entries []*Translation
+	for id, category := range r.categories {
+		source := Translation{ID: id, Name: category.Name, Description: category.Description}
+		if missing, ok := untranslated(source, translations[id]); ok {
+			entries = append(entries, &missing)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries, nil
+}
+
+// fill adds the translated fields of translation to the stored one, keeping the
+// fields that are already translated. The caller must hold m.mu.
+func fill(translations map[int]*Translation, translation Translation) {
+	stored, ok := translations[translation.ID]
+	if !ok {
+		translations[translation.ID] = trimTranslation(translation)
+		return
+	}
+	added := trimTranslation(translation)
+	if stored.Description == "" {
+		stored.Description = added.Description
+	}
+	if stored.ShortDescription == "" {
+		stored.ShortDescription = added.ShortDescription
+	}
+}
+
+func (r memoryTranslations) FillProduct(ctx context.Context, locale string, translation Translation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fill(texts(r.productTexts, locale), translation)
+	return nil
+}
+
+func (r memoryTranslations) FillCategory(ctx context.Context, locale string, translation Translation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	translation.ShortDescription = ""
+	fill(texts(r.categoryTexts, locale), translation)
+	return nil
+}
+
+type memoryTranslationMemory struct {
+	*Memory
+}
+
+// findTranslation returns the entry with the same source, languages and model.
+// The caller must hold m.mu.
+func (m *Memory) findTranslation(entry NewTranslationMemoryEntry) *memoryTranslation {
+	for _, stored := range m.translationMemory {
+		if stored.sourceHash == entry.SourceHash && stored.SourceLang == entry.SourceLang &&
+			stored.TargetLang == entry.TargetLang && stored.Model == entry.Model {
+			return stored
+		}
+	}
+	return nil
+}
+
+func (r memoryTranslationMemory) Lookup(ctx context.Context, sourceHash, sourceLang, targetLang, model string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found *memoryTranslation
+	for _, stored := range r.translationMemory {
+		if stored.sourceHash != sourceHash || stored.SourceLang != sourceLang || stored.TargetLang != targetLang {
+			continue
+		}
+		if stored.IsOverride || (stored.Model == model && found == nil) {
+			found = stored
+		}
+	}
+	if found == nil {
+		return "", ErrTranslationNotFound
+	}
+	now := time.Now()
+	found.HitCount++
+	found.LastUsedAt = &now
+	return found.TranslatedText, nil
+}
+
+// storeTranslation creates or updates the entry for a translation and applies
+// change to it. The caller must hold m.mu.
+func (m *Memory) storeTranslation(entry NewTranslationMemoryEntry, change func(stored *memoryTranslation)) *memoryTranslation {
+	now := time.Now()
+	stored := m.findTranslation(entry)
+	if stored == nil {
+		stored = &memoryTranslation{
+			TranslationMemoryEntry: model.TranslationMemoryEntry{
+				ID:         m.newID(),
+				SourceText: entry.SourceText,
+				SourceLang: entry.SourceLang,
+				TargetLang: entry.TargetLang,
+				Model:      entry.Model,
+				CreatedAt:  now,
+			},
+			sourceHash: entry.SourceHash,
+		}
+		m.translationMemory[stored.ID] = stored
+	}
+	stored.TranslatedText = entry.TranslatedText
+	stored.UpdatedAt = now
+	change(stored)
+	return stored
+}
+
+func (r memoryTranslationMemory) Remember(ctx context.Context, entry NewTranslationMemoryEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.storeTranslation(entry, func(stored *memoryTranslation) {
+		if stored.LastUsedAt == nil {
+			lastUsedAt := stored.UpdatedAt
+			stored.LastUsedAt = &lastUsedAt
+		}
+	})
+	return nil
+}
+
+func (r memoryTranslationMemory) SetOverride(ctx context.Context, entry NewTranslationMemoryEntry, userID int) (*model.TranslationMemoryEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry.Model = ""
+	stored := r.storeTranslation(entry, func(stored *memoryTranslation) {
+		stored.IsOverride = true
+		stored.UpdatedBy = &userID
+	})
+	c := stored.TranslationMemoryEntry
+	return &c, nil
+}
+
+func (r memoryTranslationMemory) Delete(ctx context.Context, id int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.translationMemory[id]; !ok {
+		return false, nil
+	}
+	delete(r.translationMemory, id)
+	return true, nil
+}
+
+func (r memoryTranslationMemory) List(ctx context.Context, filter TranslationMemoryFilter) ([]*model.TranslationMemoryEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries := []*model.TranslationMemoryEntry{}
+	for _, stored := range r.translationMemory {
+		if filter.Search != "" && !containsFold(stored.SourceText, filter.Search) && !containsFold(stored.TranslatedText, filter.Search) {
+			continue
+		}
+		if (filter.SourceLang != "" && stored.SourceLang != filter.SourceLang) ||
+			(filter.TargetLang != "" && stored.TargetLang != filter.TargetLang) ||
+			(filter.OverridesOnly && !stored.IsOverride) {
+			continue
+		}
+		c := stored.TranslationMemoryEntry
+		entries = append(entries, &c)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.IsOverride != b.IsOverride {
+			return a.IsOverride
+		}
+		if a.HitCount != b.HitCount {
+			return a.HitCount > b.HitCount
+		}
+		return a.ID > b.ID
+	})
+	if len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+	return entries, nil
+}
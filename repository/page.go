@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"ai-catalog/model"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidCursor is returned for cursors that are malformed or were issued
+// for a listing in another order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Page selects a window of a listing: the First items after the After cursor,
+// or the Last items before the Before cursor. Exactly one of First and Last
+// must be positive; an empty cursor starts at the beginning or the end.
+type Page struct {
+	First  int
+	After  string
+	Last   int
+	Before string
+}
+
+// backward reports whether the page is counted back from the end
+func (p Page) backward() bool {
+	return p.Last > 0
+}
+
+func (p Page) size() int {
+	if p.backward() {
+		return p.Last
+	}
+	return p.First
+}
+
+func (p Page) cursor() string {
+	if p.backward() {
+		return p.Before
+	}
+	return p.After
+}
+
+// ProductSort is the order of a product listing. Every order ends with the
+// product ID so products with equal keys keep a stable position.
+type ProductSort string
+
+const (
+	SortNewest    ProductSort = "newest"
+	SortPriceAsc  ProductSort = "price_asc"
+	SortPriceDesc ProductSort = "price_desc"
+	// SortRating puts the best average review rating first
+	SortRating ProductSort = "rating"
+	// SortPopularity puts the products with the most units ordered first
+	SortPopularity ProductSort = "popularity"
+)
+
+// ProductSorts lists the valid product orders
+var ProductSorts = []ProductSort{SortNewest, SortPriceAsc, SortPriceDesc, SortRating, SortPopularity}
+
+// cursor is the position of a row in a listing: its sort key as text and its ID.
+// Sort names the order the cursor was issued for.
+type cursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   int    `json:"i"`
+}
+
+// encodeCursor returns the opaque cursor clients pass back as after or before
+func encodeCursor(sort, key string, id int) string {
+	raw, _ := json.Marshal(cursor{Sort: sort, Key: key, ID: id})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor parses a cursor issued for a listing in the given order whose
+// sort key has the SQL type keyType. The key is checked here so a tampered
+// cursor is reported as invalid rather than failing the query's cast.
+func decodeCursor(encoded, sort, keyType string) (cursor, error) {
+	var c cursor
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || json.Unmarshal(raw, &c) != nil || c.Sort != sort || !validCursorKey(c.Key, keyType) {
+		return cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// numericText matches the decimal numbers Postgres accepts as numeric; Go
+// would also parse hexadecimal floats, infinities and NaN
+var numericText = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][+-]?[0-9]+)?$`)
+
+// timestampText is the layout of a Postgres timestamp cast to text
+const timestampText = "2006-01-02 15:04:05.999999999"
+
+// validCursorKey reports whether key is the text form of a value of the SQL
+// type keyType
+func validCursorKey(key, keyType string) bool {
+	switch keyType {
+	case "numeric":
+		// Out of range exponents would overflow numeric
+		_, err := strconv.ParseFloat(key, 64)
+		return err == nil && numericText.MatchString(key)
+	case "bigint":
+		_, err := strconv.ParseInt(key, 10, 64)
+		return err == nil
+	case "timestamp":
+		_, err := time.Parse(timestampText, key)
+		return err == nil
+	}
+	return false
+}
+
+// finishPage takes rows fetched for page, at most one more than its size, and
+// returns them in listing order with their cursors and the page info. The
+// extra row only tells that another page follows in the fetch direction.
+func finishPage[T any](page Page, nodes []T, cursors []string) ([]T, []string, *model.PageInfo) {
+	info := &model.PageInfo{}
+	more := len(nodes) > page.size()
+	if more {
+		nodes, cursors = nodes[:page.size()], cursors[:page.size()]
+	}
+
+	// Rows before the cursor exist when a cursor was given; counting them is
+	// not worth a query, as the Relay spec allows
+	if page.backward() {
+		info.HasPreviousPage = more
+		info.HasNextPage = page.Before != ""
+		for i, j := 0, len(nodes)-1; i < j; i, j = i+1, j-1 {
+			nodes[i], nodes[j] = nodes[j], nodes[i]
+			cursors[i], cursors[j] = cursors[j], cursors[i]
+		}
+	} else {
+		info.HasNextPage = more
+		info.HasPreviousPage = page.After != ""
+	}
+
+	if len(cursors) > 0 {
+		info.StartCursor = &cursors[0]
+		info.EndCursor = &cursors[len(cursors)-1]
+	}
+	return nodes, cursors, info
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestDecodeCursorChecksTheKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		keyType string
+		key     string
+		valid   bool
+	}{
+		{"price", "numeric", "19.99", true},
+		{"rating", "numeric", "4.5000000000000000", true},
+		{"float from the memory store", "numeric", "1e+21", true},
+		{"negative", "numeric", "-3", true},
+		{"hexadecimal float", "numeric", "0x1p-2", false},
+		{"NaN", "numeric", "NaN", false},
+		{"infinity", "numeric", "Inf", false},
+		{"overflow", "numeric", "1e999999", false},
+		{"sql", "numeric", "1); DROP TABLE products; --", false},
+		{"empty", "numeric", "", false},
+		{"popularity", "bigint", "42", true},
+		{"fraction", "bigint", "4.2", false},
+		{"bigint overflow", "bigint", "99999999999999999999", false},
+		{"timestamp", "timestamp", "2024-05-01 12:34:56.123456", true},
+		{"whole second", "timestamp", "2024-05-01 12:34:56", true},
+		{"date only", "timestamp", "2024-05-01", false},
+		{"invalid date", "timestamp", "2024-13-01 12:34:56", false},
+		{"unknown type", "text", "anything", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := encodeCursor("newest", tt.key, 7)
+			c, err := decodeCursor(encoded, "newest", tt.keyType)
+			if !tt.valid {
+				if err != ErrInvalidCursor {
+					t.Errorf("decodeCursor with %s key %q: got %+v, %v, want %v", tt.keyType, tt.key, c, err, ErrInvalidCursor)
+				}
+				return
+			}
+			if err != nil || c.Key != tt.key || c.ID != 7 {
+				t.Errorf("decodeCursor with %s key %q: got %+v, %v", tt.keyType, tt.key, c, err)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejectsMalformedCursors(t *testing.T) {
+	for name, encoded := range map[string]string{
+		"not base64":    "!!!",
+		"not json":      base64.RawURLEncoding.EncodeToString([]byte("cursor")),
+		"another order": encodeCursor(string(SortPriceAsc), "10", 1),
+	} {
+		if _, err := decodeCursor(encoded, string(SortPriceDesc), "numeric"); err != ErrInvalidCursor {
+			t.Errorf("%s: got %v, want %v", name, err, ErrInvalidCursor)
+		}
+	}
+}
+
+func TestKeysetRejectsCursorsWithInvalidKeys(t *testing.T) {
+	for sort, keys := range productKeysets {
+		// A key of the wrong type would otherwise reach the query's cast
+		page := Page{First: 10, After: encodeCursor(string(sort), "not a value", 1)}
+		if _, _, _, err := keys.apply(page, nil); err != ErrInvalidCursor {
+			t.Errorf("%s: got %v, want %v", sort, err, ErrInvalidCursor)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"ai-catalog/model"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// NewPostgres returns repositories backed by a Postgres database
+func NewPostgres(db *sql.DB) *Repositories {
+	return &Repositories{
+		Users:      &postgresUsers{db},
+		Categories: &postgresCategories{db},
+		Products:   &postgresProducts{db},
+		Variants:   &postgresVariants{db},
+		Carts:      &postgresCarts{db},
+		Wishlists:  &postgresWishlists{db},
+		Orders:     &postgresOrders{db},
+		Reviews:    &postgresReviews{db},
+		Searches:   &postgresSearches{db},
+
+		Identities:        &postgresIdentities{db},
+		LoginAttempts:     &postgresLoginAttempts{db},
+		TwoFactor:         &postgresTwoFactor{db},
+		Translations:      &postgresTranslations{db},
+		TranslationMemory: &postgresTranslationMemory{db},
+
+		Sessions:   &postgresSessions{db},
+		APIKeys:    &postgresAPIKeys{db},
+		UserTokens: &postgresUserTokens{db},
+		Jobs:       &postgresJobs{db},
+	}
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == "23505"
+}
+
+// requireRow returns notFound when an UPDATE or DELETE matched no rows
+func requireRow(result sql.Result, err error, notFound error) error {
+	if err != nil {
+		return err
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// keyset orders a Postgres listing by a sort key and then by row ID, so every
+// row has a unique position a cursor can point to
+type keyset struct {
+	// sort names the order in cursors
+	sort string
+	// key is the SQL expression sorted on and cast is the type its text form,
+	// stored in cursors, converts back to: numeric, bigint or timestamp, the
+	// types decodeCursor can check
+	key  string
+	cast string
+	desc bool
+	// id is the ID column that breaks ties
+	id string
+}
+
+// column selects the text form of the sort key, which is scanned for the cursor
+func (k keyset) column() string {
+	return "(" + k.key + ")::text"
+}
+
+// apply returns the condition selecting the rows past the page's cursor and the
+// ORDER BY and LIMIT clauses that fetch the page and one more row, appending
+// their parameters to args
+func (k keyset) apply(page Page, args []interface{}) (string, string, []interface{}, error) {
+	// Backward pages are fetched in reverse and put back in order by finishPage
+	desc := k.desc != page.backward()
+	direction, operator := "ASC", ">"
+	if desc {
+		direction, operator = "DESC", "<"
+	}
+
+	condition := "true"
+	if encoded := page.cursor(); encoded != "" {
+		c, err := decodeCursor(encoded, k.sort, k.cast)
+		if err != nil {
+			return "", "", nil, err
+		}
+		args = append(args, c.Key, c.ID)
+		condition = fmt.Sprintf("(%s, %s) %s ($%d::%s, $%d)", k.key, k.id, operator, len(args)-1, k.cast, len(args))
+	}
+
+	args = append(args, page.size()+1)
+	order := fmt.Sprintf(" ORDER BY %s %s, %s %s LIMIT $%d", k.key, direction, k.id, direction, len(args))
+	return condition, order, args, nil
+}
+
+// newestFirst orders rows of table t by creation time, newest first
+func newestFirst(t string) keyset {
+	return keyset{sort: "newest", key: t + ".created_at", cast: "timestamp", desc: true, id: t + ".id"}
+}
+
+// userColumns selects a user row of table u in the order scanUser expects
+const userColumns = `u.id, u.email, u.first_name, u.last_name, COALESCE(u.phone, ''), COALESCE(u.address, ''),
+	COALESCE(u.city, ''), COALESCE(u.country, ''), u.role, u.email_verified, u.status, u.two_factor_enabled,
+	u.two_factor_required, u.created_at, u.updated_at`
+
+// scanUser scans a row selected with userColumns
+func scanUser(row scanner) (*model.User, error) {
+	user := &model.User{}
+	err := row.Scan(&user.ID, &user.Email, &user.FirstName, &user.LastName, &user.Phone, &user.Address,
+		&user.City, &user.Country, &user.Role, &user.EmailVerified, &user.Status, &user.TwoFactorEnabled,
+		&user.TwoFactorRequired, &user.CreatedAt, &user.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+type postgresUsers struct {
+	db *sql.DB
+}
+
+func (r *postgresUsers) get(ctx context.Context, where string, arg interface{}) (*model.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users u WHERE "+where, arg))
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
+
+func (r *postgresUsers) GetByID(ctx context.Context, id int) (*model.User, error) {
+	return r.get(ctx, "u.id = $1", id)
+}
+
+func (r *postgresUsers) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	return r.get(ctx, "u.email = $1", email)
+}
+
+func (r *postgresUsers) Create(ctx context.Context, user NewUser) (*model.User, error) {
+	created, err := scanUser(r.db.QueryRowContext(ctx, `
+		INSERT INTO users AS u (email, password_hash, first_name, last_name, phone, address, city, email_verified)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+userColumns,
+		user.Email, user.PasswordHash, user.FirstName, user.LastName, user.Phone, user.Address, user.City, user.EmailVerified))
+	if isUniqueViolation(err) {
+		return nil, ErrEmailTaken
+	}
+	return created, err
+}
+
+func (r *postgresUsers) PasswordHash(ctx context.Context, id int) (string, error) {
+	var passwordHash string
+	err := r.db.QueryRowContext(ctx, "SELECT password_hash FROM users WHERE id = $1", id).Scan(&passwordHash)
+	if err == sql.ErrNoRows {
+		return "", ErrUserNotFound
+	}
+	return passwordHash, err
+}
+
+func (r *postgresUsers) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE users SET password_hash = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1", id, passwordHash)
+	return requireRow(result, err, ErrUserNotFound)
+}
+
+func (r *postgresUsers) MarkEmailVerified(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE users SET email_verified = true, updated_at = CURRENT_TIMESTAMP WHERE id = $1", id)
+	return requireRow(result, err, ErrUserNotFound)
+}
+
+func (r *postgresUsers) UpdateProfile(ctx context.Context, id int, update ProfileUpdate) (*model.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, `
+		UPDATE users AS u SET first_name = $2, last_name = $3, phone = COALESCE($4, phone),
+			address = COALESCE($5, address), city = COALESCE($6, city), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING `+userColumns,
+		id, update.FirstName, update.LastName, update.Phone, update.Address, update.City))
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
+
+func (r *postgresUsers) SetRole(ctx context.Context, id int, role string) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE users SET role = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1", id, role)
+	return requireRow(result, err, ErrUserNotFound)
+}
+
+func (r *postgresUsers) SetStatus(ctx context.Context, id int, status string) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE users SET status = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1", id, status)
+	return requireRow(result, err, ErrUserNotFound)
+}
+
+func (r *postgresUsers) SetTwoFactorRequired(ctx context.Context, id int, required bool) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE users SET two_factor_required = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1", id, required)
+	return requireRow(result, err, ErrUserNotFound)
+}
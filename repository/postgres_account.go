@@ -0,0 +1,501 @@
+package repository
+
+import (
+	"ai-catalog/model"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type postgresIdentities struct {
+	db *sql.DB
+}
+
+func (r *postgresIdentities) Login(ctx context.Context, provider, subject, email string) (int, error) {
+	var userID int
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE user_identities SET email = $3, last_login_at = CURRENT_TIMESTAMP
+		WHERE provider = $1 AND subject = $2
+		RETURNING user_id
+	`, provider, subject, email).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return 0, ErrIdentityNotFound
+	}
+	return userID, err
+}
+
+func (r *postgresIdentities) Link(ctx context.Context, identity NewIdentity, account NewUser) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var userID int
+	var emailVerified bool
+	err = tx.QueryRowContext(ctx, "SELECT id, email_verified FROM users WHERE LOWER(email) = LOWER($1)", identity.Email).
+		Scan(&userID, &emailVerified)
+	switch {
+	case err == sql.ErrNoRows:
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO users (email, password_hash, first_name, last_name, email_verified)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id
+		`, account.Email, account.PasswordHash, account.FirstName, account.LastName, account.EmailVerified).Scan(&userID)
+		if isUniqueViolation(err) {
+			return 0, ErrEmailTaken
+		}
+		if err != nil {
+			return 0, err
+		}
+	case err != nil:
+		return 0, err
+	case !emailVerified:
+		return 0, ErrEmailNotVerified
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO user_identities (user_id, provider, subject, email, last_login_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+	`, userID, identity.Provider, identity.Subject, identity.Email)
+	if err != nil {
+		return 0, err
+	}
+	return userID, tx.Commit()
+}
+
+func (r *postgresIdentities) ListForUser(ctx context.Context, userID int) ([]*model.UserIdentity, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, provider, subject, email, last_login_at, created_at
+		FROM user_identities WHERE user_id = $1
+		ORDER BY created_at
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := []*model.UserIdentity{}
+	for rows.Next() {
+		identity := &model.UserIdentity{}
+		err := rows.Scan(&identity.ID, &identity.Provider, &identity.Subject, &identity.Email, &identity.LastLoginAt, &identity.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+	return identities, rows.Err()
+}
+
+func (r *postgresIdentities) Unlink(ctx context.Context, userID int, provider string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM user_identities WHERE user_id = $1 AND provider = $2", userID, provider)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
+
+type postgresLoginAttempts struct {
+	db *sql.DB
+}
+
+func (r *postgresLoginAttempts) Record(ctx context.Context, attempt NewLoginAttempt) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO login_attempts (email, ip_address, user_agent, user_id, success, reason)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, attempt.Email, attempt.IPAddress, attempt.UserAgent, attempt.UserID, attempt.Success, attempt.Reason)
+	return err
+}
+
+func (r *postgresLoginAttempts) AccountFailures(ctx context.Context, email string, reasons []string, window time.Duration) ([]time.Duration, error) {
+	return r.failureAges(ctx, `
+		SELECT EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - created_at))
+		FROM login_attempts
+		WHERE email = $1 AND reason = ANY($2)
+			AND created_at > CURRENT_TIMESTAMP - $3 * INTERVAL '1 second'
+			AND created_at > COALESCE(
+				(SELECT MAX(created_at) FROM login_attempts WHERE email = $1 AND success),
+				'-infinity'::timestamp
+			)
+		ORDER BY created_at DESC
+	`, email, reasons, window)
+}
+
+func (r *postgresLoginAttempts) IPFailures(ctx context.Context, ipAddress string, reasons []string, window time.Duration) ([]time.Duration, error) {
+	return r.failureAges(ctx, `
+		SELECT EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - created_at))
+		FROM login_attempts
+		WHERE ip_address = $1 AND reason = ANY($2)
+			AND created_at > CURRENT_TIMESTAMP - $3 * INTERVAL '1 second'
+		ORDER BY created_at DESC
+	`, ipAddress, reasons, window)
+}
+
+// failureAges runs a query selecting the age in seconds of failed attempts.
+// Ages are computed by the database so they do not depend on the server clock.
+func (r *postgresLoginAttempts) failureAges(ctx context.Context, query, key string, reasons []string, window time.Duration) ([]time.Duration, error) {
+	rows, err := r.db.QueryContext(ctx, query, key, pq.Array(reasons), int64(window.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ages []time.Duration
+	for rows.Next() {
+		var seconds float64
+		if err := rows.Scan(&seconds); err != nil {
+			return nil, err
+		}
+		ages = append(ages, time.Duration(seconds*float64(time.Second)))
+	}
+	return ages, rows.Err()
+}
+
+func (r *postgresLoginAttempts) List(ctx context.Context, filter LoginAttemptFilter) ([]*model.LoginAttempt, error) {
+	query := "SELECT id, email, ip_address, COALESCE(user_agent, ''), user_id, success, reason, created_at FROM login_attempts WHERE 1=1"
+	var args []interface{}
+
+	if filter.Email != "" {
+		args = append(args, filter.Email)
+		query += fmt.Sprintf(" AND email = $%d", len(args))
+	}
+	if filter.IPAddress != "" {
+		args = append(args, filter.IPAddress)
+		query += fmt.Sprintf(" AND ip_address = $%d", len(args))
+	}
+	if filter.Success != nil {
+		args = append(args, *filter.Success)
+		query += fmt.Sprintf(" AND success = $%d", len(args))
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := []*model.LoginAttempt{}
+	for rows.Next() {
+		var a model.LoginAttempt
+		err := rows.Scan(&a.ID, &a.Email, &a.IPAddress, &a.UserAgent, &a.UserID, &a.Success, &a.Reason, &a.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, &a)
+	}
+	return attempts, rows.Err()
+}
+
+type postgresTwoFactor struct {
+	db *sql.DB
+}
+
+func (r *postgresTwoFactor) Secret(ctx context.Context, userID int) (string, bool, error) {
+	var secret sql.NullString
+	var enabled bool
+	err := r.db.QueryRowContext(ctx, "SELECT two_factor_secret, two_factor_enabled FROM users WHERE id = $1", userID).
+		Scan(&secret, &enabled)
+	if err == sql.ErrNoRows {
+		return "", false, ErrUserNotFound
+	}
+	return secret.String, enabled, err
+}
+
+func (r *postgresTwoFactor) SetPendingSecret(ctx context.Context, userID int, secret string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE users SET two_factor_secret = $2, two_factor_last_step = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND NOT two_factor_enabled
+	`, userID, secret)
+	return err
+}
+
+func (r *postgresTwoFactor) Enable(ctx context.Context, userID int, step int64) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users SET two_factor_enabled = true, two_factor_last_step = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, userID, step)
+	return requireRow(result, err, ErrUserNotFound)
+}
+
+func (r *postgresTwoFactor) Disable(ctx context.Context, userID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE users
+		SET two_factor_enabled = false, two_factor_secret = NULL, two_factor_last_step = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, userID)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM two_factor_recovery_codes WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *postgresTwoFactor) UseStep(ctx context.Context, userID int, step int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users SET two_factor_last_step = $2
+		WHERE id = $1 AND (two_factor_last_step IS NULL OR two_factor_last_step < $2)
+	`, userID, step)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected == 1, err
+}
+
+func (r *postgresTwoFactor) ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM two_factor_recovery_codes WHERE user_id = $1", userID); err != nil {
+		return err
+	}
+	for _, codeHash := range codeHashes {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO two_factor_recovery_codes (user_id, code_hash) VALUES ($1, $2)",
+			userID, codeHash,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *postgresTwoFactor) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE two_factor_recovery_codes SET used_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected == 1, err
+}
+
+func (r *postgresTwoFactor) UseChallenge(ctx context.Context, id string, expiresAt time.Time) (bool, error) {
+	// Expired IDs are pruned here since they can no longer be replayed
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM used_two_factor_challenges WHERE expires_at < CURRENT_TIMESTAMP"); err != nil {
+		return false, err
+	}
+	// The expiry is stored relative to the database clock, like session expiries
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO used_two_factor_challenges (id, expires_at)
+		VALUES ($1, CURRENT_TIMESTAMP + $2 * INTERVAL '1 second')
+		ON CONFLICT (id) DO NOTHING
+	`, id, int64(time.Until(expiresAt).Seconds())+1)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected == 1, err
+}
+
+const sessionColumns = "id, user_id, user_agent, ip_address, expires_at, last_used_at, created_at"
+
+func scanSession(row scanner) (*model.Session, error) {
+	session := &model.Session{}
+	err := row.Scan(&session.ID, &session.UserID, &session.UserAgent, &session.IPAddress,
+		&session.ExpiresAt, &session.LastUsedAt, &session.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+type postgresSessions struct {
+	db *sql.DB
+}
+
+func (r *postgresSessions) Create(ctx context.Context, session NewSession) (*model.Session, error) {
+	return scanSession(r.db.QueryRowContext(ctx, `
+		INSERT INTO sessions (id, user_id, refresh_token_hash, user_agent, ip_address, expires_at)
+		VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP + $6 * INTERVAL '1 second')
+		RETURNING `+sessionColumns,
+		session.ID, session.UserID, session.RefreshTokenHash, session.UserAgent, session.IPAddress, int64(session.TTL.Seconds())))
+}
+
+func (r *postgresSessions) Rotate(ctx context.Context, tokenHash, newTokenHash string, ttl time.Duration) (*model.Session, error) {
+	session, err := scanSession(r.db.QueryRowContext(ctx, `
+		UPDATE sessions
+		SET refresh_token_hash = $2,
+			previous_refresh_token_hash = refresh_token_hash,
+			expires_at = CURRENT_TIMESTAMP + $3 * INTERVAL '1 second',
+			last_used_at = CURRENT_TIMESTAMP
+		WHERE refresh_token_hash = $1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		RETURNING `+sessionColumns,
+		tokenHash, newTokenHash, int64(ttl.Seconds())))
+	if err != sql.ErrNoRows {
+		return session, err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP
+		WHERE previous_refresh_token_hash = $1 AND revoked_at IS NULL
+	`, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	return nil, ErrSessionNotFound
+}
+
+func (r *postgresSessions) Revoke(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND revoked_at IS NULL", id)
+	return err
+}
+
+func (r *postgresSessions) RevokeAll(ctx context.Context, userID int) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL", userID)
+	return err
+}
+
+func (r *postgresSessions) RevokeOthers(ctx context.Context, userID int, keepID string) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL", userID, keepID)
+	return err
+}
+
+func (r *postgresSessions) IsActive(ctx context.Context, id string) (bool, error) {
+	var active bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM sessions
+			WHERE id = $1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		)
+	`, id).Scan(&active)
+	return active, err
+}
+
+const apiKeyColumns = "id, user_id, name, prefix, scopes, last_used_at, expires_at, revoked_at, created_at"
+
+func scanAPIKey(row scanner) (*model.APIKey, error) {
+	key := &model.APIKey{}
+	err := row.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, pq.Array(&key.Scopes),
+		&key.LastUsedAt, &key.ExpiresAt, &key.RevokedAt, &key.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+type postgresAPIKeys struct {
+	db *sql.DB
+}
+
+func (r *postgresAPIKeys) Create(ctx context.Context, key NewAPIKey) (*model.APIKey, error) {
+	return scanAPIKey(r.db.QueryRowContext(ctx, `
+		INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+apiKeyColumns,
+		key.UserID, key.Name, key.Prefix, key.KeyHash, pq.Array(key.Scopes), key.ExpiresAt))
+}
+
+func (r *postgresAPIKeys) Authenticate(ctx context.Context, keyHash string, touchInterval time.Duration) (*model.APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, `
+		SELECT `+apiKeyColumns+` FROM api_keys
+		WHERE key_hash = $1 AND revoked_at IS NULL
+			AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+	`, keyHash))
+	if err == sql.ErrNoRows {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE api_keys SET last_used_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < CURRENT_TIMESTAMP - $2 * INTERVAL '1 second')
+	`, key.ID, int64(touchInterval.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (r *postgresAPIKeys) ListForUser(ctx context.Context, userID int) ([]*model.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE user_id = $1 ORDER BY created_at DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*model.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *postgresAPIKeys) Revoke(ctx context.Context, id, userID int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND ($2 = 0 OR user_id = $2) AND revoked_at IS NULL
+	`, id, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected == 1, err
+}
+
+type postgresUserTokens struct {
+	db *sql.DB
+}
+
+func (r *postgresUserTokens) Create(ctx context.Context, userID int, purpose, tokenHash string, ttl time.Duration) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE user_tokens SET used_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+	`, userID, purpose)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP + $4 * INTERVAL '1 second')
+	`, userID, purpose, tokenHash, int64(ttl.Seconds()))
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *postgresUserTokens) Consume(ctx context.Context, tokenHash, purpose string) (int, error) {
+	var userID int
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE user_tokens SET used_at = CURRENT_TIMESTAMP
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		RETURNING user_id
+	`, tokenHash, purpose).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return 0, ErrUserTokenNotFound
+	}
+	return userID, err
+}
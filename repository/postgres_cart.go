@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"ai-catalog/model"
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+type postgresCarts struct {
+	db *sql.DB
+}
+
+func (r *postgresCarts) List(ctx context.Context, userID int) ([]*model.CartItem, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+productColumns+`, c.id, c.user_id, c.product_id, c.variant_id, c.quantity, c.created_at, c.updated_at
+		FROM cart c
+		JOIN products p ON c.product_id = p.id
+		WHERE c.user_id = $1
+		ORDER BY c.created_at, c.id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*model.CartItem{}
+	var variantIDs []int
+	for rows.Next() {
+		item := &model.CartItem{}
+		item.Product, err = scanProduct(rows, &item.ID, &item.UserID, &item.ProductID, &item.VariantID, &item.Quantity, &item.CreatedAt, &item.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if item.VariantID != nil {
+			variantIDs = append(variantIDs, *item.VariantID)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	byID := map[int]*model.ProductVariant{}
+	if len(variantIDs) > 0 {
+		variants, err := scanVariants(r.db.QueryContext(ctx, "SELECT "+variantColumns+" FROM product_variants v WHERE v.id = ANY($1)", pq.Array(variantIDs)))
+		if err != nil {
+			return nil, err
+		}
+		for _, variant := range variants {
+			byID[variant.ID] = variant
+		}
+	}
+	for _, item := range items {
+		if item.VariantID != nil {
+			item.Variant = byID[*item.VariantID]
+		}
+		markAvailable(item)
+	}
+	return items, nil
+}
+
+// activeProduct returns a product that can be added to a cart or wishlist
+func activeProduct(ctx context.Context, db *sql.DB, id int) (*model.Product, error) {
+	product, err := scanProduct(db.QueryRowContext(ctx, "SELECT "+productColumns+" FROM products p WHERE p.id = $1 AND p.is_active = true", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrProductNotFound
+	}
+	return product, err
+}
+
+// cartVariant returns the variant of a product being added to a cart, or nil
+// for products not sold in variants
+func cartVariant(ctx context.Context, db *sql.DB, productID int, variantID *int) (*model.ProductVariant, error) {
+	if variantID == nil {
+		var hasVariants bool
+		err := db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM product_variants WHERE product_id = $1)", productID).Scan(&hasVariants)
+		if err != nil {
+			return nil, err
+		}
+		if hasVariants {
+			return nil, ErrVariantRequired
+		}
+		return nil, nil
+	}
+
+	variant, err := scanVariant(db.QueryRowContext(ctx, `
+		SELECT `+variantColumns+` FROM product_variants v WHERE v.id = $1 AND v.product_id = $2 AND v.is_active
+	`, *variantID, productID))
+	if err == sql.ErrNoRows {
+		return nil, ErrVariantNotFound
+	}
+	return variant, err
+}
+
+// cartItemColumns are the cart columns returned by the cart writes
+const cartItemColumns = "id, user_id, product_id, variant_id, quantity, created_at, updated_at"
+
+func (r *postgresCarts) Add(ctx context.Context, userID, productID int, variantID *int, quantity int) (*model.CartItem, error) {
+	product, err := activeProduct(ctx, r.db, productID)
+	if err != nil {
+		return nil, err
+	}
+	variant, err := cartVariant(ctx, r.db, productID, variantID)
+	if err != nil {
+		return nil, err
+	}
+	stock := product.StockQuantity
+	if variant != nil {
+		stock = variant.StockQuantity
+	}
+	if stock < quantity {
+		return nil, ErrInsufficientStock
+	}
+
+	item := &model.CartItem{Product: product, Variant: variant, Available: true}
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO cart (user_id, product_id, variant_id, quantity)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, product_id, (COALESCE(variant_id, 0)))
+		DO UPDATE SET quantity = cart.quantity + $4, updated_at = CURRENT_TIMESTAMP
+		RETURNING `+cartItemColumns,
+		userID, productID, variantID, quantity).Scan(&item.ID, &item.UserID, &item.ProductID, &item.VariantID, &item.Quantity, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (r *postgresCarts) SetQuantity(ctx context.Context, userID, id, quantity int) (*model.CartItem, error) {
+	item := &model.CartItem{}
+	var err error
+	item.Product, err = scanProduct(r.db.QueryRowContext(ctx, `
+		SELECT `+productColumns+`, c.variant_id FROM cart c
+		JOIN products p ON c.product_id = p.id
+		WHERE c.id = $1 AND c.user_id = $2
+	`, id, userID), &item.VariantID)
+	if err == sql.ErrNoRows {
+		return nil, ErrCartItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !item.Product.IsActive {
+		return nil, ErrProductNotFound
+	}
+	stock := item.Product.StockQuantity
+	if item.VariantID != nil {
+		item.Variant, err = cartVariant(ctx, r.db, item.Product.ID, item.VariantID)
+		if err != nil {
+			return nil, err
+		}
+		stock = item.Variant.StockQuantity
+	}
+	item.Available = true
+	if stock < quantity {
+		return nil, ErrInsufficientStock
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		UPDATE cart SET quantity = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND user_id = $2
+		RETURNING `+cartItemColumns,
+		id, userID, quantity).Scan(&item.ID, &item.UserID, &item.ProductID, &item.VariantID, &item.Quantity, &item.CreatedAt, &item.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrCartItemNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (r *postgresCarts) Remove(ctx context.Context, userID, id int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM cart WHERE id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
+
+func (r *postgresCarts) Clear(ctx context.Context, userID int) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM cart WHERE user_id = $1", userID)
+	return err
+}
+
+type postgresWishlists struct {
+	db *sql.DB
+}
+
+func (r *postgresWishlists) List(ctx context.Context, userID int) ([]*model.WishlistItem, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+productColumns+`, w.id, w.user_id, w.product_id, w.created_at
+		FROM wishlist w
+		JOIN products p ON w.product_id = p.id
+		WHERE w.user_id = $1
+		ORDER BY w.created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*model.WishlistItem{}
+	for rows.Next() {
+		item := &model.WishlistItem{}
+		item.Product, err = scanProduct(rows, &item.ID, &item.UserID, &item.ProductID, &item.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *postgresWishlists) ListPage(ctx context.Context, userID int, page Page) (*model.WishlistConnection, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM wishlist w JOIN products p ON w.product_id = p.id WHERE w.user_id = $1
+	`, userID).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := newestFirst("w")
+	condition, order, args, err := keys.apply(page, []interface{}{userID})
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+productColumns+`, w.id, w.user_id, w.product_id, w.created_at, `+keys.column()+`
+		FROM wishlist w
+		JOIN products p ON w.product_id = p.id
+		WHERE w.user_id = $1 AND `+condition+order, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*model.WishlistItem
+	var cursors []string
+	for rows.Next() {
+		item := &model.WishlistItem{}
+		var key string
+		item.Product, err = scanProduct(rows, &item.ID, &item.UserID, &item.ProductID, &item.CreatedAt, &key)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		cursors = append(cursors, encodeCursor(keys.sort, key, item.ID))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items, cursors, info := finishPage(page, items, cursors)
+	connection := &model.WishlistConnection{Edges: []*model.WishlistItemEdge{}, PageInfo: info, TotalCount: total}
+	for i, item := range items {
+		connection.Edges = append(connection.Edges, &model.WishlistItemEdge{Cursor: cursors[i], Node: item})
+	}
+	return connection, nil
+}
+
+func (r *postgresWishlists) Add(ctx context.Context, userID, productID int) (*model.WishlistItem, error) {
+	product, err := activeProduct(ctx, r.db, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The no-op update makes RETURNING yield the existing row on conflict
+	item := &model.WishlistItem{Product: product}
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO wishlist (user_id, product_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, product_id) DO UPDATE SET product_id = EXCLUDED.product_id
+		RETURNING id, user_id, product_id, created_at
+	`, userID, productID).Scan(&item.ID, &item.UserID, &item.ProductID, &item.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (r *postgresWishlists) Remove(ctx context.Context, userID, productID int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM wishlist WHERE user_id = $1 AND product_id = $2", userID, productID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected > 0, err
+}
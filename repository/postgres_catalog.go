@@ -0,0 +1,773 @@
+package repository
+
+import (
+	"ai-catalog/model"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// categoryColumns selects a category row of table c in the order scanCategory expects
+const categoryColumns = "c.id, c.name, COALESCE(c.description, ''), COALESCE(c.image_url, ''), c.parent_id, c.created_at"
+
+// scanCategory scans a row selected with categoryColumns
+func scanCategory(row scanner) (*model.Category, error) {
+	category := &model.Category{}
+	err := row.Scan(&category.ID, &category.Name, &category.Description, &category.ImageURL, &category.ParentID, &category.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+type postgresCategories struct {
+	db *sql.DB
+}
+
+func (r *postgresCategories) GetByID(ctx context.Context, id int) (*model.Category, error) {
+	category, err := scanCategory(r.db.QueryRowContext(ctx, "SELECT "+categoryColumns+" FROM categories c WHERE c.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrCategoryNotFound
+	}
+	return category, err
+}
+
+func (r *postgresCategories) List(ctx context.Context) ([]*model.Category, error) {
+	return r.query(ctx, "SELECT "+categoryColumns+" FROM categories c ORDER BY c.name")
+}
+
+func (r *postgresCategories) Search(ctx context.Context, text string) ([]*model.Category, error) {
+	return r.query(ctx, `
+		SELECT `+categoryColumns+` FROM categories c
+		WHERE normalize_arabic(c.name) ILIKE $1 OR EXISTS (
+			SELECT 1 FROM category_translations t
+			WHERE t.category_id = c.id AND normalize_arabic(t.name) ILIKE $1
+		)
+		ORDER BY c.name
+	`, "%"+normalizeArabic(text)+"%")
+}
+
+func (r *postgresCategories) Children(ctx context.Context, parentID *int) ([]*model.Category, error) {
+	if parentID == nil {
+		return r.query(ctx, "SELECT "+categoryColumns+" FROM categories c WHERE c.parent_id IS NULL ORDER BY c.name")
+	}
+	return r.query(ctx, "SELECT "+categoryColumns+" FROM categories c WHERE c.parent_id = $1 ORDER BY c.name", *parentID)
+}
+
+func (r *postgresCategories) Ancestors(ctx context.Context, id int) ([]*model.Category, error) {
+	// path stops the walk if the stored tree ever contains a cycle
+	return r.query(ctx, `
+		WITH RECURSIVE ancestors AS (
+			SELECT parent.id, parent.parent_id, 1 AS distance, ARRAY[child.id, parent.id] AS path
+			FROM categories child
+			JOIN categories parent ON parent.id = child.parent_id
+			WHERE child.id = $1
+			UNION ALL
+			SELECT c.id, c.parent_id, a.distance + 1, a.path || c.id
+			FROM categories c
+			JOIN ancestors a ON c.id = a.parent_id
+			WHERE NOT c.id = ANY(a.path)
+		)
+		SELECT `+categoryColumns+`
+		FROM ancestors a
+		JOIN categories c ON c.id = a.id
+		ORDER BY a.distance DESC
+	`, id)
+}
+
+func (r *postgresCategories) Create(ctx context.Context, category NewCategory) (*model.Category, error) {
+	return scanCategory(r.db.QueryRowContext(ctx, `
+		INSERT INTO categories AS c (name, description, image_url, parent_id)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4)
+		RETURNING `+categoryColumns,
+		category.Name, category.Description, category.ImageURL, category.ParentID))
+}
+
+func (r *postgresCategories) Move(ctx context.Context, id int, parentID *int) (*model.Category, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Two concurrent moves could each pass the cycle check and form a cycle
+	// together, so moves take turns
+	if _, err := tx.ExecContext(ctx, "LOCK TABLE categories IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return nil, err
+	}
+
+	if parentID != nil {
+		var exists, cycle bool
+		err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS(SELECT 1 FROM categories WHERE id = $2),
+				EXISTS(SELECT 1 FROM (`+categorySubtree(1)+`) subtree WHERE subtree.id = $2)
+		`, id, *parentID).Scan(&exists, &cycle)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, ErrCategoryNotFound
+		}
+		if cycle {
+			return nil, ErrCategoryCycle
+		}
+	}
+
+	category, err := scanCategory(tx.QueryRowContext(ctx,
+		"UPDATE categories AS c SET parent_id = $2 WHERE c.id = $1 RETURNING "+categoryColumns, id, parentID))
+	if err == sql.ErrNoRows {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return category, tx.Commit()
+}
+
+func (r *postgresCategories) Delete(ctx context.Context, id int) error {
+	var inUse bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM categories WHERE parent_id = $1) OR EXISTS(SELECT 1 FROM products WHERE category_id = $1)
+	`, id).Scan(&inUse)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return ErrCategoryNotEmpty
+	}
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM categories WHERE id = $1", id)
+	return requireRow(result, err, ErrCategoryNotFound)
+}
+
+func (r *postgresCategories) Suggest(ctx context.Context, prefix string, limit int) ([]*model.Category, error) {
+	prefix = normalizeQuery(prefix)
+	return r.query(ctx, `
+		WITH names AS (
+			SELECT c.id, normalize_arabic(c.name) AS name FROM categories c
+			UNION ALL
+			SELECT t.category_id, normalize_arabic(t.name) FROM category_translations t
+		), matches AS (
+			SELECT id, MAX(`+suggestionScore("name")+`) AS score
+			FROM names
+			WHERE `+suggestionCondition("name")+`
+			GROUP BY id
+		)
+		SELECT `+categoryColumns+`
+		FROM matches m
+		JOIN categories c ON c.id = m.id
+		ORDER BY m.score DESC, c.name
+		LIMIT $3
+	`, escapeLike(prefix), prefix, limit)
+}
+
+// categorySubtree selects the ID of the category in parameter $n and the IDs
+// of all its descendants
+func categorySubtree(n int) string {
+	return fmt.Sprintf(`
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM categories WHERE id = $%d
+			UNION
+			SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+		)
+		SELECT id FROM subtree`, n)
+}
+
+// query runs a query selecting categoryColumns and returns the categories
+func (r *postgresCategories) query(ctx context.Context, query string, args ...interface{}) ([]*model.Category, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := []*model.Category{}
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	return categories, rows.Err()
+}
+
+// productColumns selects a product row of table p in the order scanProduct expects
+const productColumns = `p.id, p.name, p.price, p.original_price, COALESCE(p.category_id, 0), COALESCE(p.description, ''),
+	COALESCE(p.short_description, ''), COALESCE(p.image_url, ''), COALESCE(p.stock_quantity, 0), COALESCE(p.sku, ''),
+	p.weight, COALESCE(p.dimensions, ''), COALESCE(p.is_active, false), COALESCE(p.is_featured, false), p.created_at, p.updated_at`
+
+// scanProduct scans a row selected with productColumns, followed by any extra destinations
+func scanProduct(row scanner, extra ...interface{}) (*model.Product, error) {
+	product := &model.Product{}
+	dest := append([]interface{}{&product.ID, &product.Name, &product.Price, &product.OriginalPrice, &product.CategoryID,
+		&product.Description, &product.ShortDescription, &product.ImageURL, &product.StockQuantity, &product.SKU,
+		&product.Weight, &product.Dimensions, &product.IsActive, &product.IsFeatured, &product.CreatedAt, &product.UpdatedAt},
+		extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// productRating is the average review rating of product p, 0 without reviews
+const productRating = "COALESCE((SELECT AVG(rv.rating) FROM reviews rv WHERE rv.product_id = p.id), 0)"
+
+// productPrices are the prices product p is sold at as a table with a price
+// column: those of its active variants, or its own price when it has no variants
+const productPrices = `(
+	SELECT v.price FROM product_variants v WHERE v.product_id = p.id AND v.is_active
+	UNION ALL
+	SELECT p.price WHERE NOT EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = p.id)
+) AS prices (price)`
+
+// productPrice is the price product p is sorted by: the lowest of productPrices,
+// which its listing starts from, or its own price when no variant is active
+const productPrice = "COALESCE((SELECT MIN(price) FROM " + productPrices + "), p.price)"
+
+// productSearchQuery is the full-text query for the search text in parameter $n.
+// The product_search_query SQL function normalizes Arabic spelling and stems
+// the words as English and Arabic to match the search_vector column.
+func productSearchQuery(n int) string {
+	return fmt.Sprintf("product_search_query($%d)", n)
+}
+
+// productWhere builds the WHERE clause and arguments for a filter
+func productWhere(filter ProductFilter) (string, []interface{}) {
+	conditions, args := productConditions(filter, nil)
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// productConditions returns the conditions of a filter, numbering its
+// parameters after args and appending them to it
+func productConditions(filter ProductFilter, args []interface{}) ([]string, []interface{}) {
+	var conditions []string
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.ActiveOnly {
+		conditions = append(conditions, "p.is_active = true")
+	}
+	if filter.CategoryID != nil {
+		if filter.IncludeDescendants {
+			args = append(args, *filter.CategoryID)
+			conditions = append(conditions, "p.category_id IN ("+categorySubtree(len(args))+")")
+		} else {
+			add("p.category_id = $%d", *filter.CategoryID)
+		}
+	}
+	if filter.Search != "" {
+		args = append(args, filter.Search)
+		conditions = append(conditions, "p.search_vector @@ "+productSearchQuery(len(args)))
+	}
+	if filter.MinPrice != nil || filter.MaxPrice != nil {
+		// Both bounds must hold for the same price
+		var bounds []string
+		if filter.MinPrice != nil {
+			args = append(args, *filter.MinPrice)
+			bounds = append(bounds, fmt.Sprintf("price >= $%d", len(args)))
+		}
+		if filter.MaxPrice != nil {
+			args = append(args, *filter.MaxPrice)
+			bounds = append(bounds, fmt.Sprintf("price <= $%d", len(args)))
+		}
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM "+productPrices+" WHERE "+strings.Join(bounds, " AND ")+")")
+	}
+	if filter.IsFeatured != nil {
+		add("p.is_featured = $%d", *filter.IsFeatured)
+	}
+	if filter.MinRating != nil {
+		add(productRating+" >= $%d", *filter.MinRating)
+	}
+	if filter.InStock != nil {
+		if *filter.InStock {
+			conditions = append(conditions, "p.stock_quantity > 0")
+		} else {
+			conditions = append(conditions, "COALESCE(p.stock_quantity, 0) <= 0")
+		}
+	}
+	return conditions, args
+}
+
+type postgresProducts struct {
+	db *sql.DB
+}
+
+func (r *postgresProducts) GetByID(ctx context.Context, id int) (*model.Product, error) {
+	product, err := scanProduct(r.db.QueryRowContext(ctx, "SELECT "+productColumns+" FROM products p WHERE p.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrProductNotFound
+	}
+	return product, err
+}
+
+func (r *postgresProducts) List(ctx context.Context, filter ProductFilter) ([]*model.Product, error) {
+	where, args := productWhere(filter)
+	query := "SELECT " + productColumns + " FROM products p" + where
+
+	var order []string
+	if filter.Search != "" {
+		args = append(args, filter.Search)
+		order = append(order, fmt.Sprintf("ts_rank(p.search_vector, %s) DESC", productSearchQuery(len(args))))
+	}
+	if filter.FeaturedFirst {
+		order = append(order, "p.is_featured DESC")
+	}
+	query += " ORDER BY " + strings.Join(append(order, "p.created_at DESC", "p.id DESC"), ", ")
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []*model.Product{}
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	return products, rows.Err()
+}
+
+// productKeysets are the Postgres orders of each product sort
+var productKeysets = map[ProductSort]keyset{
+	SortNewest:    newestFirst("p"),
+	SortPriceAsc:  {sort: string(SortPriceAsc), key: productPrice, cast: "numeric", id: "p.id"},
+	SortPriceDesc: {sort: string(SortPriceDesc), key: productPrice, cast: "numeric", desc: true, id: "p.id"},
+	SortRating:    {sort: string(SortRating), key: productRating, cast: "numeric", desc: true, id: "p.id"},
+	SortPopularity: {sort: string(SortPopularity), cast: "bigint", desc: true, id: "p.id",
+		key: "COALESCE((SELECT SUM(oi.quantity) FROM order_items oi WHERE oi.product_id = p.id), 0)"},
+}
+
+func (r *postgresProducts) ListPage(ctx context.Context, filter ProductFilter, sortBy ProductSort, page Page) (*model.ProductConnection, error) {
+	keys, ok := productKeysets[sortBy]
+	if !ok {
+		return nil, fmt.Errorf("unknown product sort %q", sortBy)
+	}
+
+	total, err := r.Count(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := productWhere(filter)
+	condition, order, args, err := keys.apply(page, args)
+	if err != nil {
+		return nil, err
+	}
+	if where == "" {
+		where = " WHERE " + condition
+	} else {
+		where += " AND " + condition
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT "+productColumns+", "+keys.column()+" FROM products p"+where+order, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []*model.Product
+	var cursors []string
+	for rows.Next() {
+		var key string
+		product, err := scanProduct(rows, &key)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+		cursors = append(cursors, encodeCursor(keys.sort, key, product.ID))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	products, cursors, info := finishPage(page, products, cursors)
+	connection := &model.ProductConnection{Edges: []*model.ProductEdge{}, PageInfo: info, TotalCount: total}
+	for i, product := range products {
+		connection.Edges = append(connection.Edges, &model.ProductEdge{Cursor: cursors[i], Node: product})
+	}
+	return connection, nil
+}
+
+func (r *postgresProducts) Count(ctx context.Context, filter ProductFilter) (int, error) {
+	where, args := productWhere(filter)
+	var total int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM products p"+where, args...).Scan(&total)
+	return total, err
+}
+
+func (r *postgresProducts) Facets(ctx context.Context, filter ProductFilter) (*model.ProductFacets, error) {
+	// The products are read without the filters the facets refine, and each
+	// facet's own filter is evaluated per product so every facet can apply
+	// all the filters but its own
+	base := filter
+	base.CategoryID, base.IncludeDescendants = nil, false
+	base.MinPrice, base.MaxPrice, base.MinRating, base.InStock = nil, nil, nil, nil
+	where, args := productWhere(base)
+	facetCondition := func(facetFilter ProductFilter) string {
+		var conditions []string
+		conditions, args = productConditions(facetFilter, args)
+		if len(conditions) == 0 {
+			return "true"
+		}
+		return strings.Join(conditions, " AND ")
+	}
+	byCategory := facetCondition(ProductFilter{CategoryID: filter.CategoryID, IncludeDescendants: filter.IncludeDescendants})
+	byPrice := facetCondition(ProductFilter{MinPrice: filter.MinPrice, MaxPrice: filter.MaxPrice})
+	byRating := facetCondition(ProductFilter{MinRating: filter.MinRating})
+	byStock := facetCondition(ProductFilter{InStock: filter.InStock})
+	args = append(args, pq.Array(PriceRangeBounds), pq.Array(RatingThresholds))
+	bounds, thresholds := len(args)-1, len(args)
+
+	// The products are read once; every facet is an aggregate of them. A
+	// product sold in variants counts in the price range of each variant price.
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		WITH matches AS MATERIALIZED (
+			SELECT p.category_id, COALESCE(p.stock_quantity, 0) > 0 AS in_stock, %s AS rating,
+				ARRAY(SELECT DISTINCT width_bucket(price, $%d::numeric[]) FROM %s) AS price_buckets,
+				%s AS by_category, %s AS by_price, %s AS by_rating, %s AS by_stock
+			FROM products p%s
+		)
+		SELECT 'total', 0, COUNT(*) FROM matches WHERE by_category AND by_price AND by_rating AND by_stock
+		UNION ALL
+		SELECT 'category', category_id, COUNT(*) FROM matches
+		WHERE category_id IS NOT NULL AND by_price AND by_rating AND by_stock
+		GROUP BY category_id
+		UNION ALL
+		SELECT 'price', bucket, COUNT(*) FROM matches CROSS JOIN unnest(price_buckets) AS bucket
+		WHERE by_category AND by_rating AND by_stock
+		GROUP BY bucket
+		UNION ALL
+		SELECT 'rating', threshold, COUNT(*) FILTER (WHERE rating >= threshold AND by_category AND by_price AND by_stock)
+		FROM matches CROSS JOIN unnest($%d::int[]) AS threshold GROUP BY threshold
+		UNION ALL
+		SELECT 'stock', in_stock::int, COUNT(*) FROM matches WHERE by_category AND by_price AND by_rating GROUP BY in_stock
+	`, productRating, bounds, productPrices, byCategory, byPrice, byRating, byStock, where, thresholds), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var total, inStock, outOfStock int
+	categories := []*model.CategoryFacet{}
+	priceBuckets := map[int]int{}
+	ratings := map[int]int{}
+	for rows.Next() {
+		var facet string
+		var key, count int
+		if err := rows.Scan(&facet, &key, &count); err != nil {
+			return nil, err
+		}
+		switch facet {
+		case "total":
+			total = count
+		case "category":
+			categories = append(categories, &model.CategoryFacet{CategoryID: key, Count: count})
+		case "price":
+			priceBuckets[key] = count
+		case "rating":
+			ratings[key] = count
+		case "stock":
+			if key == 1 {
+				inStock = count
+			} else {
+				outOfStock = count
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	facets := newProductFacets(priceBuckets, ratings)
+	facets.Total, facets.InStock, facets.OutOfStock = total, inStock, outOfStock
+	sortCategoryFacets(categories)
+	facets.Categories = categories
+	return facets, nil
+}
+
+func (r *postgresProducts) Suggest(ctx context.Context, prefix string, limit int) ([]*model.Product, error) {
+	prefix = normalizeQuery(prefix)
+	rows, err := r.db.QueryContext(ctx, `
+		WITH names AS (
+			SELECT p.id, normalize_arabic(p.name) AS name FROM products p
+			UNION ALL
+			SELECT t.product_id, normalize_arabic(t.name) FROM product_translations t
+		), matches AS (
+			SELECT id, MAX(`+suggestionScore("name")+`) AS score
+			FROM names
+			WHERE `+suggestionCondition("name")+`
+			GROUP BY id
+		)
+		SELECT `+productColumns+`
+		FROM matches m
+		JOIN products p ON p.id = m.id
+		WHERE p.is_active = true
+		ORDER BY m.score DESC, p.name
+		LIMIT $3
+	`, escapeLike(prefix), prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []*model.Product{}
+	for rows.Next() {
+		product, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	return products, rows.Err()
+}
+
+func (r *postgresProducts) SKUTaken(ctx context.Context, sku string, excludeID int) (bool, error) {
+	var taken bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM products WHERE sku = $1 AND id <> $2)
+			OR EXISTS(SELECT 1 FROM product_variants WHERE sku = $1)
+	`, sku, excludeID).Scan(&taken)
+	return taken, err
+}
+
+// sortedColumns returns the columns of fields and their values in a stable order
+func sortedColumns(fields ProductFields) ([]string, []interface{}) {
+	columns := make([]string, 0, len(fields))
+	for column := range fields {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		values[i] = fields[column]
+	}
+	return columns, values
+}
+
+func (r *postgresProducts) Create(ctx context.Context, fields ProductFields) (*model.Product, error) {
+	columns, values := sortedColumns(fields)
+	placeholders := make([]string, len(columns))
+	for i := range columns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+
+	product, err := scanProduct(r.db.QueryRowContext(ctx, fmt.Sprintf(
+		"INSERT INTO products AS p (%s) VALUES (%s) RETURNING %s",
+		strings.Join(columns, ", "), strings.Join(placeholders, ", "), productColumns,
+	), values...))
+	if isUniqueViolation(err) {
+		return nil, ErrSKUTaken
+	}
+	return product, err
+}
+
+func (r *postgresProducts) Update(ctx context.Context, id int, fields ProductFields) (*model.Product, error) {
+	if len(fields) == 0 {
+		return r.GetByID(ctx, id)
+	}
+
+	columns, values := sortedColumns(fields)
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		assignments[i] = fmt.Sprintf("%s = $%d", column, i+2)
+	}
+
+	product, err := scanProduct(r.db.QueryRowContext(ctx, fmt.Sprintf(
+		"UPDATE products AS p SET %s WHERE p.id = $1 RETURNING %s",
+		strings.Join(assignments, ", "), productColumns,
+	), append([]interface{}{id}, values...)...))
+	if err == sql.ErrNoRows {
+		return nil, ErrProductNotFound
+	}
+	if isUniqueViolation(err) {
+		return nil, ErrSKUTaken
+	}
+	return product, err
+}
+
+func (r *postgresProducts) SetActive(ctx context.Context, id int, active bool) (*model.Product, error) {
+	product, err := scanProduct(r.db.QueryRowContext(ctx,
+		"UPDATE products AS p SET is_active = $2 WHERE p.id = $1 RETURNING "+productColumns, id, active,
+	))
+	if err == sql.ErrNoRows {
+		return nil, ErrProductNotFound
+	}
+	return product, err
+}
+
+func (r *postgresProducts) Delete(ctx context.Context, id int) error {
+	var ordered bool
+	if err := r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM order_items WHERE product_id = $1)", id).Scan(&ordered); err != nil {
+		return err
+	}
+	if ordered {
+		return ErrProductOrdered
+	}
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM products WHERE id = $1", id)
+	return requireRow(result, err, ErrProductNotFound)
+}
+
+func (r *postgresProducts) SetLiked(ctx context.Context, userID, productID int, liked bool) error {
+	if !liked {
+		_, err := r.db.ExecContext(ctx, "DELETE FROM product_likes WHERE user_id = $1 AND product_id = $2", userID, productID)
+		return err
+	}
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM products WHERE id = $1 AND is_active = true)", productID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrProductNotFound
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO product_likes (user_id, product_id) VALUES ($1, $2)
+		ON CONFLICT (user_id, product_id) DO NOTHING
+	`, userID, productID)
+	return err
+}
+
+// variantColumns selects a product variant row of table v in the order
+// scanVariant expects
+const variantColumns = "v.id, v.product_id, v.sku, v.price, v.stock_quantity, v.options, v.images, v.is_active, v.created_at, v.updated_at"
+
+// scanVariant scans a row selected with variantColumns
+func scanVariant(row scanner) (*model.ProductVariant, error) {
+	variant := &model.ProductVariant{}
+	var options []byte
+	err := row.Scan(&variant.ID, &variant.ProductID, &variant.SKU, &variant.Price, &variant.StockQuantity, &options,
+		pq.Array(&variant.Images), &variant.IsActive, &variant.CreatedAt, &variant.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(options, &variant.Options); err != nil {
+		return nil, err
+	}
+	return variant, nil
+}
+
+// scanVariants scans the rows of a variant query
+func scanVariants(rows *sql.Rows, err error) ([]*model.ProductVariant, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	variants := []*model.ProductVariant{}
+	for rows.Next() {
+		variant, err := scanVariant(rows)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+	}
+	return variants, rows.Err()
+}
+
+type postgresVariants struct {
+	db *sql.DB
+}
+
+func (r *postgresVariants) GetByID(ctx context.Context, id int) (*model.ProductVariant, error) {
+	variant, err := scanVariant(r.db.QueryRowContext(ctx, "SELECT "+variantColumns+" FROM product_variants v WHERE v.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrVariantNotFound
+	}
+	return variant, err
+}
+
+func (r *postgresVariants) ListForProduct(ctx context.Context, productID int) ([]*model.ProductVariant, error) {
+	return scanVariants(r.db.QueryContext(ctx, "SELECT "+variantColumns+" FROM product_variants v WHERE v.product_id = $1 ORDER BY v.id", productID))
+}
+
+func (r *postgresVariants) SKUTaken(ctx context.Context, sku string, excludeID int) (bool, error) {
+	var taken bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM product_variants WHERE sku = $1 AND id <> $2)
+			OR EXISTS(SELECT 1 FROM products WHERE sku = $1)
+	`, sku, excludeID).Scan(&taken)
+	return taken, err
+}
+
+// variantConflict maps a unique violation on product_variants to the error it means
+func variantConflict(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Constraint == "product_variants_sku_key" {
+		return ErrSKUTaken
+	}
+	return ErrVariantExists
+}
+
+func (r *postgresVariants) Create(ctx context.Context, newVariant NewVariant) (*model.ProductVariant, error) {
+	options, err := json.Marshal(newVariant.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Selecting from products inserts nothing when the product does not exist
+	variant, err := scanVariant(r.db.QueryRowContext(ctx, `
+		INSERT INTO product_variants AS v (product_id, sku, price, stock_quantity, options, images)
+		SELECT id, $2, $3, $4, $5, COALESCE($6::text[], '{}') FROM products WHERE id = $1
+		RETURNING `+variantColumns,
+		newVariant.ProductID, newVariant.SKU, newVariant.Price, newVariant.StockQuantity, string(options),
+		pq.Array(newVariant.Images)))
+	if err == sql.ErrNoRows {
+		return nil, ErrProductNotFound
+	}
+	if isUniqueViolation(err) {
+		return nil, variantConflict(err)
+	}
+	return variant, err
+}
+
+func (r *postgresVariants) Update(ctx context.Context, id int, update VariantUpdate) (*model.ProductVariant, error) {
+	var images interface{}
+	if update.Images != nil {
+		images = pq.Array(update.Images)
+	}
+	variant, err := scanVariant(r.db.QueryRowContext(ctx, `
+		UPDATE product_variants AS v SET sku = COALESCE($2, sku), price = COALESCE($3, price),
+			stock_quantity = COALESCE($4, stock_quantity), images = COALESCE($5::text[], images),
+			is_active = COALESCE($6, is_active)
+		WHERE v.id = $1
+		RETURNING `+variantColumns,
+		id, update.SKU, update.Price, update.StockQuantity, images, update.IsActive))
+	if err == sql.ErrNoRows {
+		return nil, ErrVariantNotFound
+	}
+	if isUniqueViolation(err) {
+		return nil, variantConflict(err)
+	}
+	return variant, err
+}
+
+func (r *postgresVariants) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM product_variants WHERE id = $1", id)
+	return requireRow(result, err, ErrVariantNotFound)
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"ai-catalog/model"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const jobColumns = "id, type, payload, status, result, error, attempts, max_attempts, run_at, created_by, created_at, updated_at, completed_at"
+
+func scanJob(row scanner) (*model.Job, error) {
+	job := &model.Job{}
+	var payload, result []byte
+	err := row.Scan(&job.ID, &job.Type, &payload, &job.Status, &result, &job.Error, &job.Attempts,
+		&job.MaxAttempts, &job.RunAt, &job.CreatedBy, &job.CreatedAt, &job.UpdatedAt, &job.CompletedAt)
+	if err != nil {
+		return nil, err
+	}
+	job.Payload = payload
+	if result != nil {
+		job.Result = result
+	}
+	return job, nil
+}
+
+type postgresJobs struct {
+	db *sql.DB
+}
+
+func (r *postgresJobs) Enqueue(ctx context.Context, job NewJob) (*model.Job, error) {
+	return scanJob(r.db.QueryRowContext(ctx, `
+		INSERT INTO jobs (type, payload, max_attempts, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+jobColumns,
+		job.Type, []byte(job.Payload), job.MaxAttempts, job.CreatedBy))
+}
+
+func (r *postgresJobs) GetByID(ctx context.Context, id int) (*model.Job, error) {
+	job, err := scanJob(r.db.QueryRowContext(ctx, "SELECT "+jobColumns+" FROM jobs WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrJobNotFound
+	}
+	return job, err
+}
+
+// Claim locks the next due job. SKIP LOCKED lets several workers, in this or
+// other processes, poll the queue without picking the same job.
+func (r *postgresJobs) Claim(ctx context.Context, types []string, staleAfter time.Duration) (*model.Job, error) {
+	// A job that keeps hanging or crashing its worker must not be retried forever
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE jobs SET status = $2, error = $3, locked_at = NULL,
+			completed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE type = ANY($1) AND status = $4 AND attempts >= max_attempts
+			AND locked_at < CURRENT_TIMESTAMP - $5 * INTERVAL '1 second'
+	`, pq.Array(types), model.JobDead, ErrJobAbandoned.Error(), model.JobRunning, int64(staleAfter.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := scanJob(r.db.QueryRowContext(ctx, `
+		UPDATE jobs SET status = $1, attempts = attempts + 1, locked_at = CURRENT_TIMESTAMP,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE type = ANY($2) AND (
+				(status = $3 AND run_at <= CURRENT_TIMESTAMP)
+				OR (status = $1 AND locked_at < CURRENT_TIMESTAMP - $4 * INTERVAL '1 second'
+					AND attempts < max_attempts)
+			)
+			ORDER BY run_at, id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING `+jobColumns,
+		model.JobRunning, pq.Array(types), model.JobPending, int64(staleAfter.Seconds())))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return job, err
+}
+
+func (r *postgresJobs) Complete(ctx context.Context, id int, result json.RawMessage) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE jobs SET status = $2, result = $3, error = NULL, locked_at = NULL,
+			completed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, id, model.JobCompleted, []byte(result))
+	return err
+}
+
+func (r *postgresJobs) Reschedule(ctx context.Context, id int, jobErr string, delay time.Duration) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE jobs SET status = $2, error = $3, locked_at = NULL,
+			run_at = CURRENT_TIMESTAMP + $4 * INTERVAL '1 second', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, id, model.JobPending, jobErr, int64(delay.Seconds()))
+	return err
+}
+
+func (r *postgresJobs) DeadLetter(ctx context.Context, id int, jobErr string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE jobs SET status = $2, error = $3, locked_at = NULL,
+			completed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, id, model.JobDead, jobErr)
+	return err
+}
+
+func (r *postgresJobs) Requeue(ctx context.Context, id int) (*model.Job, error) {
+	job, err := scanJob(r.db.QueryRowContext(ctx, `
+		UPDATE jobs SET status = $2, attempts = 0, run_at = CURRENT_TIMESTAMP, completed_at = NULL,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = $3
+		RETURNING `+jobColumns,
+		id, model.JobPending, model.JobDead))
+	if err == sql.ErrNoRows {
+		return nil, ErrJobNotDead
+	}
+	return job, err
+}
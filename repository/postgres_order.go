@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"ai-catalog/model"
+	"context"
+	"database/sql"
+	"encoding/json"
+)
+
+// orderColumns selects an order row of table o in the order scanOrder expects
+const orderColumns = `o.id, o.user_id, o.order_number, o.status, o.total_amount, o.shipping_address, o.shipping_city,
+	COALESCE(o.shipping_country, ''), o.shipping_phone, COALESCE(o.payment_method, ''), COALESCE(o.payment_status, ''),
+	COALESCE(o.notes, ''), o.created_at, o.updated_at`
+
+// scanOrder scans a row selected with orderColumns, followed by any extra destinations
+func scanOrder(row scanner, extra ...interface{}) (*model.Order, error) {
+	order := &model.Order{}
+	dest := append([]interface{}{&order.ID, &order.UserID, &order.OrderNumber, &order.Status, &order.TotalAmount,
+		&order.ShippingAddress, &order.ShippingCity, &order.ShippingCountry, &order.ShippingPhone,
+		&order.PaymentMethod, &order.PaymentStatus, &order.Notes, &order.CreatedAt, &order.UpdatedAt},
+		extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+type postgresOrders struct {
+	db *sql.DB
+}
+
+func (r *postgresOrders) GetByID(ctx context.Context, id int) (*model.Order, error) {
+	order, err := scanOrder(r.db.QueryRowContext(ctx, "SELECT "+orderColumns+" FROM orders o WHERE o.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrOrderNotFound
+	}
+	return order, err
+}
+
+func (r *postgresOrders) ListForUser(ctx context.Context, userID int) ([]*model.Order, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+orderColumns+" FROM orders o WHERE o.user_id = $1 ORDER BY o.created_at DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []*model.Order{}
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
+
+func (r *postgresOrders) ListPageForUser(ctx context.Context, userID int, page Page) (*model.OrderConnection, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM orders WHERE user_id = $1", userID).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	keys := newestFirst("o")
+	condition, order, args, err := keys.apply(page, []interface{}{userID})
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, "SELECT "+orderColumns+", "+keys.column()+" FROM orders o WHERE o.user_id = $1 AND "+condition+order, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orders []*model.Order
+	var cursors []string
+	for rows.Next() {
+		var key string
+		order, err := scanOrder(rows, &key)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+		cursors = append(cursors, encodeCursor(keys.sort, key, order.ID))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	orders, cursors, info := finishPage(page, orders, cursors)
+	connection := &model.OrderConnection{Edges: []*model.OrderEdge{}, PageInfo: info, TotalCount: total}
+	for i, order := range orders {
+		connection.Edges = append(connection.Edges, &model.OrderEdge{Cursor: cursors[i], Node: order})
+	}
+	return connection, nil
+}
+
+func (r *postgresOrders) Items(ctx context.Context, orderID int) ([]*model.OrderItem, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, order_id, COALESCE(product_id, 0), product_name, product_price, variant_id, COALESCE(variant_name, ''),
+			quantity, total_price, created_at
+		FROM order_items WHERE order_id = $1 ORDER BY id
+	`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*model.OrderItem{}
+	for rows.Next() {
+		item := &model.OrderItem{}
+		err := rows.Scan(&item.ID, &item.OrderID, &item.ProductID, &item.ProductName, &item.ProductPrice,
+			&item.VariantID, &item.VariantName, &item.Quantity, &item.TotalPrice, &item.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func (r *postgresOrders) CreateFromCart(ctx context.Context, newOrder NewOrder) (*model.Order, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock the cart first so a second checkout of the same cart, say a double
+	// click, waits for this one and then finds the cart empty instead of
+	// placing a duplicate order. Then lock the stock of everything in the cart
+	// until the order is placed, in id order so concurrent orders cannot
+	// deadlock. Variants come before products, as the
+	// product_variants_sync_stock trigger locks the product after its variant.
+	for _, lock := range []string{
+		"SELECT id FROM cart WHERE user_id = $1 ORDER BY id FOR UPDATE",
+		"SELECT id FROM product_variants WHERE id IN (SELECT variant_id FROM cart WHERE user_id = $1) ORDER BY id FOR UPDATE",
+		"SELECT id FROM products WHERE id IN (SELECT product_id FROM cart WHERE user_id = $1) ORDER BY id FOR UPDATE",
+	} {
+		if _, err := tx.ExecContext(ctx, lock, newOrder.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Read the cart before writing anything; the rows must be closed before the
+	// transaction can run other statements
+	rows, err := tx.QueryContext(ctx, `
+		SELECT c.product_id, c.variant_id, c.quantity, p.name, COALESCE(v.price, p.price), v.options,
+			p.is_active AND COALESCE(v.is_active, true), COALESCE(v.stock_quantity, p.stock_quantity)
+		FROM cart c
+		JOIN products p ON c.product_id = p.id
+		LEFT JOIN product_variants v ON c.variant_id = v.id
+		WHERE c.user_id = $1
+		ORDER BY c.id
+	`, newOrder.UserID)
+	if err != nil {
+		return nil, err
+	}
+	var items []*model.OrderItem
+	var totalAmount float64
+	unavailable, outOfStock := false, false
+	for rows.Next() {
+		item := &model.OrderItem{}
+		var options []byte
+		var active bool
+		var stock int
+		if err := rows.Scan(&item.ProductID, &item.VariantID, &item.Quantity, &item.ProductName, &item.ProductPrice, &options, &active, &stock); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		unavailable = unavailable || !active
+		outOfStock = outOfStock || stock < item.Quantity
+		if options != nil {
+			var selected []*model.SelectedOption
+			if err := json.Unmarshal(options, &selected); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			item.VariantName = variantName(selected)
+		}
+		item.TotalPrice = item.ProductPrice * float64(item.Quantity)
+		totalAmount += item.TotalPrice
+		items = append(items, item)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if unavailable {
+		return nil, ErrCartUnavailable
+	}
+	if outOfStock {
+		return nil, ErrInsufficientStock
+	}
+	if len(items) == 0 {
+		return nil, ErrCartEmpty
+	}
+
+	order, err := scanOrder(tx.QueryRowContext(ctx, `
+		INSERT INTO orders AS o (user_id, order_number, status, total_amount, shipping_address, shipping_city, shipping_phone, payment_method, notes)
+		VALUES ($1, $2, 'pending', $3, $4, $5, $6, $7, $8)
+		RETURNING `+orderColumns,
+		newOrder.UserID, newOrder.OrderNumber, totalAmount, newOrder.ShippingAddress, newOrder.ShippingCity,
+		newOrder.ShippingPhone, newOrder.PaymentMethod, newOrder.Notes))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO order_items (order_id, product_id, product_name, product_price, variant_id, variant_name, quantity, total_price)
+			VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8)
+		`, order.ID, item.ProductID, item.ProductName, item.ProductPrice, item.VariantID, item.VariantName, item.Quantity, item.TotalPrice)
+		if err != nil {
+			return nil, err
+		}
+
+		// The product_variants_sync_stock trigger updates the product's stock
+		if item.VariantID != nil {
+			_, err = tx.ExecContext(ctx, "UPDATE product_variants SET stock_quantity = stock_quantity - $1 WHERE id = $2", item.Quantity, *item.VariantID)
+		} else {
+			_, err = tx.ExecContext(ctx, "UPDATE products SET stock_quantity = stock_quantity - $1 WHERE id = $2", item.Quantity, item.ProductID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM cart WHERE user_id = $1", newOrder.UserID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (r *postgresOrders) UpdateStatus(ctx context.Context, id int, status string) (*model.Order, error) {
+	order, err := scanOrder(r.db.QueryRowContext(ctx, `
+		UPDATE orders AS o SET status = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE o.id = $1
+		RETURNING `+orderColumns, id, status))
+	if err == sql.ErrNoRows {
+		return nil, ErrOrderNotFound
+	}
+	return order, err
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"ai-catalog/model"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// reviewColumns selects a review and its author in the order scanReview expects.
+// $1 is the viewing user's ID, or 0 for anonymous requests.
+const reviewColumns = `r.id, r.user_id, r.product_id, r.rating, COALESCE(r.title, ''), COALESCE(r.comment, ''),
+	COALESCE(r.is_verified_purchase, false), r.created_at, r.updated_at,
+	(SELECT COUNT(*) FROM review_likes l WHERE l.review_id = r.id),
+	EXISTS(SELECT 1 FROM review_likes l WHERE l.review_id = r.id AND l.user_id = $1),
+	u.id, u.email, u.first_name, u.last_name, COALESCE(u.phone, ''), COALESCE(u.address, ''), COALESCE(u.city, ''),
+	COALESCE(u.country, ''), u.created_at, u.updated_at`
+
+// scanReview scans a row selected with reviewColumns, followed by any extra destinations
+func scanReview(row scanner, extra ...interface{}) (*model.Review, error) {
+	review := &model.Review{}
+	user := &model.User{}
+	dest := append([]interface{}{
+		&review.ID, &review.UserID, &review.ProductID, &review.Rating, &review.Title, &review.Comment,
+		&review.IsVerifiedPurchase, &review.CreatedAt, &review.UpdatedAt, &review.LikeCount, &review.IsLiked,
+		&user.ID, &user.Email, &user.FirstName, &user.LastName, &user.Phone, &user.Address, &user.City,
+		&user.Country, &user.CreatedAt, &user.UpdatedAt,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	review.User = user
+	return review, nil
+}
+
+type postgresReviews struct {
+	db *sql.DB
+}
+
+func (r *postgresReviews) GetByID(ctx context.Context, viewerID, id int) (*model.Review, error) {
+	review, err := scanReview(r.db.QueryRowContext(ctx, `
+		SELECT `+reviewColumns+`
+		FROM reviews r
+		JOIN users u ON r.user_id = u.id
+		WHERE r.id = $2
+	`, viewerID, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrReviewNotFound
+	}
+	return review, err
+}
+
+// reviewConditions returns the conditions selecting the reviews that match
+// filter, appending their parameters to args
+func reviewConditions(filter ReviewFilter, args []interface{}) ([]string, []interface{}) {
+	conditions := []string{"true"}
+	if filter.ProductID != 0 {
+		args = append(args, filter.ProductID)
+		conditions = append(conditions, fmt.Sprintf("r.product_id = $%d", len(args)))
+	}
+	if filter.UserID != 0 {
+		args = append(args, filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("r.user_id = $%d", len(args)))
+	}
+	return conditions, args
+}
+
+func (r *postgresReviews) List(ctx context.Context, viewerID int, filter ReviewFilter) ([]*model.Review, error) {
+	conditions, args := reviewConditions(filter, []interface{}{viewerID})
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+reviewColumns+`
+		FROM reviews r
+		JOIN users u ON r.user_id = u.id
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY r.created_at DESC
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []*model.Review{}
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, rows.Err()
+}
+
+func (r *postgresReviews) ListPage(ctx context.Context, viewerID int, filter ReviewFilter, page Page) (*model.ReviewConnection, error) {
+	var total int
+	conditions, args := reviewConditions(filter, nil)
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM reviews r WHERE "+strings.Join(conditions, " AND "), args...).Scan(&total)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions, args = reviewConditions(filter, []interface{}{viewerID})
+	keys := newestFirst("r")
+	condition, order, args, err := keys.apply(page, args)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+reviewColumns+`, `+keys.column()+`
+		FROM reviews r
+		JOIN users u ON r.user_id = u.id
+		WHERE `+strings.Join(append(conditions, condition), " AND ")+order, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reviews []*model.Review
+	var cursors []string
+	for rows.Next() {
+		var key string
+		review, err := scanReview(rows, &key)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+		cursors = append(cursors, encodeCursor(keys.sort, key, review.ID))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	reviews, cursors, info := finishPage(page, reviews, cursors)
+	connection := &model.ReviewConnection{Edges: []*model.ReviewEdge{}, PageInfo: info, TotalCount: total}
+	for i, review := range reviews {
+		connection.Edges = append(connection.Edges, &model.ReviewEdge{Cursor: cursors[i], Node: review})
+	}
+	return connection, nil
+}
+
+func (r *postgresReviews) Create(ctx context.Context, review NewReview) (*model.Review, error) {
+	var hasPurchased bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM order_items oi
+			JOIN orders o ON oi.order_id = o.id
+			WHERE o.user_id = $1 AND oi.product_id = $2 AND o.status = 'delivered'
+		)
+	`, review.UserID, review.ProductID).Scan(&hasPurchased)
+	if err != nil {
+		return nil, err
+	}
+
+	var id int
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO reviews (user_id, product_id, rating, title, comment, is_verified_purchase)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`, review.UserID, review.ProductID, review.Rating, review.Title, review.Comment, hasPurchased).Scan(&id)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, review.UserID, id)
+}
+
+func (r *postgresReviews) Update(ctx context.Context, id, rating int, title, comment string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE reviews SET rating = $2, title = $3, comment = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`, id, rating, title, comment)
+	return requireRow(result, err, ErrReviewNotFound)
+}
+
+func (r *postgresReviews) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM reviews WHERE id = $1", id)
+	return requireRow(result, err, ErrReviewNotFound)
+}
+
+func (r *postgresReviews) SetLiked(ctx context.Context, userID, reviewID int, liked bool) error {
+	if !liked {
+		_, err := r.db.ExecContext(ctx, "DELETE FROM review_likes WHERE user_id = $1 AND review_id = $2", userID, reviewID)
+		return err
+	}
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM reviews WHERE id = $1)", reviewID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return ErrReviewNotFound
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO review_likes (user_id, review_id) VALUES ($1, $2)
+		ON CONFLICT (user_id, review_id) DO NOTHING
+	`, userID, reviewID)
+	return err
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"ai-catalog/model"
+	"context"
+	"database/sql"
+	"time"
+)
+
+// suggestionCondition matches the names in column that start with the prefix
+// in $1, escaped for LIKE, that have a word starting with it, or whose words
+// resemble the prefix in $2 by trigram similarity
+func suggestionCondition(column string) string {
+	return "(" + column + " ILIKE $1::text || '%' OR " + column + " ILIKE '% ' || $1::text || '%' OR $2::text <% " + column + ")"
+}
+
+// suggestionScore ranks the names in column matched by suggestionCondition:
+// names starting with the prefix first, then names with a word starting with
+// it, each by similarity
+func suggestionScore(column string) string {
+	return "CASE WHEN " + column + " ILIKE $1::text || '%' THEN 2 WHEN " + column + " ILIKE '% ' || $1::text || '%' THEN 1 ELSE 0 END" +
+		" + word_similarity($2::text, " + column + ")"
+}
+
+// maxQueryLength is the longest query the search log stores
+const maxQueryLength = 200
+
+type postgresSearches struct {
+	db *sql.DB
+}
+
+func (r *postgresSearches) Record(ctx context.Context, search NewSearch) error {
+	query := []rune(search.Query)
+	if len(query) > maxQueryLength {
+		query = query[:maxQueryLength]
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO search_queries (query, normalized, result_count, user_id, client_key, locale)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''))
+	`, string(query), normalizeQuery(string(query)), search.Results, search.UserID, search.ClientKey, search.Locale)
+	return err
+}
+
+func (r *postgresSearches) Suggest(ctx context.Context, prefix string, since time.Time, minSearchers, limit int) ([]string, error) {
+	prefix = normalizeQuery(prefix)
+	// Each suggestion shows the spelling customers used most
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT mode() WITHIN GROUP (ORDER BY query)
+		FROM search_queries
+		WHERE created_at >= $3 AND result_count > 0 AND `+suggestionCondition("normalized")+`
+		GROUP BY normalized
+		HAVING COUNT(DISTINCT COALESCE(user_id::text, client_key)) >= $4
+		ORDER BY MAX(`+suggestionScore("normalized")+`) DESC, COUNT(*) DESC
+		LIMIT $5
+	`, escapeLike(prefix), prefix, since, minSearchers, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	queries := []string{}
+	for rows.Next() {
+		var query string
+		if err := rows.Scan(&query); err != nil {
+			return nil, err
+		}
+		queries = append(queries, query)
+	}
+	return queries, rows.Err()
+}
+
+func (r *postgresSearches) Popular(ctx context.Context, since time.Time, zeroResults bool, limit int) ([]*model.SearchStat, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT mode() WITHIN GROUP (ORDER BY query), COUNT(*), MAX(created_at)
+		FROM search_queries
+		WHERE created_at >= $1 AND (NOT $2 OR result_count = 0)
+		GROUP BY normalized
+		ORDER BY COUNT(*) DESC, MAX(created_at) DESC
+		LIMIT $3
+	`, since, zeroResults, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []*model.SearchStat{}
+	for rows.Next() {
+		stat := &model.SearchStat{}
+		if err := rows.Scan(&stat.Query, &stat.Searches, &stat.LastSearchedAt); err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
+}
@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"ai-catalog/model"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// nullIfEmpty converts an empty string to NULL so the default locale text is used
+func nullIfEmpty(s string) interface{} {
+	if s = strings.TrimSpace(s); s == "" {
+		return nil
+	}
+	return s
+}
+
+type postgresTranslations struct {
+	db *sql.DB
+}
+
+func (r *postgresTranslations) Products(ctx context.Context, locale string, ids []int) (map[int]*Translation, error) {
+	return r.query(ctx, `
+		SELECT product_id, name, COALESCE(description, ''), COALESCE(short_description, '') FROM product_translations
+		WHERE locale = $1 AND product_id = ANY($2)
+	`, locale, ids)
+}
+
+func (r *postgresTranslations) Categories(ctx context.Context, locale string, ids []int) (map[int]*Translation, error) {
+	return r.query(ctx, `
+		SELECT category_id, name, COALESCE(description, ''), '' FROM category_translations
+		WHERE locale = $1 AND category_id = ANY($2)
+	`, locale, ids)
+}
+
+func (r *postgresTranslations) query(ctx context.Context, query, locale string, ids []int) (map[int]*Translation, error) {
+	translations := map[int]*Translation{}
+	if len(ids) == 0 {
+		return translations, nil
+	}
+	rows, err := r.db.QueryContext(ctx, query, locale, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t := &Translation{}
+		if err := rows.Scan(&t.ID, &t.Name, &t.Description, &t.ShortDescription); err != nil {
+			return nil, err
+		}
+		translations[t.ID] = t
+	}
+	return translations, rows.Err()
+}
+
+func (r *postgresTranslations) SetProduct(ctx context.Context, locale string, t Translation) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO product_translations (product_id, locale, name, description, short_description)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (product_id, locale) DO UPDATE SET
+			name = EXCLUDED.name, description = EXCLUDED.description,
+			short_description = EXCLUDED.short_description, updated_at = CURRENT_TIMESTAMP
+	`, t.ID, locale, t.Name, nullIfEmpty(t.Description), nullIfEmpty(t.ShortDescription))
+	return err
+}
+
+func (r *postgresTranslations) SetCategory(ctx context.Context, locale string, t Translation) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO category_translations (category_id, locale, name, description)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (category_id, locale) DO UPDATE SET
+			name = EXCLUDED.name, description = EXCLUDED.description, updated_at = CURRENT_TIMESTAMP
+	`, t.ID, locale, t.Name, nullIfEmpty(t.Description))
+	return err
+}
+
+func (r *postgresTranslations) UntranslatedProducts(ctx context.Context, locale string) ([]*Translation, error) {
+	return r.untranslated(ctx, `
+		SELECT p.id,
+			CASE WHEN t.name IS NULL THEN p.name ELSE '' END,
+			CASE WHEN t.description IS NULL THEN COALESCE(p.description, '') ELSE '' END,
+			CASE WHEN t.short_description IS NULL THEN COALESCE(p.short_description, '') ELSE '' END
+		FROM products p
+		LEFT JOIN product_translations t ON t.product_id = p.id AND t.locale = $1
+		WHERE t.product_id IS NULL
+			OR (t.description IS NULL AND COALESCE(p.description, '') <> '')
+			OR (t.short_description IS NULL AND COALESCE(p.short_description, '') <> '')
+		ORDER BY p.id
+	`, locale)
+}
+
+func (r *postgresTranslations) UntranslatedCategories(ctx context.Context, locale string) ([]*Translation, error) {
+	return r.untranslated(ctx, `
+		SELECT c.id,
+			CASE WHEN t.name IS NULL THEN c.name ELSE '' END,
+			CASE WHEN t.description IS NULL THEN COALESCE(c.description, '') ELSE '' END,
+			''
+		FROM categories c
+		LEFT JOIN category_translations t ON t.category_id = c.id AND t.locale = $1
+		WHERE t.category_id IS NULL OR (t.description IS NULL AND COALESCE(c.description, '') <> '')
+		ORDER BY c.id
+	`, locale)
+}
+
+func (r *postgresTranslations) untranslated(ctx context.Context, query, locale string) ([]*Translation, error) {
+	rows, err := r.db.QueryContext(ctx, query, locale)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*Translation
+	for rows.Next() {
+		t := &Translation{}
+		if err := rows.Scan(&t.ID, &t.Name, &t.Description, &t.ShortDescription); err != nil {
+			return nil, err
+		}
+		entries = append(entries, t)
+	}
+	return entries, rows.Err()
+}
+
+func (r *postgresTranslations) FillProduct(ctx context.Context, locale string, t Translation) error {
+	// The name is only empty when it is already translated, so it is never replaced
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO product_translations (product_id, locale, name, description, short_description)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (product_id, locale) DO UPDATE SET
+			description = COALESCE(product_translations.description, EXCLUDED.description),
+			short_description = COALESCE(product_translations.short_description, EXCLUDED.short_description),
+			updated_at = CURRENT_TIMESTAMP
+	`, t.ID, locale, t.Name, nullIfEmpty(t.Description), nullIfEmpty(t.ShortDescription))
+	return err
+}
+
+func (r *postgresTranslations) FillCategory(ctx context.Context, locale string, t Translation) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO category_translations (category_id, locale, name, description)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (category_id, locale) DO UPDATE SET
+			description = COALESCE(category_translations.description, EXCLUDED.description),
+			updated_at = CURRENT_TIMESTAMP
+	`, t.ID, locale, t.Name, nullIfEmpty(t.Description))
+	return err
+}
+
+const translationMemoryColumns = `id, source_text, source_lang, target_lang, model, translated_text, is_override,
+	hit_count, last_used_at, updated_by, created_at, updated_at`
+
+func scanTranslationMemoryEntry(row scanner) (*model.TranslationMemoryEntry, error) {
+	entry := &model.TranslationMemoryEntry{}
+	err := row.Scan(&entry.ID, &entry.SourceText, &entry.SourceLang, &entry.TargetLang, &entry.Model,
+		&entry.TranslatedText, &entry.IsOverride, &entry.HitCount, &entry.LastUsedAt, &entry.UpdatedBy,
+		&entry.CreatedAt, &entry.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+type postgresTranslationMemory struct {
+	db *sql.DB
+}
+
+func (r *postgresTranslationMemory) Lookup(ctx context.Context, sourceHash, sourceLang, targetLang, model string) (string, error) {
+	var translated string
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE translation_memory SET hit_count = hit_count + 1, last_used_at = CURRENT_TIMESTAMP
+		WHERE id = (
+			SELECT id FROM translation_memory
+			WHERE source_hash = $1 AND source_lang = $2 AND target_lang = $3 AND (is_override OR model = $4)
+			ORDER BY is_override DESC
+			LIMIT 1
+		)
+		RETURNING translated_text
+	`, sourceHash, sourceLang, targetLang, model).Scan(&translated)
+	if err == sql.ErrNoRows {
+		return "", ErrTranslationNotFound
+	}
+	return translated, err
+}
+
+func (r *postgresTranslationMemory) Remember(ctx context.Context, entry NewTranslationMemoryEntry) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO translation_memory (source_hash, source_text, source_lang, target_lang, model, translated_text, last_used_at)
+		VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)
+		ON CONFLICT (source_hash, source_lang, target_lang, model) DO UPDATE SET
+			translated_text = EXCLUDED.translated_text, updated_at = CURRENT_TIMESTAMP
+	`, entry.SourceHash, entry.SourceText, entry.SourceLang, entry.TargetLang, entry.Model, entry.TranslatedText)
+	return err
+}
+
+func (r *postgresTranslationMemory) SetOverride(ctx context.Context, entry NewTranslationMemoryEntry, userID int) (*model.TranslationMemoryEntry, error) {
+	// Overrides are stored without a model so they apply to every model
+	return scanTranslationMemoryEntry(r.db.QueryRowContext(ctx, `
+		INSERT INTO translation_memory (source_hash, source_text, source_lang, target_lang, model, translated_text, is_override, updated_by)
+		VALUES ($1, $2, $3, $4, '', $5, true, $6)
+		ON CONFLICT (source_hash, source_lang, target_lang, model) DO UPDATE SET
+			translated_text = EXCLUDED.translated_text, is_override = true,
+			updated_by = EXCLUDED.updated_by, updated_at = CURRENT_TIMESTAMP
+		RETURNING `+translationMemoryColumns,
+		entry.SourceHash, entry.SourceText, entry.SourceLang, entry.TargetLang, entry.TranslatedText, userID,
+	))
+}
+
+func (r *postgresTranslationMemory) Delete(ctx context.Context, id int) (bool, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM translation_memory WHERE id = $1", id)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	return rowsAffected == 1, err
+}
+
+func (r *postgresTranslationMemory) List(ctx context.Context, filter TranslationMemoryFilter) ([]*model.TranslationMemoryEntry, error) {
+	query := "SELECT " + translationMemoryColumns + " FROM translation_memory WHERE 1=1"
+	var args []interface{}
+
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		query += fmt.Sprintf(" AND (source_text ILIKE $%d OR translated_text ILIKE $%d)", len(args), len(args))
+	}
+	if filter.SourceLang != "" {
+		args = append(args, filter.SourceLang)
+		query += fmt.Sprintf(" AND source_lang = $%d", len(args))
+	}
+	if filter.TargetLang != "" {
+		args = append(args, filter.TargetLang)
+		query += fmt.Sprintf(" AND target_lang = $%d", len(args))
+	}
+	if filter.OverridesOnly {
+		query += " AND is_override"
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY is_override DESC, hit_count DESC, id DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*model.TranslationMemoryEntry{}
+	for rows.Next() {
+		entry, err := scanTranslationMemoryEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
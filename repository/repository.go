@@ -0,0 +1,620 @@
+// Package repository provides data access for the store. Each entity has a
+// repository interface with a Postgres implementation for the server and an
+// in-memory implementation for tests and local development.
+package repository
+
+import (
+	"ai-catalog/model"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Errors returned by the repositories
+var (
+	ErrUserNotFound      = errors.New("user not found")
+	ErrEmailTaken        = errors.New("an account with this email already exists")
+	ErrCategoryNotFound  = errors.New("category not found")
+	ErrCategoryCycle     = errors.New("a category cannot be moved under itself or one of its subcategories")
+	ErrCategoryNotEmpty  = errors.New("category has subcategories or products, move them first")
+	ErrProductNotFound   = errors.New("product not found")
+	ErrProductOrdered    = errors.New("product has been ordered and cannot be deleted, archive it instead")
+	ErrSKUTaken          = errors.New("SKU is already used by another product")
+	ErrVariantNotFound   = errors.New("product variant not found")
+	ErrVariantRequired   = errors.New("choose a variant of this product")
+	ErrVariantExists     = errors.New("the product already has a variant with these options")
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrCartItemNotFound  = errors.New("cart item not found")
+	ErrCartEmpty         = errors.New("cart is empty")
+	ErrCartUnavailable   = errors.New("the cart has items that are no longer available, remove them to place the order")
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrReviewNotFound    = errors.New("review not found")
+
+	ErrIdentityNotFound    = errors.New("identity not found")
+	ErrEmailNotVerified    = errors.New("an account with this email already exists and its email address is not verified")
+	ErrTranslationNotFound = errors.New("translation not found")
+
+	ErrSessionNotFound   = errors.New("session not found")
+	ErrAPIKeyNotFound    = errors.New("API key not found")
+	ErrUserTokenNotFound = errors.New("token not found")
+	ErrJobNotFound       = errors.New("job not found")
+	ErrJobNotDead        = errors.New("job not found or not dead-lettered")
+	ErrJobAbandoned      = errors.New("job was abandoned by its worker on every attempt")
+)
+
+// Repositories groups the repositories the resolvers use
+type Repositories struct {
+	Users      UserRepository
+	Categories CategoryRepository
+	Products   ProductRepository
+	Variants   VariantRepository
+	Carts      CartRepository
+	Wishlists  WishlistRepository
+	Orders     OrderRepository
+	Reviews    ReviewRepository
+	Searches   SearchRepository
+
+	Identities        IdentityRepository
+	LoginAttempts     LoginAttemptRepository
+	TwoFactor         TwoFactorRepository
+	Translations      TranslationRepository
+	TranslationMemory TranslationMemoryRepository
+
+	Sessions   SessionRepository
+	APIKeys    APIKeyRepository
+	UserTokens UserTokenRepository
+	Jobs       JobRepository
+}
+
+// NewUser holds the fields of an account being registered
+type NewUser struct {
+	Email        string
+	PasswordHash string
+	FirstName    string
+	LastName     string
+	Phone        string
+	Address      string
+	City         string
+	// EmailVerified is set for accounts created from an identity whose
+	// provider verified the email address
+	EmailVerified bool
+}
+
+// ProfileUpdate holds the profile fields a user can change. Nil fields keep
+// their current value.
+type ProfileUpdate struct {
+	FirstName string
+	LastName  string
+	Phone     *string
+	Address   *string
+	City      *string
+}
+
+// UserRepository stores user accounts
+type UserRepository interface {
+	GetByID(ctx context.Context, id int) (*model.User, error)
+	GetByEmail(ctx context.Context, email string) (*model.User, error)
+	Create(ctx context.Context, user NewUser) (*model.User, error)
+	// PasswordHash returns the password hash of an account
+	PasswordHash(ctx context.Context, id int) (string, error)
+	UpdatePassword(ctx context.Context, id int, passwordHash string) error
+	MarkEmailVerified(ctx context.Context, id int) error
+	UpdateProfile(ctx context.Context, id int, update ProfileUpdate) (*model.User, error)
+	SetRole(ctx context.Context, id int, role string) error
+	SetStatus(ctx context.Context, id int, status string) error
+	SetTwoFactorRequired(ctx context.Context, id int, required bool) error
+}
+
+// NewCategory holds the fields of a category being created. A nil ParentID
+// creates a root category.
+type NewCategory struct {
+	Name        string
+	Description string
+	ImageURL    string
+	ParentID    *int
+}
+
+// CategoryRepository stores product categories. Categories form a tree through
+// their parent IDs.
+type CategoryRepository interface {
+	GetByID(ctx context.Context, id int) (*model.Category, error)
+	// List returns every category ordered by name
+	List(ctx context.Context) ([]*model.Category, error)
+	// Search returns the categories whose name contains text, in the default
+	// locale or any translation, ignoring Arabic spelling variants
+	Search(ctx context.Context, text string) ([]*model.Category, error)
+	// Children returns the subcategories of parentID ordered by name, or the
+	// root categories when parentID is nil
+	Children(ctx context.Context, parentID *int) ([]*model.Category, error)
+	// Ancestors returns the categories above a category, from the root down to
+	// its parent
+	Ancestors(ctx context.Context, id int) ([]*model.Category, error)
+	Create(ctx context.Context, category NewCategory) (*model.Category, error)
+	// Move gives a category a new parent, or makes it a root when parentID is
+	// nil. Moving a category under itself or a descendant returns ErrCategoryCycle.
+	Move(ctx context.Context, id int, parentID *int) (*model.Category, error)
+	// Delete deletes a category, returning ErrCategoryNotEmpty when it still
+	// has subcategories or products
+	Delete(ctx context.Context, id int) error
+	// Suggest returns up to limit categories whose name, in the default locale
+	// or any translation, starts with or resembles prefix, best match first
+	Suggest(ctx context.Context, prefix string, limit int) ([]*model.Category, error)
+}
+
+// ProductFilter selects products. Zero values do not filter.
+type ProductFilter struct {
+	CategoryID *int
+	// IncludeDescendants extends CategoryID to the products of its subcategories
+	IncludeDescendants bool
+	// Search is full-text search over the name, SKU, short description and
+	// description, in the default locale or any translation. Arabic spelling
+	// variants match, and List orders the matches by relevance first.
+	Search string
+	// MinPrice and MaxPrice match products sold at a price in the range. The
+	// prices of a product sold in variants are those of its active variants.
+	MinPrice   *float64
+	MaxPrice   *float64
+	IsFeatured *bool
+	// MinRating selects products with an average review rating of at least
+	// this many stars; products without reviews rate 0
+	MinRating *int
+	// InStock selects products in stock when true and out of stock when false
+	InStock *bool
+	// ActiveOnly leaves out archived products
+	ActiveOnly bool
+	// FeaturedFirst orders featured products before the others of equal
+	// relevance; products are otherwise ordered newest first
+	FeaturedFirst bool
+	Limit         int
+	// Offset skips that many products, for listings numbered by page
+	Offset int
+}
+
+// ProductFields holds product column values keyed by column name, such as
+// "name", "price" or "category_id". Only the columns present are written.
+type ProductFields map[string]interface{}
+
+// ProductRepository stores the catalog products
+type ProductRepository interface {
+	// GetByID returns a product, including archived products
+	GetByID(ctx context.Context, id int) (*model.Product, error)
+	List(ctx context.Context, filter ProductFilter) ([]*model.Product, error)
+	// ListPage returns a page of the products matching filter in the given
+	// order, ignoring the filter's ordering, Limit and Offset
+	ListPage(ctx context.Context, filter ProductFilter, sort ProductSort, page Page) (*model.ProductConnection, error)
+	// Count returns the number of products matching filter, ignoring its Limit and Offset
+	Count(ctx context.Context, filter ProductFilter) (int, error)
+	// Suggest returns up to limit active products whose name, in the default
+	// locale or any translation, starts with or resembles prefix, best match first
+	Suggest(ctx context.Context, prefix string, limit int) ([]*model.Product, error)
+	// Facets counts the products matching filter in total and by category,
+	// price range, rating and stock, ignoring its Limit and Offset. Each facet
+	// is counted with every filter but its own. Categories are ordered by
+	// count, largest first, without their Category.
+	Facets(ctx context.Context, filter ProductFilter) (*model.ProductFacets, error)
+	// SKUTaken reports whether a product other than excludeID, or any variant,
+	// uses sku
+	SKUTaken(ctx context.Context, sku string, excludeID int) (bool, error)
+	Create(ctx context.Context, fields ProductFields) (*model.Product, error)
+	Update(ctx context.Context, id int, fields ProductFields) (*model.Product, error)
+	SetActive(ctx context.Context, id int, active bool) (*model.Product, error)
+	// Delete permanently deletes a product that has never been ordered
+	Delete(ctx context.Context, id int) error
+	// SetLiked records or removes a user's like of an active product
+	SetLiked(ctx context.Context, userID, productID int, liked bool) error
+}
+
+// NewVariant holds the fields of a product variant being created. Options lists
+// its value of each of the product's option types, in order.
+type NewVariant struct {
+	ProductID     int
+	SKU           string
+	Price         float64
+	StockQuantity int
+	Options       []*model.SelectedOption
+	Images        []string
+}
+
+// VariantUpdate holds the variant fields being changed. Nil fields keep their
+// current value. A variant's options cannot change.
+type VariantUpdate struct {
+	SKU           *string
+	Price         *float64
+	StockQuantity *int
+	Images        []string
+	IsActive      *bool
+}
+
+// VariantRepository stores the variants products are sold in. The stock of a
+// product with variants is kept at the total stock of its active variants.
+type VariantRepository interface {
+	GetByID(ctx context.Context, id int) (*model.ProductVariant, error)
+	// ListForProduct returns a product's variants, including inactive ones, in
+	// the order they were created
+	ListForProduct(ctx context.Context, productID int) ([]*model.ProductVariant, error)
+	// SKUTaken reports whether a variant other than excludeID, or any product,
+	// uses sku
+	SKUTaken(ctx context.Context, sku string, excludeID int) (bool, error)
+	// Create returns ErrVariantExists when the product already has a variant
+	// with the same options
+	Create(ctx context.Context, variant NewVariant) (*model.ProductVariant, error)
+	Update(ctx context.Context, id int, update VariantUpdate) (*model.ProductVariant, error)
+	// Delete deletes a variant and removes it from carts. Orders keep its name.
+	Delete(ctx context.Context, id int) error
+}
+
+// CartRepository stores the users' shopping carts
+type CartRepository interface {
+	// List returns the items in a user's cart with their products and variants.
+	// Items whose product or variant has been deactivated are marked unavailable.
+	List(ctx context.Context, userID int) ([]*model.CartItem, error)
+	// Add adds quantity of an active product to the cart, on top of any already
+	// there. Products sold in variants need an active variantID, and other
+	// products take none; ErrVariantRequired and ErrVariantNotFound are
+	// returned otherwise. Stock is checked against the variant.
+	Add(ctx context.Context, userID, productID int, variantID *int, quantity int) (*model.CartItem, error)
+	// SetQuantity replaces the quantity of an item in the user's cart. Like Add,
+	// it returns ErrProductNotFound or ErrVariantNotFound once the product or
+	// variant has been deactivated.
+	SetQuantity(ctx context.Context, userID, id, quantity int) (*model.CartItem, error)
+	// Remove deletes an item from the user's cart and reports whether it was there
+	Remove(ctx context.Context, userID, id int) (bool, error)
+	Clear(ctx context.Context, userID int) error
+}
+
+// WishlistRepository stores the users' wishlists
+type WishlistRepository interface {
+	// List returns the items in a user's wishlist with their products, newest first
+	List(ctx context.Context, userID int) ([]*model.WishlistItem, error)
+	// ListPage returns a page of a user's wishlist, newest first
+	ListPage(ctx context.Context, userID int, page Page) (*model.WishlistConnection, error)
+	// Add adds an active product to the wishlist. Adding it twice returns the existing item.
+	Add(ctx context.Context, userID, productID int) (*model.WishlistItem, error)
+	// Remove deletes a product from the wishlist and reports whether it was there
+	Remove(ctx context.Context, userID, productID int) (bool, error)
+}
+
+// NewOrder holds the checkout details of an order placed from a cart
+type NewOrder struct {
+	UserID          int
+	OrderNumber     string
+	ShippingAddress string
+	ShippingCity    string
+	ShippingPhone   string
+	PaymentMethod   string
+	Notes           *string
+}
+
+// OrderRepository stores orders
+type OrderRepository interface {
+	GetByID(ctx context.Context, id int) (*model.Order, error)
+	// ListForUser returns a user's orders, newest first, without their items
+	ListForUser(ctx context.Context, userID int) ([]*model.Order, error)
+	// ListPageForUser returns a page of a user's orders, newest first, without their items
+	ListPageForUser(ctx context.Context, userID int, page Page) (*model.OrderConnection, error)
+	Items(ctx context.Context, orderID int) ([]*model.OrderItem, error)
+	// CreateFromCart places an order for everything in the user's cart at the
+	// current product or variant prices, reduces the stock of the ordered
+	// products or variants and empties the cart. The stock is locked while the
+	// order is placed; ErrCartUnavailable is returned when an item is no longer
+	// active and ErrInsufficientStock when there is not enough of one.
+	CreateFromCart(ctx context.Context, order NewOrder) (*model.Order, error)
+	UpdateStatus(ctx context.Context, id int, status string) (*model.Order, error)
+}
+
+// ReviewFilter selects reviews. Zero values do not filter.
+type ReviewFilter struct {
+	ProductID int
+	UserID    int
+}
+
+// NewReview holds the fields of a review being written
+type NewReview struct {
+	UserID    int
+	ProductID int
+	Rating    int
+	Title     string
+	Comment   string
+}
+
+// ReviewRepository stores product reviews. viewerID is the user whose likes set
+// Review.IsLiked, or 0 for anonymous requests.
+type ReviewRepository interface {
+	GetByID(ctx context.Context, viewerID, id int) (*model.Review, error)
+	// List returns the matching reviews with their authors, newest first
+	List(ctx context.Context, viewerID int, filter ReviewFilter) ([]*model.Review, error)
+	// ListPage returns a page of the matching reviews with their authors, newest first
+	ListPage(ctx context.Context, viewerID int, filter ReviewFilter, page Page) (*model.ReviewConnection, error)
+	// Create stores a review, marking it as a verified purchase when the user
+	// has a delivered order containing the product
+	Create(ctx context.Context, review NewReview) (*model.Review, error)
+	Update(ctx context.Context, id, rating int, title, comment string) error
+	Delete(ctx context.Context, id int) error
+	// SetLiked records or removes a user's like of a review
+	SetLiked(ctx context.Context, userID, reviewID int, liked bool) error
+}
+
+// NewSearch is a search a customer made, for the search log
+type NewSearch struct {
+	Query   string
+	Results int
+	// UserID is nil for anonymous searches
+	UserID *int
+	// ClientKey tells anonymous searchers apart, such as a hash of their IP
+	// address, so their searches are not counted as those of many customers
+	ClientKey string
+	Locale    string
+}
+
+// SearchRepository logs the searches customers make. Queries are grouped by
+// their normalized form, ignoring case and Arabic spelling variants.
+type SearchRepository interface {
+	Record(ctx context.Context, search NewSearch) error
+	// Suggest returns up to limit past queries that start with or resemble
+	// prefix and found products, searched by at least minSearchers different
+	// users or anonymous clients since the given time, best match first and
+	// then most frequent first. Anonymous searches without a ClientKey are not
+	// counted as searchers.
+	Suggest(ctx context.Context, prefix string, since time.Time, minSearchers, limit int) ([]string, error)
+	// Popular returns the most frequent queries since the given time. With
+	// zeroResults it returns only the queries that found no products.
+	Popular(ctx context.Context, since time.Time, zeroResults bool, limit int) ([]*model.SearchStat, error)
+}
+
+// NewIdentity is an external identity being linked to an account
+type NewIdentity struct {
+	Provider string
+	Subject  string
+	Email    string
+}
+
+// IdentityRepository stores the OIDC identities users sign in with
+type IdentityRepository interface {
+	// Login records a login with an identity, updating its email address, and
+	// returns the ID of the user it is linked to, or ErrIdentityNotFound
+	Login(ctx context.Context, provider, subject, email string) (int, error)
+	// Link links an identity to the account with the same email address, or to
+	// a new account created from account when there is none, and returns the
+	// user ID. Accounts that have not verified their email address are not
+	// linked and ErrEmailNotVerified is returned.
+	Link(ctx context.Context, identity NewIdentity, account NewUser) (int, error)
+	// ListForUser returns the identities linked to a user, oldest first
+	ListForUser(ctx context.Context, userID int) ([]*model.UserIdentity, error)
+	// Unlink removes a provider's identity from a user and reports whether one
+	// was linked
+	Unlink(ctx context.Context, userID int, provider string) (bool, error)
+}
+
+// NewLoginAttempt is the outcome of a login attempt
+type NewLoginAttempt struct {
+	Email     string
+	IPAddress string
+	UserAgent string
+	// UserID is nil when no account was identified
+	UserID  *int
+	Success bool
+	Reason  string
+}
+
+// LoginAttemptFilter selects login attempts. Zero values do not filter.
+type LoginAttemptFilter struct {
+	Email     string
+	IPAddress string
+	Success   *bool
+	Limit     int
+}
+
+// LoginAttemptRepository stores login attempts for throttling and auditing
+type LoginAttemptRepository interface {
+	Record(ctx context.Context, attempt NewLoginAttempt) error
+	// AccountFailures returns the ages of the failed attempts for email with one
+	// of reasons within window, newest first. Failures before the email's last
+	// successful login are left out.
+	AccountFailures(ctx context.Context, email string, reasons []string, window time.Duration) ([]time.Duration, error)
+	// IPFailures returns the ages of the failed attempts from ipAddress with one
+	// of reasons within window, newest first
+	IPFailures(ctx context.Context, ipAddress string, reasons []string, window time.Duration) ([]time.Duration, error)
+	// List returns the matching attempts, newest first
+	List(ctx context.Context, filter LoginAttemptFilter) ([]*model.LoginAttempt, error)
+}
+
+// TwoFactorRepository stores the TOTP secrets and recovery codes of two-factor
+// authentication. Recovery codes are stored as hashes.
+type TwoFactorRepository interface {
+	// Secret returns a user's TOTP secret, empty when there is none, and whether
+	// two-factor authentication is enabled
+	Secret(ctx context.Context, userID int) (string, bool, error)
+	// SetPendingSecret stores the secret of a user who is enrolling, unless
+	// two-factor authentication is already enabled
+	SetPendingSecret(ctx context.Context, userID int, secret string) error
+	// Enable turns two-factor authentication on with the pending secret.
+	// step is the time step of the code that confirmed it.
+	Enable(ctx context.Context, userID int, step int64) error
+	// Disable turns two-factor authentication off and removes the secret and
+	// recovery codes
+	Disable(ctx context.Context, userID int) error
+	// UseStep records the time step of an accepted code. It reports false when
+	// the step is not newer than the last one used, so codes cannot be replayed.
+	UseStep(ctx context.Context, userID int, step int64) (bool, error)
+	// ReplaceRecoveryCodes discards a user's recovery codes and stores new ones
+	ReplaceRecoveryCodes(ctx context.Context, userID int, codeHashes []string) error
+	// UseRecoveryCode marks an unused recovery code as used and reports whether
+	// there was one
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+	// UseChallenge records the ID of a two-factor challenge token that completed
+	// a login and reports false when it was already used. IDs only need to be
+	// kept until expiresAt, after which the token is rejected anyway.
+	UseChallenge(ctx context.Context, id string, expiresAt time.Time) (bool, error)
+}
+
+// Translation is the text of a product or category in a locale other than the
+// default. Empty descriptions fall back to the default locale text.
+type Translation struct {
+	// ID is the product or category ID
+	ID               int
+	Name             string
+	Description      string
+	ShortDescription string
+}
+
+// TranslationRepository stores the translations of catalog text. Categories
+// have no short description.
+type TranslationRepository interface {
+	// Products returns the translations into locale of the given products, by product ID
+	Products(ctx context.Context, locale string, ids []int) (map[int]*Translation, error)
+	// Categories returns the translations into locale of the given categories, by category ID
+	Categories(ctx context.Context, locale string, ids []int) (map[int]*Translation, error)
+	// SetProduct creates or replaces the translation of a product
+	SetProduct(ctx context.Context, locale string, translation Translation) error
+	// SetCategory creates or replaces the translation of a category
+	SetCategory(ctx context.Context, locale string, translation Translation) error
+	// UntranslatedProducts returns the default locale text of the products with
+	// untranslated fields, ordered by ID. Fields that are already translated are
+	// left empty.
+	UntranslatedProducts(ctx context.Context, locale string) ([]*Translation, error)
+	// UntranslatedCategories is UntranslatedProducts for categories
+	UntranslatedCategories(ctx context.Context, locale string) ([]*Translation, error)
+	// FillProduct adds the translated fields of translation to a product's
+	// translation, keeping the fields that are already translated
+	FillProduct(ctx context.Context, locale string, translation Translation) error
+	// FillCategory is FillProduct for categories
+	FillCategory(ctx context.Context, locale string, translation Translation) error
+}
+
+// NewTranslationMemoryEntry is a translation being remembered. SourceHash keys
+// the source text.
+type NewTranslationMemoryEntry struct {
+	SourceHash     string
+	SourceText     string
+	SourceLang     string
+	TargetLang     string
+	Model          string
+	TranslatedText string
+}
+
+// TranslationMemoryFilter selects translation memory entries. Zero values do
+// not filter.
+type TranslationMemoryFilter struct {
+	// Search matches the source or translated text
+	Search        string
+	SourceLang    string
+	TargetLang    string
+	OverridesOnly bool
+	Limit         int
+}
+
+// TranslationMemoryRepository caches machine translations and stores the
+// overrides linguists make
+type TranslationMemoryRepository interface {
+	// Lookup returns the override for a text, or else the translation made by
+	// model, and counts the hit. It returns ErrTranslationNotFound when there is
+	// neither.
+	Lookup(ctx context.Context, sourceHash, sourceLang, targetLang, model string) (string, error)
+	// Remember stores a machine translation, replacing the model's earlier one
+	Remember(ctx context.Context, entry NewTranslationMemoryEntry) error
+	// SetOverride stores a linguist's translation, which applies to every model.
+	// The entry's Model is ignored.
+	SetOverride(ctx context.Context, entry NewTranslationMemoryEntry, userID int) (*model.TranslationMemoryEntry, error)
+	// Delete removes an entry and reports whether it existed
+	Delete(ctx context.Context, id int) (bool, error)
+	// List returns the matching entries, overrides and most used entries first
+	List(ctx context.Context, filter TranslationMemoryFilter) ([]*model.TranslationMemoryEntry, error)
+}
+
+// NewSession holds the details of a login session being started. TTL is how
+// long its refresh token stays valid.
+type NewSession struct {
+	ID               string
+	UserID           int
+	RefreshTokenHash string
+	UserAgent        string
+	IPAddress        string
+	TTL              time.Duration
+}
+
+// SessionRepository stores the server-side sessions backing refresh tokens.
+// Refresh tokens are stored as hashes.
+type SessionRepository interface {
+	Create(ctx context.Context, session NewSession) (*model.Session, error)
+	// Rotate replaces the refresh token of the active session holding tokenHash
+	// with newTokenHash and extends the session by ttl. It returns
+	// ErrSessionNotFound for unknown, expired and revoked tokens. A token that
+	// was already rotated away revokes its session, since it means the token
+	// was copied.
+	Rotate(ctx context.Context, tokenHash, newTokenHash string, ttl time.Duration) (*model.Session, error)
+	Revoke(ctx context.Context, id string) error
+	// RevokeAll revokes every active session of a user
+	RevokeAll(ctx context.Context, userID int) error
+	// RevokeOthers revokes every active session of a user except keepID
+	RevokeOthers(ctx context.Context, userID int, keepID string) error
+	// IsActive reports whether a session exists and is neither revoked nor expired
+	IsActive(ctx context.Context, id string) (bool, error)
+}
+
+// NewAPIKey holds the fields of an API key being issued. Only the hash of the
+// key is stored, with a short prefix to tell keys apart.
+type NewAPIKey struct {
+	UserID    int
+	Name      string
+	Prefix    string
+	KeyHash   string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+// APIKeyRepository stores the users' API keys
+type APIKeyRepository interface {
+	Create(ctx context.Context, key NewAPIKey) (*model.APIKey, error)
+	// Authenticate returns the active key with keyHash, or ErrAPIKeyNotFound,
+	// and records its use. LastUsedAt is written at most once per touchInterval.
+	Authenticate(ctx context.Context, keyHash string, touchInterval time.Duration) (*model.APIKey, error)
+	// ListForUser returns a user's keys, newest first, including revoked ones
+	ListForUser(ctx context.Context, userID int) ([]*model.APIKey, error)
+	// Revoke revokes an active key and reports whether there was one. A userID
+	// of 0 revokes the key whoever owns it.
+	Revoke(ctx context.Context, id, userID int) (bool, error)
+}
+
+// UserTokenRepository stores the hashes of single-use tokens, such as password
+// reset links, by purpose
+type UserTokenRepository interface {
+	// Create stores a token valid for ttl, invalidating the user's earlier
+	// unused tokens with the same purpose
+	Create(ctx context.Context, userID int, purpose, tokenHash string, ttl time.Duration) error
+	// Consume marks an unused, unexpired token as used and returns the user it
+	// was issued to, or ErrUserTokenNotFound
+	Consume(ctx context.Context, tokenHash, purpose string) (int, error)
+}
+
+// NewJob holds the fields of a job being queued. CreatedBy is nil for jobs
+// started by the system.
+type NewJob struct {
+	Type        string
+	Payload     json.RawMessage
+	MaxAttempts int
+	CreatedBy   *int
+}
+
+// JobRepository stores the background job queue
+type JobRepository interface {
+	// Enqueue stores a pending job due now
+	Enqueue(ctx context.Context, job NewJob) (*model.Job, error)
+	GetByID(ctx context.Context, id int) (*model.Job, error)
+	// Claim marks the next due pending job of one of types as running, counts
+	// the attempt and returns it, or returns nil when no job is due. Running
+	// jobs claimed more than staleAfter ago are assumed abandoned by a crashed
+	// worker: they are claimed again while they have attempts left and
+	// dead-lettered with ErrJobAbandoned once they do not. Concurrent claims,
+	// in this or other processes, never return the same job.
+	Claim(ctx context.Context, types []string, staleAfter time.Duration) (*model.Job, error)
+	// Complete stores the result of a job that succeeded
+	Complete(ctx context.Context, id int, result json.RawMessage) error
+	// Reschedule records a failed attempt and queues the job again after delay
+	Reschedule(ctx context.Context, id int, jobErr string, delay time.Duration) error
+	// DeadLetter records a failed attempt and stops retrying the job
+	DeadLetter(ctx context.Context, id int, jobErr string) error
+	// Requeue moves a dead-lettered job back to the queue with a fresh set of
+	// attempts, or returns ErrJobNotDead
+	Requeue(ctx context.Context, id int) (*model.Job, error)
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"ai-catalog/model"
+	"strings"
+	"unicode"
+)
+
+// arabicVariants maps Arabic letters to the form they are searched as, matching
+// the normalize_arabic SQL function: alef with hamza or madda becomes a bare
+// alef, hamza on waw or yaa becomes the bare letter, taa marbuta becomes haa and
+// alef maksura becomes yaa
+var arabicVariants = map[rune]rune{
+	'أ': 'ا', 'إ': 'ا', 'آ': 'ا', 'ٱ': 'ا',
+	'ؤ': 'و', 'ئ': 'ي',
+	'ة': 'ه', 'ى': 'ي',
+}
+
+// normalizeArabic returns text with Arabic spelling variants unified and the
+// diacritics and tatweel removed, so "مُكَيِّفات" and "مكيفات" compare equal
+func normalizeArabic(text string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= '\u064B' && r <= '\u0652') || r == '\u0670' || r == '\u0640' {
+			return -1
+		}
+		if normalized, ok := arabicVariants[r]; ok {
+			return normalized
+		}
+		return r
+	}, text)
+}
+
+// searchWords splits search text into normalized lower-case words
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(normalizeArabic(text)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// searchRank scores how well product matches search text for the in-memory
+// store, weighing fields like the Postgres search_vector. Every word must occur
+// in the name, SKU, short description or description; it returns 0 otherwise.
+func searchRank(product *model.Product, text string) int {
+	fields := []struct {
+		text   string
+		weight int
+	}{
+		{product.Name, 4},
+		{product.SKU, 4},
+		{product.ShortDescription, 2},
+		{product.Description, 1},
+	}
+
+	rank := 0
+	for _, word := range searchWords(text) {
+		wordRank := 0
+		for _, field := range fields {
+			if strings.Contains(strings.ToLower(normalizeArabic(field.text)), word) {
+				wordRank += field.weight
+			}
+		}
+		if wordRank == 0 {
+			return 0
+		}
+		rank += wordRank
+	}
+	return rank
+}
+
+// normalizeQuery returns search text in the form searches are grouped and
+// matched by: lower case, single spaced and with Arabic spelling variants unified
+func normalizeQuery(text string) string {
+	return strings.ToLower(normalizeArabic(strings.Join(strings.Fields(text), " ")))
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// suggestionRank scores how well text completes the normalized prefix for the
+// in-memory store: 2 when text starts with it, 1 when one of its words does and
+// less for a word that starts with the prefix mistyped by one letter. ok is
+// false when text does not match.
+func suggestionRank(text, prefix string) (rank float64, ok bool) {
+	text = normalizeQuery(text)
+	if strings.HasPrefix(text, prefix) {
+		return 2, true
+	}
+	words := strings.Fields(text)
+	for _, word := range words {
+		if strings.HasPrefix(word, prefix) {
+			return 1, true
+		}
+	}
+	// Short prefixes are too ambiguous to correct
+	typed := []rune(prefix)
+	if len(typed) < 4 {
+		return 0, false
+	}
+	// A dropped or extra letter changes how much of the word was typed
+	for _, word := range words {
+		letters := []rune(word)
+		for n := len(typed) - 1; n <= len(typed)+1 && n <= len(letters); n++ {
+			if editDistance(letters[:n], typed) <= 1 {
+				return 0.5, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			substitution := previous[j-1]
+			if a[i-1] != b[j-1] {
+				substitution++
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, substitution)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
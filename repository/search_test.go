@@ -0,0 +1,110 @@
+package repository
+
+import "testing"
+
+func TestNormalizeArabic(t *testing.T) {
+	tests := []struct {
+		name, text, want string
+	}{
+		{"alef with hamza above", "أحمر", "احمر"},
+		{"alef with hamza below", "إبريق", "ابريق"},
+		{"alef with madda", "آلة", "اله"},
+		{"alef wasla", "ٱلبيت", "البيت"},
+		{"hamza on waw", "مؤشر", "موشر"},
+		{"hamza on yaa", "بيئة", "بييه"},
+		{"taa marbuta", "ساعة", "ساعه"},
+		{"alef maksura", "مستشفى", "مستشفي"},
+		{"diacritics", "مُكَيِّفات", "مكيفات"},
+		{"tanween and sukun", "كتابًا مُسْتَعمَلٌ", "كتابا مستعمل"},
+		{"superscript alef", "هٰذا", "هذا"},
+		{"tatweel", "ســـاعة", "ساعه"},
+		{"mixed with latin", "iPhone أصلي ١٢", "iPhone اصلي ١٢"},
+		{"latin untouched", "Café", "Café"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeArabic(tt.text); got != tt.want {
+				t.Errorf("normalizeArabic(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchWords(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Smart  Phone", []string{"smart", "phone"}},
+		{"مُكَيِّف، هواء!", []string{"مكيف", "هواء"}},
+		{"iPhone-15 برو", []string{"iphone", "15", "برو"}},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		got := searchWords(tt.text)
+		if len(got) != len(tt.want) {
+			t.Errorf("searchWords(%q) = %q, want %q", tt.text, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("searchWords(%q) = %q, want %q", tt.text, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"phone", "phnoe", 2},
+		// Distances count letters, not the bytes of their UTF-8 encoding
+		{"مكيف", "مكيف", 0},
+		{"مكيف", "مكيفات", 2},
+		{"هاتف", "هاتق", 1},
+		{"سماعة", "سماعه", 1},
+		{"café", "cafe", 1},
+		{"日本語", "日本", 1},
+	}
+	for _, tt := range tests {
+		got := editDistance([]rune(tt.a), []rune(tt.b))
+		if got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if reverse := editDistance([]rune(tt.b), []rune(tt.a)); reverse != got {
+			t.Errorf("editDistance(%q, %q) = %d, but %d the other way round", tt.b, tt.a, reverse, got)
+		}
+	}
+}
+
+func TestSuggestionRankCorrectsOneMistypedLetter(t *testing.T) {
+	tests := []struct {
+		text, prefix string
+		want         float64
+		ok           bool
+	}{
+		{"Smart phone", "smart", 2, true},
+		{"Smart phone", "pho", 1, true},
+		{"Smart phone", "phnoe", 0, false},
+		{"Smart phone", "phome", 0.5, true},
+		{"Smart phone", "phne", 0.5, true},
+		{"مكيف سبليت", "مكيق", 0.5, true},
+		// Prefixes shorter than four letters are not corrected
+		{"Smart phone", "phx", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := suggestionRank(tt.text, normalizeQuery(tt.prefix))
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("suggestionRank(%q, %q) = %v, %v, want %v, %v", tt.text, tt.prefix, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"ai-catalog/model"
+	"strings"
+)
+
+// variantName names a variant by its option values, such as "42 / Red", for
+// order items
+func variantName(options []*model.SelectedOption) string {
+	values := make([]string, len(options))
+	for i, option := range options {
+		values[i] = option.Value
+	}
+	return strings.Join(values, " / ")
+}
+
+// sameOptions reports whether two variants have the same option values
+func sameOptions(a, b []*model.SelectedOption) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if *a[i] != *b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// markAvailable sets whether a cart item can still be ordered: its product and
+// variant, when it has one, must be active
+func markAvailable(item *model.CartItem) {
+	item.Available = item.Product.IsActive && (item.Variant == nil || item.Variant.IsActive)
+}